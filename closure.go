@@ -0,0 +1,221 @@
+package pkgdmp
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// maxClosureDepth is the maximum number of references followed from the
+// symbol of a [Package.Closure].
+const maxClosureDepth = 32
+
+// Closure returns a copy of the package with only the function or type named
+// name and the package-local types it transitively refers to in its
+// signature, fields, and methods.
+//
+// Methods of included types are included, as well as functions declared with
+// an included type as receiver. Consts and vars are not included. References are
+// followed at most 32 levels deep.
+//
+// Closure returns false if the package has no function or type named name.
+func (p *Package) Closure(name string) (*Package, bool) {
+	types := make(map[string]int, len(p.Types))
+
+	for i, td := range p.Types {
+		types[td.Name] = i
+	}
+
+	recvFuncs := make(map[string][]Func)
+
+	for _, f := range p.Funcs {
+		if rt := f.ReceiverType(); rt != "" {
+			recvFuncs[rt] = append(recvFuncs[rt], f)
+		}
+	}
+
+	var (
+		start    *Func
+		level    []string
+		included = make(map[string]struct{})
+	)
+
+	// include adds the package-local types referred to by type expressions
+	// typs to the closure and returns the ones not already included.
+	include := func(typs []string) []string {
+		var res []string
+
+		for _, typ := range typs {
+			for _, ref := range TypeRefs(typ) {
+				if _, ok := types[ref]; !ok {
+					continue
+				}
+
+				if _, ok := included[ref]; ok {
+					continue
+				}
+
+				included[ref] = struct{}{}
+				res = append(res, ref)
+			}
+		}
+
+		return res
+	}
+
+	if _, ok := types[name]; ok {
+		included[name] = struct{}{}
+		level = []string{name}
+	} else {
+		for i, f := range p.Funcs {
+			if f.Name == name && f.Receiver == nil {
+				start = &p.Funcs[i]
+				break
+			}
+		}
+
+		if start == nil {
+			return nil, false
+		}
+
+		level = include(funcTypes(*start))
+	}
+
+	for depth := 0; len(level) != 0 && depth < maxClosureDepth; depth++ {
+		var next []string
+
+		for _, n := range level {
+			next = append(next, include(typeDefTypes(p.Types[types[n]]))...)
+
+			for _, f := range recvFuncs[n] {
+				next = append(next, include(funcTypes(f))...)
+			}
+		}
+
+		level = next
+	}
+
+	res := &Package{
+		Name:     p.Name,
+		Header:   p.Header,
+		Doc:      p.Doc,
+		Imports:  p.Imports,
+		printCfg: p.printCfg,
+	}
+
+	for _, td := range p.Types {
+		if _, ok := included[td.Name]; ok {
+			res.Types = append(res.Types, td)
+		}
+	}
+
+	for _, f := range p.Funcs {
+		if _, ok := included[f.ReceiverType()]; ok || (start != nil && f.Name == name && f.Receiver == nil) {
+			res.Funcs = append(res.Funcs, f)
+		}
+	}
+
+	return res, true
+}
+
+// typeDefTypes returns the type expressions of td's definition, fields, and
+// methods.
+func typeDefTypes(td TypeDef) []string {
+	typs := []string{td.Type, td.Key, td.Value, td.Elt}
+	typs = append(typs, fieldTypes(td.TypeParams)...)
+	typs = append(typs, fieldTypes(td.Params)...)
+	typs = append(typs, fieldTypes(td.Results)...)
+	typs = append(typs, fieldTypes(td.Fields)...)
+
+	for _, m := range td.Methods {
+		typs = append(typs, funcTypes(m)...)
+	}
+
+	return typs
+}
+
+// funcTypes returns the type expressions of f's receiver, type parameters,
+// parameters, and results.
+func funcTypes(f Func) []string {
+	var typs []string
+
+	if f.Receiver != nil {
+		typs = append(typs, f.Receiver.Type)
+	}
+
+	typs = append(typs, fieldTypes(f.TypeParams)...)
+	typs = append(typs, fieldTypes(f.Params)...)
+
+	return append(typs, fieldTypes(f.Results)...)
+}
+
+func fieldTypes(fields []Field) []string {
+	typs := make([]string, 0, len(fields))
+
+	for _, f := range fields {
+		typs = append(typs, f.Type)
+	}
+
+	return typs
+}
+
+// TypeRefs returns the identifiers referred to by type expression typ that
+// are not qualified by a package name, such as `int` and `Reader` for
+// `map[int][]*Reader`, or nil if typ is not a valid type expression.
+//
+// Names of fields and methods of inline struct and interface types are not
+// included, as they are not references to types.
+func TypeRefs(typ string) []string {
+	refs := typeRefOffsets(typ)
+	names := make([]string, len(refs))
+
+	for i, ref := range refs {
+		names[i] = ref.name
+	}
+
+	return names
+}
+
+// typeRef is an identifier referred to by a type expression.
+type typeRef struct {
+	name   string
+	offset int // Byte offset of the identifier in the type expression.
+}
+
+// typeRefOffsets returns the identifiers returned by [TypeRefs] for typ with
+// their offsets in typ.
+func typeRefOffsets(typ string) []typeRef {
+	trimmed := strings.TrimPrefix(typ, "...")
+	fset := token.NewFileSet()
+
+	expr, err := parser.ParseExprFrom(fset, "", trimmed, 0)
+	if err != nil {
+		return nil
+	}
+
+	var refs []typeRef
+
+	var inspect func(ast.Node)
+
+	inspect = func(node ast.Node) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				return false
+			case *ast.Field:
+				inspect(n.Type)
+				return false
+			case *ast.Ident:
+				offset := fset.Position(n.Pos()).Offset + len(typ) - len(trimmed)
+				refs = append(refs, typeRef{name: n.Name, offset: offset})
+			}
+
+			return true
+		})
+	}
+
+	inspect(expr)
+
+	return refs
+}
@@ -0,0 +1,133 @@
+package pkgdmp_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+func TestPackage_Closure(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "closure.go"}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		symbol    string
+		wantTypes []string
+		wantFuncs []string
+	}{
+		{
+			name:   "type with cyclic references",
+			symbol: "MyServer",
+			wantTypes: []string{
+				"MyConfig", "MyHandler", "MyLevel", "MyRequest", "MyResponse", "MyServer", "MyTLS", "myLogger",
+			},
+			wantFuncs: []string{"MyServer.Serve", "myLogger.Log"},
+		},
+		{
+			name:      "type without references",
+			symbol:    "MyRequest",
+			wantTypes: []string{"MyRequest"},
+		},
+		{
+			name:      "function",
+			symbol:    "MyHelper",
+			wantTypes: []string{"MyUnrelated"},
+			wantFuncs: []string{"MyHelper"},
+		},
+		{
+			name:      "interface method references",
+			symbol:    "myLogger",
+			wantTypes: []string{"MyLevel", "myLogger"},
+			wantFuncs: []string{"myLogger.Log"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			closure, ok := pkg.Closure(tt.symbol)
+			if !ok {
+				t.Fatalf("expected closure of %s to be found", tt.symbol)
+			}
+
+			var types, funcs []string
+
+			for _, td := range closure.Types {
+				types = append(types, td.Name)
+
+				for _, m := range td.Methods {
+					funcs = append(funcs, td.Name+"."+m.Name)
+				}
+			}
+
+			for _, f := range closure.Funcs {
+				funcs = append(funcs, f.Name)
+			}
+
+			if !reflect.DeepEqual(types, tt.wantTypes) {
+				t.Errorf("expected types %v, but got %v", tt.wantTypes, types)
+			}
+
+			if !reflect.DeepEqual(funcs, tt.wantFuncs) {
+				t.Errorf("expected funcs %v, but got %v", tt.wantFuncs, funcs)
+			}
+
+			if len(closure.Consts) != 0 {
+				t.Errorf("expected no consts, but got %v", closure.Consts)
+			}
+		})
+	}
+}
+
+func TestPackage_Closure_NotFound(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(defaultDocPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	for _, name := range []string{"MyNonExistentSymbol", "MyMethod"} {
+		if _, ok := pkg.Closure(name); ok {
+			t.Errorf("expected closure of %s not to be found", name)
+		}
+	}
+}
+
+func TestTypeRefs(t *testing.T) {
+	tt := []struct {
+		typ  string
+		want []string
+	}{
+		{"MyType", []string{"MyType"}},
+		{"map[int][]*MyType", []string{"int", "MyType"}},
+		{"...MyType", []string{"MyType"}},
+		{"io.Reader", nil},
+		{"func(r MyReader) (n int, err error)", []string{"MyReader", "int", "error"}},
+		{"struct{ myField MyType }", []string{"MyType"}},
+		{"interface{ Get(key string) MyValue }", []string{"string", "MyValue"}},
+		{"MyList[mypkg.Item, MyItem]", []string{"MyList", "MyItem"}},
+		{"not a type", nil},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.typ, func(t *testing.T) {
+			actual := pkgdmp.TypeRefs(tc.typ)
+
+			if len(actual) == 0 && len(tc.want) == 0 {
+				return
+			}
+
+			if !reflect.DeepEqual(actual, tc.want) {
+				t.Errorf("expected type references %v, but got %v", tc.want, actual)
+			}
+		})
+	}
+}
@@ -2,20 +2,21 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/doc"
 	"go/parser"
 	"go/token"
-	"io/fs"
+	"io"
 	"log"
 	"os"
-	"strings"
+	"sort"
 
 	"github.com/michenriksen/pkgdmp"
 	"github.com/michenriksen/pkgdmp/internal/cli"
 
-	"github.com/alecthomas/chroma/quick"
+	"golang.org/x/term"
 )
 
 func main() {
@@ -24,102 +25,501 @@ func main() {
 		os.Exit(exitCode)
 	}
 
+	// The -api mode replaces the directory arguments with all directories
+	// of the public API surface below them.
+	if cfg.API {
+		dirs, err := cli.APIDirs(cfg.Dirs)
+		if err != nil {
+			fatal(cfg, err)
+		}
+
+		cfg.Dirs = dirs
+	}
+
 	pkgParserOpts, err := cli.ParserOptsFromCfg(cfg)
 	if err != nil {
-		log.Fatal(err)
+		fatal(cfg, cli.UsageError(err))
 	}
 
 	pkgParser, err := pkgdmp.NewParser(pkgParserOpts...)
 	if err != nil {
-		log.Fatal(err)
+		fatal(cfg, cli.UsageError(err))
+	}
+
+	if cfg.DryRun {
+		if err := dryRun(cfg, pkgParser); err != nil {
+			fatal(cfg, err)
+		}
+
+		return
+	}
+
+	if cfg.MethodDiff != "" {
+		if err := writeMethodDiffs(cfg, pkgParser); err != nil {
+			fatal(cfg, err)
+		}
+
+		return
 	}
 
-	unparsed, err := getPackages(cfg.Dirs)
+	enc, err := cfg.Encoder(isTerminal(os.Stdout))
 	if err != nil {
-		log.Fatal(err)
+		fatal(cfg, err)
 	}
 
-	parsed := make([]*pkgdmp.Package, 0, len(unparsed))
+	if err := writePackages(cfg, pkgParser, enc); err != nil {
+		fatal(cfg, err)
+	}
+}
 
-	for _, uPkg := range unparsed {
-		if !cfg.IncludePackage(uPkg.Name) {
-			continue
+// writeMethodDiffs writes the method diff of the types compared with the
+// -method-diff flag for each package declaring both, and returns a usage error
+// if no package declares both.
+func writeMethodDiffs(cfg *cli.Config, pkgParser *pkgdmp.Parser) error {
+	var written bool
+
+	err := eachPackage(cfg, pkgParser, func(pkg *pkgdmp.Package) error {
+		err := cli.WriteMethodDiff(os.Stdout, pkg, cfg.MethodDiff, cfg.MethodDiffWith)
+		if errors.Is(err, cli.ErrTypeNotFound) {
+			return nil
 		}
 
-		pkg, err := pkgParser.Package(doc.New(uPkg, "", doc.AllDecls))
 		if err != nil {
-			log.Fatal(err)
+			return err //nolint:wrapcheck // error is already wrapped.
 		}
 
-		parsed = append(parsed, pkg)
+		written = true
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	if err := printPackages(parsed, cfg); err != nil {
-		log.Fatal(err)
+	if !written {
+		return cli.UsageError(fmt.Errorf("%w: no package declares both %s and %s",
+			cli.ErrTypeNotFound, cfg.MethodDiff, cfg.MethodDiffWith,
+		))
 	}
+
+	return nil
 }
 
-func getPackages(dirs []string) ([]*ast.Package, error) {
-	var all []*ast.Package
+// fatal reports err and exits with the exit code for its class of error, as
+// returned by [cli.ExitCode].
+//
+// With JSON output, err is written to stdout as a JSON object with an `error`
+// field, so callers always receive JSON, unless it was already written as
+// part of the output. Otherwise, err is logged to stderr.
+func fatal(cfg *cli.Config, err error) {
+	if f := cfg.OutputFormat(); (f == cli.FormatJSON || f == cli.FormatFlatJSON) && !cli.IsReported(err) {
+		if wErr := cli.WriteJSONError(os.Stdout, err); wErr == nil {
+			os.Exit(cli.ExitCode(err))
+		}
+	}
+
+	log.Print(err)
+	os.Exit(cli.ExitCode(err))
+}
 
-	for _, dir := range dirs {
-		fset := token.NewFileSet()
+// eachPackage parses the configured directories one at a time and calls fn
+// with each included package, reduced to the closure of a symbol or to the
+// selected symbols, without symbols identical to ones of earlier packages, and
+// renamed if configured.
+//
+// Packages without the symbol of a configured closure or without any of the
+// selected symbols are skipped, and a usage error is returned if a selected
+// symbol is in none of the packages.
+func eachPackage(cfg *cli.Config, pkgParser *pkgdmp.Parser, fn func(*pkgdmp.Package) error) error {
+	var cache *cli.Cache
 
-		pkgs, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
-			return !strings.HasSuffix(fi.Name(), "_test.go")
-		}, parser.ParseComments)
+	if cfg.Cache != "" {
+		c, err := cli.NewCache(cfg.Cache)
 		if err != nil {
-			return nil, fmt.Errorf("parsing files in %s: %w", dir, err)
+			return err //nolint:wrapcheck // error is already wrapped.
+		}
+
+		cache = c
+	}
+
+	var deduper pkgdmp.Deduper
+
+	found := make(map[string]bool, len(cfg.Symbols))
+
+	for _, dir := range cfg.Dirs {
+		pkgs, err := dirPackages(cfg, pkgParser, cache, dir)
+		if err != nil {
+			return err
 		}
 
 		for _, pkg := range pkgs {
-			all = append(all, pkg)
+			if cfg.Closure != "" {
+				closure, ok := pkg.Closure(cfg.Closure)
+				if !ok {
+					continue
+				}
+
+				pkg = closure
+			}
+
+			if len(cfg.Symbols) != 0 {
+				for _, sel := range cfg.Symbols {
+					if !found[sel] {
+						_, found[sel] = pkg.Select(sel)
+					}
+				}
+
+				selected, ok := pkg.Select(cfg.Symbols...)
+				if !ok {
+					continue
+				}
+
+				pkg = selected
+			}
+
+			if cfg.Dedupe {
+				pkg = deduper.Dedupe(pkg)
+			}
+
+			if cfg.SkipEmpty && pkg.IsEmpty() {
+				continue
+			}
+
+			if cfg.As != "" {
+				pkg.Name = cfg.As
+			}
+
+			if err := fn(pkg); err != nil {
+				return err
+			}
 		}
 	}
 
-	return all, nil
+	for _, sel := range cfg.Symbols {
+		if !found[sel] {
+			return cli.UsageError(fmt.Errorf("selected symbol %s is in none of the packages", sel))
+		}
+	}
+
+	return nil
+}
+
+// cacheEntry is a cached result of parsing the packages of a directory.
+type cacheEntry struct {
+	Packages json.RawMessage `json:"packages"`
+	Warnings [][]string      `json:"warnings"`
 }
 
-func printPackages(pkgs []*pkgdmp.Package, cfg *cli.Config) error {
-	if cfg.JSON {
-		encoder := json.NewEncoder(os.Stdout)
-		encoder.SetIndent("", "  ")
+// dirPackages returns the included packages in dir, from cache if it is
+// non-nil and contains an entry for the directory's current source files.
+//
+// Warnings of cached packages are reported as when they were parsed.
+func dirPackages(cfg *cli.Config, pkgParser *pkgdmp.Parser, cache *cli.Cache, dir string) ([]*pkgdmp.Package, error) {
+	var key string
 
-		if err := encoder.Encode(pkgs); err != nil {
-			return fmt.Errorf("encoding packages as JSON: %w", err)
+	if cache != nil && dir != "-" && !cli.IsGoFile(dir) && !cli.IsArchive(dir) {
+		k, err := cache.Key(cfg, dir, cfg.SourceFileFilter(dir))
+		if err != nil {
+			return nil, fmt.Errorf("computing cache key for %s: %w", dir, err)
 		}
 
-		return nil
+		data, ok, err := cache.Get(k)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // error is already wrapped.
+		}
+
+		if ok {
+			return cachedPackages(cfg, pkgParser, data)
+		}
+
+		key = k
+	}
+
+	unparsed, fset, err := getPackages(dir, cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, pkg := range pkgs {
-		source, err := pkg.Source()
+	sort.Slice(unparsed, func(i, j int) bool { return unparsed[i].Name < unparsed[j].Name })
+
+	pkgs := make([]*pkgdmp.Package, 0, len(unparsed))
+	entry := cacheEntry{Warnings: make([][]string, 0, len(unparsed))}
+
+	for _, uPkg := range unparsed {
+		if !cfg.IncludeSourcePackage(uPkg) {
+			continue
+		}
+
+		pkg, err := parsePackage(cfg, pkgParser, uPkg)
 		if err != nil {
-			return fmt.Errorf("getting source for %s package: %w", pkg.Name, err)
+			return nil, cli.ParseError(fmt.Errorf("parsing %s package: %w", uPkg.Name, err))
 		}
 
-		if cfg.NoHighlight {
-			fmt.Printf("%s\n\n", source)
-			continue
+		warnings := warningLines(cfg, fset, pkgParser.Warnings())
+		if err := reportWarnings(cfg, uPkg.Name, warnings); err != nil {
+			return nil, err
+		}
+
+		pkgs = append(pkgs, pkg)
+		entry.Warnings = append(entry.Warnings, warnings)
+	}
+
+	if key != "" {
+		var err error
+
+		if entry.Packages, err = pkgParser.MarshalPackages(pkgs); err != nil {
+			return nil, fmt.Errorf("encoding packages for cache: %w", err)
+		}
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return nil, fmt.Errorf("encoding packages for cache: %w", err)
+		}
+
+		if err := cache.Put(key, data); err != nil {
+			return nil, err //nolint:wrapcheck // error is already wrapped.
+		}
+	}
+
+	return pkgs, nil
+}
+
+// cachedPackages decodes the packages of cache entry data and reports their
+// warnings.
+func cachedPackages(cfg *cli.Config, pkgParser *pkgdmp.Parser, data []byte) ([]*pkgdmp.Package, error) {
+	var entry cacheEntry
+
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("decoding cache entry: %w", err)
+	}
+
+	pkgs, err := pkgParser.UnmarshalPackages(entry.Packages)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // error is already wrapped.
+	}
+
+	if len(entry.Warnings) != len(pkgs) {
+		return nil, fmt.Errorf("decoding cache entry: warnings for %d packages, but got %d packages",
+			len(entry.Warnings), len(pkgs),
+		)
+	}
+
+	for i, pkg := range pkgs {
+		if err := reportWarnings(cfg, pkg.Name, entry.Warnings[i]); err != nil {
+			return nil, err
 		}
+	}
+
+	return pkgs, nil
+}
 
-		highlighted, err := highlight(source, cfg.Theme)
+// dryRun parses and filters packages in the configured directories and
+// writes counts of included and excluded symbols for each package.
+//
+// Packages are always parsed from source, since cached packages carry no
+// filter results.
+func dryRun(cfg *cli.Config, pkgParser *pkgdmp.Parser) error {
+	for _, dir := range cfg.Dirs {
+		unparsed, fset, err := getPackages(dir, cfg)
 		if err != nil {
-			return fmt.Errorf("syntax highlighting source for %s package: %w", pkg.Name, err)
+			return err
 		}
 
-		fmt.Printf("%s\n\n", highlighted)
+		sort.Slice(unparsed, func(i, j int) bool { return unparsed[i].Name < unparsed[j].Name })
+
+		for _, uPkg := range unparsed {
+			if !cfg.IncludeSourcePackage(uPkg) {
+				continue
+			}
+
+			if _, err := parsePackage(cfg, pkgParser, uPkg); err != nil {
+				return cli.ParseError(fmt.Errorf("parsing %s package: %w", uPkg.Name, err))
+			}
+
+			if err := reportWarnings(cfg, uPkg.Name, warningLines(cfg, fset, pkgParser.Warnings())); err != nil {
+				return err
+			}
+
+			if err := cli.WriteFilterCounts(os.Stdout, uPkg.Name, pkgParser.FilterResults()); err != nil {
+				return err //nolint:wrapcheck // error is already wrapped.
+			}
+		}
 	}
 
 	return nil
 }
 
-func highlight(source, theme string) (string, error) {
-	var b strings.Builder
+// writePackages writes the packages of the configured directories to stdout
+// with enc.
+//
+// If enc is a [cli.StreamEncoder], packages are written as they are parsed,
+// so only one directory is held in memory at a time.
+func writePackages(cfg *cli.Config, pkgParser *pkgdmp.Parser, enc pkgdmp.Encoder) error {
+	each := func(fn func(*pkgdmp.Package) error) error {
+		return eachPackage(cfg, pkgParser, fn)
+	}
+
+	if sEnc, ok := enc.(cli.StreamEncoder); ok {
+		return sEnc.EncodeStream(os.Stdout, each) //nolint:wrapcheck // error is already wrapped.
+	}
+
+	var parsed []*pkgdmp.Package
+
+	err := each(func(pkg *pkgdmp.Package) error {
+		parsed = append(parsed, pkg)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return enc.Encode(os.Stdout, parsed) //nolint:wrapcheck // error is already wrapped.
+}
+
+// warningLines returns parser warnings as messages prefixed with their
+// position if known.
+func warningLines(cfg *cli.Config, fset *token.FileSet, warnings []error) []string {
+	lines := make([]string, 0, len(warnings))
+
+	for _, w := range warnings {
+		var uErr *pkgdmp.UnsupportedError
+
+		if errors.As(w, &uErr) && uErr.Pos.IsValid() {
+			lines = append(lines, fmt.Sprintf("%s: %v", cfg.Position(fset, uErr.Pos), w))
+			continue
+		}
+
+		lines = append(lines, w.Error())
+	}
+
+	return lines
+}
+
+// reportWarnings writes warnings of the named package, as returned by
+// [warningLines], to stderr, and returns an error if there are any warnings
+// and strict mode is enabled.
+func reportWarnings(cfg *cli.Config, pkgName string, warnings []string) error {
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+
+	if cfg.Strict && len(warnings) != 0 {
+		return cli.ParseError(fmt.Errorf("%s package has %d unsupported declarations", pkgName, len(warnings)))
+	}
+
+	return nil
+}
+
+// parsePackage parses sPkg with type information if it has any, and sets its
+// header if configured.
+//
+// Test files of sPkg are included in the documentation to associate examples
+// with symbols, and flags of main packages are detected if configured.
+func parsePackage(cfg *cli.Config, pkgParser *pkgdmp.Parser, sPkg cli.SourcePackage) (*pkgdmp.Package, error) {
+	var flags []pkgdmp.CommandFlag
+
+	// Flags are detected in the syntax trees of the package, as function
+	// bodies are not part of its documentation.
+	if cfg.DetectFlags && sPkg.Name == "main" {
+		flags = cli.DetectFlags(sPkg.Package)
+	}
+
+	files := packageFiles(sPkg)
+
+	dPkg, err := newDocPackage(cfg.FileSet(), sPkg, files)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkg *pkgdmp.Package
+
+	if sPkg.Types != nil {
+		pkg, err = pkgParser.TypedPackage(dPkg, sPkg.Types, files...)
+	} else {
+		pkg, err = pkgParser.Package(dPkg, files...)
+	}
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // error is wrapped by caller.
+	}
+
+	if cfg.Header {
+		pkg.Header = sPkg.Header
+	}
+
+	pkg.Flags = flags
+
+	return pkg, nil
+}
+
+func newDocPackage(fset *token.FileSet, sPkg cli.SourcePackage, files []*ast.File) (*doc.Package, error) {
+	if len(sPkg.TestFiles) == 0 {
+		return doc.New(sPkg.Package, "", doc.AllDecls|doc.PreserveAST), nil
+	}
 
-	if err := quick.Highlight(&b, source, "go", "terminal", theme); err != nil {
-		return "", fmt.Errorf("chroma error: %w", err)
+	dPkg, err := doc.NewFromFiles(fset, files, "", doc.AllDecls|doc.PreserveAST)
+	if err != nil {
+		return nil, fmt.Errorf("creating documentation for %s package: %w", sPkg.Name, err)
 	}
 
-	return b.String(), nil
+	return dPkg, nil
+}
+
+// packageFiles returns the files of sPkg sorted by name, followed by its test
+// files.
+func packageFiles(sPkg cli.SourcePackage) []*ast.File {
+	names := make([]string, 0, len(sPkg.Files))
+
+	for name := range sPkg.Files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	files := make([]*ast.File, 0, len(names)+len(sPkg.TestFiles))
+
+	for _, name := range names {
+		files = append(files, sPkg.Files[name])
+	}
+
+	return append(files, sPkg.TestFiles...)
+}
+
+func getPackages(dir string, cfg *cli.Config) ([]cli.SourcePackage, *token.FileSet, error) {
+	if dir != "-" {
+		return cfg.LoadPackages(dir) //nolint:wrapcheck // error is already wrapped.
+	}
+
+	fset := cfg.FileSet()
+
+	pkg, err := parseStdin(fset, cfg.StdinName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return []cli.SourcePackage{{Package: pkg, Header: cli.PackageHeader(pkg)}}, fset, nil
+}
+
+// parseStdin parses Go source from standard input as a single-file package,
+// using name as the file name in positions and error messages.
+func parseStdin(fset *token.FileSet, name string) (*ast.Package, error) {
+	src, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("reading from stdin: %w", err)
+	}
+
+	file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing stdin: %w", err)
+	}
+
+	return &ast.Package{
+		Name:  file.Name.Name,
+		Files: map[string]*ast.File{name: file},
+	}, nil
+}
+
+// isTerminal returns true if f is a terminal.
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
 }
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runMainEnv is set to make the test binary run [main] instead of the tests,
+// so the command is tested as invoked from the command line.
+const runMainEnv = "PKGDMP_TEST_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) != "" {
+		os.Args = append([]string{"pkgdmp"}, strings.Fields(os.Getenv(runMainEnv))...)
+		main()
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// runPkgdmp runs the command with args in dir and returns its stdout and
+// exit code.
+func runPkgdmp(t *testing.T, dir string, args ...string) (string, int) {
+	t.Helper()
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("error getting test executable: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(exe)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), runMainEnv+"="+strings.Join(args, " "))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatalf("error running command: %v", err)
+	}
+
+	if exitErr != nil {
+		t.Logf("stderr: %s", stderr.String())
+		return stdout.String(), exitErr.ExitCode()
+	}
+
+	return stdout.String(), 0
+}
+
+// writeTestPackages writes a package with an exported function to directory
+// exported, a package with only an unexported function to directory
+// unexported, and a package with two types with methods to directory stores
+// of a new temporary directory, and returns its path.
+func writeTestPackages(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"exported/exported.go":     "package exported\n\nfunc MyFunc() {}\n",
+		"unexported/unexported.go": "package unexported\n\nfunc myFunc() {}\n",
+		"stores/stores.go": "package stores\n\ntype MyFileStore struct{}\n\n" +
+			"func (MyFileStore) Get() {}\n\nfunc (MyFileStore) Sync() {}\n\n" +
+			"type MyMemStore struct{}\n\nfunc (MyMemStore) Get() {}\n",
+	}
+
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("error creating directory: %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+			t.Fatalf("error writing source file: %v", err)
+		}
+	}
+
+	return dir
+}
+
+func TestSkipEmpty(t *testing.T) {
+	dir := writeTestPackages(t)
+
+	t.Run("text", func(t *testing.T) {
+		out, code := runPkgdmp(t, dir, "-skip-empty", "exported", "unexported")
+		if code != 0 {
+			t.Fatalf("expected exit code 0, but got %d", code)
+		}
+
+		if !strings.Contains(out, "package exported") {
+			t.Errorf("expected output to contain exported package, but got:\n\n%s", out)
+		}
+
+		if strings.Contains(out, "package unexported") {
+			t.Errorf("expected output to not contain empty unexported package, but got:\n\n%s", out)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, code := runPkgdmp(t, dir, "-skip-empty", "-format", "json", "exported", "unexported")
+		if code != 0 {
+			t.Fatalf("expected exit code 0, but got %d", code)
+		}
+
+		var pkgs []struct {
+			Name string `json:"name"`
+		}
+
+		if err := json.Unmarshal([]byte(out), &pkgs); err != nil {
+			t.Fatalf("error decoding output: %v\n\n%s", err, out)
+		}
+
+		if len(pkgs) != 1 || pkgs[0].Name != "exported" {
+			t.Errorf("expected only exported package, but got %+v", pkgs)
+		}
+	})
+
+	for _, format := range []string{"json", "flat-json"} {
+		t.Run(format+" all skipped", func(t *testing.T) {
+			out, code := runPkgdmp(t, dir, "-skip-empty", "-format", format, "unexported")
+			if code != 0 {
+				t.Fatalf("expected exit code 0, but got %d", code)
+			}
+
+			if strings.TrimSpace(out) != "[]" {
+				t.Errorf("expected empty JSON array, but got:\n\n%s", out)
+			}
+		})
+	}
+
+	t.Run("not skipped", func(t *testing.T) {
+		out, code := runPkgdmp(t, dir, "exported", "unexported")
+		if code != 0 {
+			t.Fatalf("expected exit code 0, but got %d", code)
+		}
+
+		if !strings.Contains(out, "package unexported") {
+			t.Errorf("expected output to contain unexported package without -skip-empty, but got:\n\n%s", out)
+		}
+	})
+}
+
+func TestMethodDiff(t *testing.T) {
+	dir := writeTestPackages(t)
+
+	out, code := runPkgdmp(t, dir, "-method-diff", "MyFileStore", "MyMemStore", "stores", "exported")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, but got %d", code)
+	}
+
+	want := "package stores\n  only MyFileStore  Sync\n  only MyMemStore   -\n  common            Get\n"
+
+	if out != want {
+		t.Errorf("expected output:\n\n%s\nbut got:\n\n%s", want, out)
+	}
+
+	for _, args := range [][]string{
+		{"-method-diff", "MyFileStore", "MyMemStroe", "stores"},
+		{"-method-diff", "MyFileStore", "MyMemStore", "stores", "--", "MyFileStore"},
+		{"-format", "json", "-method-diff", "MyFileStore", "MyMemStore", "stores"},
+	} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			out, code := runPkgdmp(t, dir, args...)
+			if code != 2 {
+				t.Errorf("expected exit code 2, but got %d", code)
+			}
+
+			if out != "" {
+				t.Errorf("expected no output, but got:\n\n%s", out)
+			}
+		})
+	}
+}
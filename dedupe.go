@@ -0,0 +1,84 @@
+package pkgdmp
+
+import "fmt"
+
+// Deduper removes symbols from packages that are identical to symbols of
+// packages it has already deduplicated, such as types re-exported as aliases
+// by several packages.
+//
+// Symbols are identical if they have the same name and rendered signature, as
+// given by [Func.SignatureHash] and [TypeDef.SignatureHash], ignoring doc
+// comments. Methods are removed together with their types, and methods
+// declared on types of other packages are compared by receiver type and name.
+//
+// The zero value is ready to use.
+type Deduper struct {
+	seen map[string]struct{}
+}
+
+// Dedupe returns a copy of pkg without the consts, vars, types, and functions
+// identical to ones seen in earlier packages, and records the remaining
+// symbols as seen.
+func (d *Deduper) Dedupe(pkg *Package) *Package {
+	if d.seen == nil {
+		d.seen = make(map[string]struct{})
+	}
+
+	res := *pkg
+	res.Consts, res.Vars, res.Types, res.Funcs = nil, nil, nil, nil
+
+	for _, cg := range pkg.Consts {
+		var consts []Const
+
+		for _, c := range cg.Consts {
+			if d.add(fmt.Sprintf("const %s %v", c.Spec, c.Values)) {
+				consts = append(consts, c)
+			}
+		}
+
+		if len(consts) != 0 {
+			cg.Consts = consts
+			res.Consts = append(res.Consts, cg)
+		}
+	}
+
+	for _, vg := range pkg.Vars {
+		var vars []Var
+
+		for _, v := range vg.Vars {
+			if d.add(fmt.Sprintf("var %s %v", v.Spec, v.Embed)) {
+				vars = append(vars, v)
+			}
+		}
+
+		if len(vars) != 0 {
+			vg.Vars = vars
+			res.Vars = append(res.Vars, vg)
+		}
+	}
+
+	for _, td := range pkg.Types {
+		if d.add("type " + td.Name + " " + td.SignatureHash()) {
+			res.Types = append(res.Types, td)
+		}
+	}
+
+	for _, f := range pkg.Funcs {
+		if d.add("func " + f.ReceiverType() + "." + f.Name + " " + f.SignatureHash()) {
+			res.Funcs = append(res.Funcs, f)
+		}
+	}
+
+	return &res
+}
+
+// add records key as seen and returns true if it was not seen before.
+func (d *Deduper) add(key string) bool {
+	if _, ok := d.seen[key]; ok {
+		return false
+	}
+
+	d.seen[key] = struct{}{}
+
+	return true
+}
@@ -0,0 +1,92 @@
+package pkgdmp_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+func TestDeduper_Dedupe(t *testing.T) {
+	first := parseSource(t, `package mypackage
+
+import "example.com/mymodule/internal/config"
+
+// MyConfig is the configuration.
+type MyConfig = config.Config
+
+// MyVersion is the version.
+const MyVersion = "1.0.0"
+
+// MyOption is an option.
+type MyOption int
+
+// New returns a new configuration.
+func New() *MyConfig { return nil }
+
+// MyFirstFunc is only in the first package.
+func MyFirstFunc() {}
+`)
+
+	second := parseSource(t, `package myotherpackage
+
+import "example.com/mymodule/internal/config"
+
+// MyConfig re-exports the configuration.
+type MyConfig = config.Config
+
+// MyVersion is the version.
+const MyVersion = "1.0.0"
+
+// MyOption is a different option.
+type MyOption string
+
+// New returns a new configuration.
+func New() *MyConfig { return nil }
+
+// MySecondFunc is only in the second package.
+func MySecondFunc() {}
+`)
+
+	var d pkgdmp.Deduper
+
+	if got := symbolNames(d.Dedupe(first)); !reflect.DeepEqual(got, symbolNames(first)) {
+		t.Errorf("expected first package to be unchanged, but got symbols %v", got)
+	}
+
+	want := []string{"MyOption", "MySecondFunc"}
+
+	deduped := d.Dedupe(second)
+
+	if got := symbolNames(deduped); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected symbols %v in second package, but got %v", want, got)
+	}
+
+	if deduped.Name != "myotherpackage" {
+		t.Errorf("expected deduplicated package to keep its name, but got %s", deduped.Name)
+	}
+
+	if len(second.Types) != 2 || len(second.Funcs) != 2 || len(second.Consts) != 1 {
+		t.Errorf("expected original package to be unchanged, but got symbols %v", symbolNames(second))
+	}
+}
+
+func symbolNames(pkg *pkgdmp.Package) []string {
+	var names []string
+
+	for _, cg := range pkg.Consts {
+		for _, c := range cg.Consts {
+			names = append(names, c.Ident())
+		}
+	}
+
+	for _, td := range pkg.Types {
+		names = append(names, td.Name)
+	}
+
+	for _, f := range pkg.Funcs {
+		names = append(names, f.Name)
+	}
+
+	return names
+}
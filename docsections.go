@@ -0,0 +1,130 @@
+package pkgdmp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// docSectionHeadings maps conventional doc comment section headings, in lower
+// case and without the trailing colon, to the sections they start.
+var docSectionHeadings = map[string]docSection{
+	"parameters":    docSectionParams,
+	"params":        docSectionParams,
+	"arguments":     docSectionParams,
+	"args":          docSectionParams,
+	"returns":       docSectionReturns,
+	"return":        docSectionReturns,
+	"return value":  docSectionReturns,
+	"return values": docSectionReturns,
+	"example":       docSectionExample,
+	"examples":      docSectionExample,
+	"example usage": docSectionExample,
+	"usage":         docSectionExample,
+}
+
+// docParamRegexp matches a parameter description line such as
+// `a: The first integer` or `- a - The first integer`.
+var docParamRegexp = regexp.MustCompile(`^(?:[-*]\s+)?([\pL_][\pL\pN_]*(?:,\s*[\pL_][\pL\pN_]*)*)\s*(?::|\s-)\s*(.*)$`)
+
+type docSection int
+
+const (
+	docSectionNone docSection = iota
+	docSectionParams
+	docSectionReturns
+	docSectionExample
+)
+
+// DocSections represents conventional sections of a function's doc comment,
+// such as `Parameters:`, `Returns:`, and `Example usage:`.
+type DocSections struct {
+	Params  []DocParam `json:"params,omitempty"`
+	Returns string     `json:"returns,omitempty"`
+	Example string     `json:"example,omitempty"`
+}
+
+// DocParam represents the description of a parameter in the `Parameters:`
+// section of a doc comment.
+type DocParam struct {
+	Name string `json:"name"`
+	Doc  string `json:"doc,omitempty"`
+}
+
+// parseDocSections returns the conventional sections of doc comment text doc,
+// or nil if it has none.
+//
+// A section starts at a line consisting only of a recognized heading followed
+// by a colon, and ends at the next heading or at the first unindented
+// paragraph after its content.
+func parseDocSections(doc string) *DocSections {
+	var (
+		res     DocSections
+		found   bool
+		section docSection
+		content bool
+		blank   bool
+		example []string
+		returns []string
+	)
+
+	for _, line := range strings.Split(doc, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		heading, isHeading := strings.CutSuffix(trimmed, ":")
+
+		if s, ok := docSectionHeadings[strings.ToLower(heading)]; ok && isHeading {
+			section, content, blank, found = s, false, false, true
+			continue
+		}
+
+		if section == docSectionNone {
+			continue
+		}
+
+		if trimmed == "" {
+			blank = true
+
+			if section == docSectionExample && len(example) != 0 {
+				example = append(example, "")
+			}
+
+			continue
+		}
+
+		if blank && content && line == strings.TrimLeft(line, " \t") {
+			section = docSectionNone
+			continue
+		}
+
+		content, blank = true, false
+
+		switch section {
+		case docSectionParams:
+			if m := docParamRegexp.FindStringSubmatch(trimmed); m != nil {
+				res.Params = append(res.Params, DocParam{Name: m[1], Doc: m[2]})
+				continue
+			}
+
+			if n := len(res.Params); n != 0 {
+				res.Params[n-1].Doc = strings.TrimSpace(res.Params[n-1].Doc + " " + trimmed)
+			}
+		case docSectionReturns:
+			returns = append(returns, trimmed)
+		case docSectionExample:
+			example = append(example, strings.TrimPrefix(line, "\t"))
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	res.Returns = strings.Join(returns, " ")
+	res.Example = strings.TrimSpace(strings.Join(example, "\n"))
+
+	if len(res.Params) == 0 && res.Returns == "" && res.Example == "" {
+		return nil
+	}
+
+	return &res
+}
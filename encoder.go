@@ -0,0 +1,112 @@
+package pkgdmp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Names of the built-in encoders.
+const (
+	EncoderText = "text"
+	EncoderJSON = "json"
+)
+
+// Encoder writes packages to an output stream in an output format.
+type Encoder interface {
+	Encode(w io.Writer, pkgs []*Package) error
+}
+
+// EncoderFunc is an adapter to use ordinary functions as encoders.
+type EncoderFunc func(w io.Writer, pkgs []*Package) error
+
+// Encode calls f(w, pkgs).
+func (f EncoderFunc) Encode(w io.Writer, pkgs []*Package) error {
+	return f(w, pkgs)
+}
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[string]Encoder{
+		EncoderText: EncoderFunc(encodeText),
+		EncoderJSON: EncoderFunc(encodeJSON),
+	}
+)
+
+// RegisterEncoder makes an encoder available by the provided format name.
+//
+// RegisterEncoder panics if enc is nil or an encoder is already registered
+// with the name, including the built-in [EncoderText] and [EncoderJSON]
+// encoders.
+func RegisterEncoder(name string, enc Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	if enc == nil {
+		panic("pkgdmp: RegisterEncoder encoder is nil")
+	}
+
+	if _, ok := encoders[name]; ok {
+		panic("pkgdmp: RegisterEncoder called twice for encoder " + name)
+	}
+
+	encoders[name] = enc
+}
+
+// LookupEncoder returns the encoder registered with the provided format name,
+// and false if there is none.
+func LookupEncoder(name string) (Encoder, bool) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	enc, ok := encoders[name]
+
+	return enc, ok
+}
+
+// Encoders returns the sorted format names of the registered encoders.
+func Encoders() []string {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	names := make([]string, 0, len(encoders))
+
+	for name := range encoders {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// encodeText writes the formatted source code of each package, separated by
+// blank lines.
+func encodeText(w io.Writer, pkgs []*Package) error {
+	for _, pkg := range pkgs {
+		source, err := pkg.Source()
+		if err != nil {
+			return fmt.Errorf("getting source for %s package: %w", pkg.Name, err)
+		}
+
+		if _, err := fmt.Fprintf(w, "%s\n\n", source); err != nil {
+			return fmt.Errorf("writing source for %s package: %w", pkg.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// encodeJSON writes the packages as an indented JSON array.
+func encodeJSON(w io.Writer, pkgs []*Package) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(pkgs); err != nil {
+		return fmt.Errorf("encoding as JSON: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package pkgdmp_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+func TestLookupEncoder_BuiltIn(t *testing.T) {
+	pkgs := []*pkgdmp.Package{
+		{Name: "mypackage", Funcs: []pkgdmp.Func{{Name: "MyFunc"}}},
+		{Name: "otherpackage"},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		enc, ok := pkgdmp.LookupEncoder(pkgdmp.EncoderText)
+		if !ok {
+			t.Fatal("expected text encoder to be registered")
+		}
+
+		var b strings.Builder
+
+		if err := enc.Encode(&b, pkgs); err != nil {
+			t.Fatalf("expected no error, but got: %v", err)
+		}
+
+		want := "package mypackage\n\nfunc MyFunc()\n\n\npackage otherpackage\n\n\n"
+
+		if b.String() != want {
+			t.Errorf("expected output:\n\n%q\n\nbut got:\n\n%q", want, b.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		enc, ok := pkgdmp.LookupEncoder(pkgdmp.EncoderJSON)
+		if !ok {
+			t.Fatal("expected json encoder to be registered")
+		}
+
+		var b strings.Builder
+
+		if err := enc.Encode(&b, pkgs); err != nil {
+			t.Fatalf("expected no error, but got: %v", err)
+		}
+
+		var decoded []*pkgdmp.Package
+
+		if err := json.Unmarshal([]byte(b.String()), &decoded); err != nil {
+			t.Fatalf("expected valid JSON, but got error: %v", err)
+		}
+
+		if len(decoded) != 2 || decoded[0].Name != "mypackage" || decoded[1].Name != "otherpackage" {
+			t.Errorf("expected decoded packages to match encoded packages, but got %d packages", len(decoded))
+		}
+	})
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	name := "test-names"
+
+	pkgdmp.RegisterEncoder(name, pkgdmp.EncoderFunc(func(w io.Writer, pkgs []*pkgdmp.Package) error {
+		for _, pkg := range pkgs {
+			if _, err := fmt.Fprintln(w, pkg.Name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	enc, ok := pkgdmp.LookupEncoder(name)
+	if !ok {
+		t.Fatalf("expected %s encoder to be registered", name)
+	}
+
+	var b strings.Builder
+
+	if err := enc.Encode(&b, []*pkgdmp.Package{{Name: "mypackage"}, {Name: "otherpackage"}}); err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if want := "mypackage\notherpackage\n"; b.String() != want {
+		t.Errorf("expected output %q, but got %q", want, b.String())
+	}
+
+	found := false
+
+	for _, n := range pkgdmp.Encoders() {
+		if n == name {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("expected %s to be in registered encoders %v", name, pkgdmp.Encoders())
+	}
+
+	if _, ok := pkgdmp.LookupEncoder("unregistered"); ok {
+		t.Error("expected no encoder for unregistered format")
+	}
+
+	t.Run("panics on duplicate", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected RegisterEncoder to panic for duplicate name")
+			}
+		}()
+
+		pkgdmp.RegisterEncoder(pkgdmp.EncoderText, enc)
+	})
+}
@@ -2,20 +2,58 @@ package pkgdmp
 
 import (
 	"fmt"
-	"go/ast"
+	"go/doc"
 	"go/format"
 	"io"
+	"sort"
 	"strings"
 )
 
+// SchemaVersion is the version of the JSON representation of [Package], the
+// entities it contains, and [PackageStats].
+//
+// The version is incremented whenever fields are added, removed, or renamed.
+const SchemaVersion = 1
+
+// printConfig configures how entities are rendered as code.
+type printConfig struct {
+	wrap        int  // Column to wrap comments at, or 0 for no wrapping.
+	groupByKind bool // Group symbols in labeled sections by kind.
+	noRecvNames bool // Omit variable names of method receivers.
+	noRecvs     bool // Omit method receivers.
+	flatConsts  bool // Coalesce single consts of the same type into blocks.
+	maxMethods  int  // Maximum number of methods to print per type, or 0 for all.
+	wrapSigs    int  // Column to wrap function signatures at, or 0 for no wrapping.
+	zeroValues  bool // Annotate struct fields with the zero value of their type.
+	underlying  bool // Annotate identifier type definitions with their underlying type.
+	noEmpty     bool // Render empty struct and interface bodies on a single line.
+	compact     bool // Render struct and interface bodies on a single line.
+	groupCtors  bool // Render constructor functions after the types they construct.
+	methodDocs  bool // Render interface method synopses as trailing line comments.
+	sortMethods bool // Render interface methods in alphabetical order.
+
+	// Names of unexported types to render as internal placeholders.
+	internalTypes map[string]struct{}
+}
+
+// defaultPrintConfig is used when rendering entities not created by a
+// [Parser].
+var defaultPrintConfig = printConfig{wrap: 80}
+
 // Package represents a go package containing functions and types such as
 // structs and interfaces.
 type Package struct {
-	Name   string       `json:"name"`
-	Doc    string       `json:"doc,omitempty"`
-	Consts []ConstGroup `json:"consts,omitempty"`
-	Funcs  []Func       `json:"funcs,omitempty"`
-	Types  []TypeDef    `json:"types,omitempty"`
+	Name     string        `json:"name"`
+	Header   string        `json:"header,omitempty"`
+	Doc      string        `json:"doc,omitempty"`
+	Imports  []string      `json:"imports,omitempty"`
+	Consts   []ConstGroup  `json:"consts,omitempty"`
+	Vars     []VarGroup    `json:"vars,omitempty"`
+	Funcs    []Func        `json:"funcs,omitempty"`
+	Types    []TypeDef     `json:"types,omitempty"`
+	Examples []Example     `json:"examples,omitempty"`
+	Flags    []CommandFlag `json:"flags,omitempty"`
+	printCfg *printConfig
 }
 
 // Source returns the formatted package signature source.
@@ -25,6 +63,18 @@ func (p *Package) Source() (string, error) {
 		return "", fmt.Errorf("formatting source: %w", err)
 	}
 
+	if p.printConfig().compact {
+		if formatted, err = compactTypes(formatted); err != nil {
+			return "", fmt.Errorf("compacting source: %w", err)
+		}
+	}
+
+	if width := p.printConfig().wrapSigs; width > 0 {
+		if formatted, err = wrapParams(formatted, width); err != nil {
+			return "", fmt.Errorf("wrapping signatures: %w", err)
+		}
+	}
+
 	return string(formatted), nil
 }
 
@@ -43,32 +93,261 @@ func (*Package) SymbolType() SymbolType {
 	return SymbolPackage
 }
 
+// IsEmpty returns true if the package has no consts, vars, type definitions,
+// or functions, e.g. because all of its symbols were excluded by filters.
+func (p *Package) IsEmpty() bool {
+	return len(p.Consts) == 0 && len(p.Vars) == 0 && len(p.Types) == 0 && len(p.Funcs) == 0
+}
+
 // Print writes unformatted package code to writer.
 func (p *Package) Print(w io.Writer) {
-	if p.Doc != "" {
-		fmt.Fprint(w, mkComment(p.Doc))
+	cfg := p.printConfig()
+
+	if p.Header != "" {
+		fmt.Fprintf(w, "%s\n\n", p.Header)
+	}
+
+	if doc := docWithExamples(docWithFlags(p.Doc, p.Flags), p.Examples); doc != "" {
+		fmt.Fprint(w, mkComment(doc, cfg.wrap))
 	}
 
 	fmt.Fprintf(w, "package %s", p.Name)
 
-	for _, c := range p.Consts {
-		fmt.Fprint(w, "\n\n")
-		c.Print(w)
+	p.printImports(w)
+
+	if cfg.groupByKind {
+		p.printGrouped(w, cfg)
+		fmt.Fprint(w, "\n")
+
+		return
 	}
 
-	for _, t := range p.Types {
-		fmt.Fprint(w, "\n\n")
-		t.Print(w)
+	var blocks []string
+
+	types, funcs := p.typesAndFuncs(cfg)
+
+	for _, c := range p.constGroups(cfg) {
+		blocks = appendBlock(blocks, c.print, cfg)
 	}
 
-	for _, f := range p.Funcs {
-		fmt.Fprint(w, "\n\n")
-		f.Print(w)
+	for _, v := range p.Vars {
+		blocks = appendBlock(blocks, v.print, cfg)
+	}
+
+	for _, t := range types {
+		blocks = appendBlock(blocks, t.print, cfg)
 	}
 
+	for _, f := range funcs {
+		blocks = appendBlock(blocks, f.print, cfg)
+	}
+
+	printBlocks(w, "", blocks)
+
 	fmt.Fprint(w, "\n")
 }
 
+// printImports writes an import declaration for the package's imports, if
+// any.
+func (p *Package) printImports(w io.Writer) {
+	switch len(p.Imports) {
+	case 0:
+		return
+	case 1:
+		fmt.Fprintf(w, "\n\nimport %q", p.Imports[0])
+		return
+	}
+
+	fmt.Fprint(w, "\n\nimport (\n")
+
+	for _, imp := range p.Imports {
+		fmt.Fprintf(w, "\t%q\n", imp)
+	}
+
+	fmt.Fprint(w, ")")
+}
+
+// printGrouped writes the package's symbols in sections grouped by kind, each
+// preceded by a label comment.
+//
+// Sections without any symbols to print are omitted.
+func (p *Package) printGrouped(w io.Writer, cfg printConfig) {
+	var blocks []string
+
+	for _, c := range p.constGroups(cfg) {
+		blocks = appendBlock(blocks, c.print, cfg)
+	}
+
+	printBlocks(w, kindLabels[SymbolConst], blocks)
+
+	blocks = nil
+
+	for _, v := range p.Vars {
+		blocks = appendBlock(blocks, v.print, cfg)
+	}
+
+	printBlocks(w, kindLabels[SymbolVar], blocks)
+
+	types, funcs := p.typesAndFuncs(cfg)
+
+	for _, st := range kindOrder {
+		blocks = nil
+
+		for _, t := range types {
+			if t.SymbolType() == st {
+				blocks = appendBlock(blocks, t.print, cfg)
+			}
+		}
+
+		for _, f := range funcs {
+			if f.SymbolType() == st {
+				blocks = appendBlock(blocks, f.print, cfg)
+			}
+		}
+
+		printBlocks(w, kindLabels[st], blocks)
+	}
+}
+
+// appendBlock appends the code written by print to blocks, without leading
+// and trailing line breaks, unless print writes no code.
+func appendBlock(blocks []string, print func(io.Writer, printConfig), cfg printConfig) []string {
+	var b strings.Builder
+
+	print(&b, cfg)
+
+	if block := strings.Trim(b.String(), "\n"); block != "" {
+		return append(blocks, block)
+	}
+
+	return blocks
+}
+
+// printBlocks writes blocks of code separated from each other and preceding
+// code by a single blank line, preceded by a label comment if label is not
+// empty.
+func printBlocks(w io.Writer, label string, blocks []string) {
+	if len(blocks) == 0 {
+		return
+	}
+
+	if label != "" {
+		fmt.Fprintf(w, "\n\n// %s", label)
+	}
+
+	for _, block := range blocks {
+		fmt.Fprintf(w, "\n\n%s", block)
+	}
+}
+
+// typesAndFuncs returns the package's type definitions and functions to
+// render according to cfg.
+//
+// If configured, functions without a receiver whose first result is a type
+// definition of the package, or a pointer to one, are considered constructors
+// of the type and rendered after its declaration instead of with the other
+// functions.
+func (p *Package) typesAndFuncs(cfg printConfig) ([]TypeDef, []Func) {
+	if !cfg.groupCtors {
+		return p.Types, p.Funcs
+	}
+
+	types := make([]TypeDef, len(p.Types))
+	index := make(map[string]int, len(p.Types))
+
+	for i, td := range p.Types {
+		td.ctors = nil
+		types[i] = td
+		index[td.Name] = i
+	}
+
+	funcs := make([]Func, 0, len(p.Funcs))
+
+	for _, f := range p.Funcs {
+		if f.Receiver == nil && len(f.Results) != 0 {
+			if i, ok := index[receiverTypeName(f.Results[0].Type)]; ok {
+				types[i].ctors = append(types[i].ctors, f)
+				continue
+			}
+		}
+
+		funcs = append(funcs, f)
+	}
+
+	return types, funcs
+}
+
+// constGroups returns the package's const groups to render according to cfg.
+//
+// If configured, single consts of the same type are coalesced into a group
+// at the position of the first of them, with their doc comments moved onto
+// the consts. As consts are ordered by name, coalescing by type rather than
+// by position is what keeps the output compact.
+func (p *Package) constGroups(cfg printConfig) []ConstGroup {
+	if !cfg.flatConsts {
+		return p.Consts
+	}
+
+	res := make([]ConstGroup, 0, len(p.Consts))
+	groups := make(map[string]int)
+
+	for _, cg := range p.Consts {
+		key := singleConstType(cg)
+
+		i, ok := groups[key]
+		if key == "" || !ok {
+			if key != "" {
+				groups[key] = len(res)
+			}
+
+			res = append(res, cg)
+
+			continue
+		}
+
+		g := &res[i]
+
+		if len(g.Consts) == 1 {
+			first := g.Consts[0]
+			first.Doc = g.Doc
+			g.Consts = []Const{first}
+			g.Doc = ""
+		}
+
+		c := cg.Consts[0]
+		c.Doc = cg.Doc
+		g.Consts = append(g.Consts, c)
+	}
+
+	return res
+}
+
+// singleConstType returns a key identifying the value type of a const group
+// with a single const, or an empty string if the group has multiple consts or
+// the type is unknown.
+func singleConstType(cg ConstGroup) string {
+	if len(cg.Consts) != 1 || len(cg.Consts[0].Values) == 0 {
+		return ""
+	}
+
+	v := cg.Consts[0].Values[0]
+	if v.Type == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/%t", v.Type, v.Specific)
+}
+
+// printConfig returns the package's print configuration, or the default
+// configuration if the package was not created by a [Parser].
+func (p *Package) printConfig() printConfig {
+	if p.printCfg == nil {
+		return defaultPrintConfig
+	}
+
+	return *p.printCfg
+}
+
 // String returns the unformatted package signature code.
 func (p *Package) String() string {
 	var b strings.Builder
@@ -80,18 +359,61 @@ func (p *Package) String() string {
 
 // ConstGroup represents one or more const declarations.
 type ConstGroup struct {
-	Doc    string  `json:"doc,omitempty"`
-	Consts []Const `json:"consts"`
+	Doc      string  `json:"doc,omitempty"`
+	Consts   []Const `json:"consts"`
+	enumType string
+}
+
+// EnumMember represents a member of an enum const group, as returned by
+// [ConstGroup.Enum].
+type EnumMember struct {
+	Name  string
+	Value string
+	Doc   string
+}
+
+// Enum returns the type and members of the const group if it is an
+// iota-style enum, i.e. a group of consts of an explicit type with values
+// derived from iota, such as:
+//
+//	const (
+//		Red Color = iota
+//		Green
+//		Blue
+//	)
+//
+// Member values are evaluated with iota where possible, and empty otherwise.
+// Member docs are the doc comments of the consts, or their line comments if
+// they have no doc comments.
+//
+// Enum returns false if the group is not an enum, or was not created by a
+// [Parser].
+func (cg ConstGroup) Enum() (string, []EnumMember, bool) {
+	if cg.enumType == "" || len(cg.Consts) == 0 {
+		return "", nil, false
+	}
+
+	members := make([]EnumMember, len(cg.Consts))
+
+	for i, c := range cg.Consts {
+		members[i] = EnumMember{Name: c.Ident(), Value: c.enumValue, Doc: c.enumDoc}
+	}
+
+	return cg.enumType, members, true
 }
 
 // Print writes unformatted const declaration code to writer.
 func (cg ConstGroup) Print(w io.Writer) {
+	cg.print(w, defaultPrintConfig)
+}
+
+func (cg ConstGroup) print(w io.Writer, cfg printConfig) {
 	if len(cg.Consts) == 0 {
 		return
 	}
 
 	if cg.Doc != "" {
-		fmt.Fprint(w, mkComment(cg.Doc))
+		fmt.Fprint(w, mkComment(cg.Doc, cfg.wrap))
 	}
 
 	fmt.Fprint(w, "const ")
@@ -103,8 +425,16 @@ func (cg ConstGroup) Print(w io.Writer) {
 
 	fmt.Fprint(w, "(\n")
 
-	for _, c := range cg.Consts {
-		fmt.Fprint(w, "    ")
+	for i, c := range cg.Consts {
+		if c.Doc != "" {
+			if i != 0 {
+				fmt.Fprint(w, "\n")
+			}
+
+			fmt.Fprint(w, indentLines(mkComment(c.Doc, cfg.wrap), "\t"))
+		}
+
+		fmt.Fprint(w, "\t")
 		c.Print(w)
 		fmt.Fprint(w, "\n")
 	}
@@ -123,10 +453,15 @@ func (cg ConstGroup) String() string {
 
 // Const represents a single const declaration.
 type Const struct {
-	valSpec *ast.ValueSpec
-	Doc     string   `json:"doc,omitempty"`
-	Names   []string `json:"names"`
-	Values  []Value  `json:"values"`
+	Doc    string   `json:"doc,omitempty"`
+	Names  []string `json:"names"`
+	Values []Value  `json:"values"`
+	Spec   string   `json:"-"`
+	sig    string   // Spec without comments and with implicit type and values.
+	rawDoc string
+
+	enumValue string // Evaluated value in an enum const group.
+	enumDoc   string // Doc or line comment in an enum const group.
 }
 
 // Ident returns the first name.
@@ -144,9 +479,15 @@ func (Const) SymbolType() SymbolType {
 	return SymbolConst
 }
 
+// FullDoc returns the full doc comments of the const and its const group as
+// written in the source, or Doc if the const was not created by a [Parser].
+func (c Const) FullDoc() string {
+	return fullDoc(c.rawDoc, c.Doc)
+}
+
 // Print writes the unformatted const declaration code fragment to writer.
 func (c Const) Print(w io.Writer) {
-	fmt.Fprint(w, printNodes(c.valSpec))
+	fmt.Fprint(w, c.Spec)
 }
 
 // String returns the unformatted const declaration code fragment.
@@ -158,6 +499,17 @@ func (c Const) String() string {
 	return b.String()
 }
 
+// signature returns the const declaration code fragment without comments and
+// with the type and values implied by a preceding spec in its group, or Spec
+// if the const was not created by a [Parser].
+func (c Const) signature() string {
+	if c.sig == "" {
+		return c.Spec
+	}
+
+	return c.sig
+}
+
 // Value represents a value in a [Const] declaration.
 type Value struct {
 	Value    string `json:"value,omitempty"`
@@ -165,17 +517,157 @@ type Value struct {
 	Specific bool   `json:"specific,omitempty"`
 }
 
+// VarGroup represents one or more var declarations.
+type VarGroup struct {
+	Doc  string `json:"doc,omitempty"`
+	Vars []Var  `json:"vars"`
+}
+
+// Print writes unformatted var declaration code to writer.
+func (vg VarGroup) Print(w io.Writer) {
+	vg.print(w, defaultPrintConfig)
+}
+
+func (vg VarGroup) print(w io.Writer, cfg printConfig) {
+	if len(vg.Vars) == 0 {
+		return
+	}
+
+	if vg.Doc != "" {
+		fmt.Fprint(w, mkComment(vg.Doc, cfg.wrap))
+	}
+
+	if len(vg.Vars) == 1 {
+		vg.Vars[0].printEmbed(w, vg.Doc != "")
+		fmt.Fprint(w, "var ")
+		vg.Vars[0].Print(w)
+
+		return
+	}
+
+	fmt.Fprint(w, "var (\n")
+
+	for i, v := range vg.Vars {
+		if i != 0 && (v.Doc != "" || len(v.Embed) != 0) {
+			fmt.Fprint(w, "\n")
+		}
+
+		if v.Doc != "" {
+			fmt.Fprint(w, indentLines(mkComment(v.Doc, cfg.wrap), "\t"))
+		}
+
+		var b strings.Builder
+
+		v.printEmbed(&b, v.Doc != "")
+		fmt.Fprint(w, indentLines(b.String(), "\t"))
+		fmt.Fprint(w, "\t")
+		v.Print(w)
+		fmt.Fprint(w, "\n")
+	}
+
+	fmt.Fprint(w, ")")
+}
+
+// String returns the unformatted var declaration code.
+func (vg VarGroup) String() string {
+	var b strings.Builder
+
+	vg.Print(&b)
+
+	return b.String()
+}
+
+// Var represents a single var declaration.
+//
+// Embed contains the patterns of the `//go:embed` directives of the var, one
+// entry per directive. Directives of vars declared outside of a var group are
+// only available if the [doc.Package] was created with [doc.PreserveAST].
+type Var struct {
+	Doc    string   `json:"doc,omitempty"`
+	Names  []string `json:"names"`
+	Type   string   `json:"type,omitempty"`
+	Embed  []string `json:"embed,omitempty"`
+	Spec   string   `json:"-"`
+	sig    string   // Spec without comments.
+	rawDoc string
+}
+
+// Ident returns the first name.
+func (v Var) Ident() string {
+	return v.Names[0]
+}
+
+// IsExported returns true if the first name is exported.
+func (v Var) IsExported() bool {
+	return isExportedIdent(v.Names[0])
+}
+
+// SymbolType returns [SymbolVar].
+func (Var) SymbolType() SymbolType {
+	return SymbolVar
+}
+
+// FullDoc returns the full doc comments of the var and its var group as
+// written in the source, or Doc if the var was not created by a [Parser].
+func (v Var) FullDoc() string {
+	return fullDoc(v.rawDoc, v.Doc)
+}
+
+// Print writes the unformatted var declaration code fragment to writer.
+func (v Var) Print(w io.Writer) {
+	fmt.Fprint(w, v.Spec)
+}
+
+// printEmbed writes the `//go:embed` directives of the var, separated from a
+// preceding doc comment by an empty comment line if doc is true.
+func (v Var) printEmbed(w io.Writer, doc bool) {
+	if len(v.Embed) == 0 {
+		return
+	}
+
+	if doc {
+		fmt.Fprint(w, "//\n")
+	}
+
+	for _, pattern := range v.Embed {
+		fmt.Fprintf(w, "//go:embed %s\n", pattern)
+	}
+}
+
+// String returns the unformatted var declaration code fragment.
+func (v Var) String() string {
+	var b strings.Builder
+
+	v.Print(&b)
+
+	return b.String()
+}
+
+// signature returns the var declaration code fragment without comments, or
+// Spec if the var was not created by a [Parser].
+func (v Var) signature() string {
+	if v.sig == "" {
+		return v.Spec
+	}
+
+	return v.sig
+}
+
 // Func represents a function or a struct method if the Receiver field contains
 // a pointer to a [FuncReceiver].
 type Func struct {
-	Receiver   *Field  `json:"receiver,omitempty"`
-	Name       string  `json:"name"`
-	Doc        string  `json:"doc,omitempty"`
-	Comment    string  `json:"comment,omitempty"`
-	Params     []Field `json:"params,omitempty"`
-	Results    []Field `json:"results,omitempty"`
-	funcKw     bool
+	Receiver   *Field       `json:"receiver,omitempty"`
+	Name       string       `json:"name"`
+	Qualified  string       `json:"qualified,omitempty"`
+	Doc        string       `json:"doc,omitempty"`
+	Comment    string       `json:"comment,omitempty"`
+	TypeParams []Field      `json:"typeParams,omitempty"`
+	Params     []Field      `json:"params,omitempty"`
+	Results    []Field      `json:"results,omitempty"`
+	Examples   []Example    `json:"examples,omitempty"`
+	Sections   *DocSections `json:"sections,omitempty"`
 	symbolType SymbolType
+	rawDoc     string
 }
 
 // Ident returns the function's name.
@@ -193,27 +685,68 @@ func (f Func) SymbolType() SymbolType {
 	return f.symbolType
 }
 
+// FullDoc returns the function's full doc comment as written in the source,
+// or Doc if the function was not created by a [Parser].
+func (f Func) FullDoc() string {
+	return fullDoc(f.rawDoc, f.Doc)
+}
+
+// isInterfaceMethod returns true if the function is a method declared in an
+// interface type.
+func (f Func) isInterfaceMethod() bool {
+	return f.symbolType == SymbolMethod && f.Receiver == nil
+}
+
+// ReceiverType returns the base type name of the method's receiver, e.g.
+// `MyStruct` for a `(s *MyStruct)` receiver, or an empty string if the
+// function has no receiver.
+func (f Func) ReceiverType() string {
+	if f.Receiver == nil {
+		return ""
+	}
+
+	return receiverTypeName(f.Receiver.Type)
+}
+
 // Print writes unformatted function signature code to writer.
+//
+// Functions and methods are written with the func keyword, while methods
+// declared in interface types are written as method specifications without
+// it.
 func (f Func) Print(w io.Writer) {
-	if f.Doc != "" {
-		fmt.Fprint(w, mkComment(f.Doc))
+	f.print(w, defaultPrintConfig)
+}
+
+func (f Func) print(w io.Writer, cfg printConfig) {
+	f.printSignature(w, cfg, !f.isInterfaceMethod())
+}
+
+// printSpec writes the function as a method specification of an interface
+// type, without the func keyword.
+func (f Func) printSpec(w io.Writer, cfg printConfig) {
+	f.printSignature(w, cfg, false)
+}
+
+func (f Func) printSignature(w io.Writer, cfg printConfig, funcKw bool) {
+	if doc := docWithExamples(f.Doc, f.Examples); doc != "" {
+		fmt.Fprint(w, mkComment(doc, cfg.wrap))
 	}
 
-	if f.funcKw {
+	if funcKw {
 		fmt.Fprint(w, "func ")
 	}
 
-	if f.Receiver != nil {
+	if f.Receiver != nil && !cfg.noRecvs {
 		fmt.Fprint(w, "(")
-		f.Receiver.Print(w)
+		f.Receiver.print(w, cfg)
 		fmt.Fprint(w, ") ")
 	}
 
-	fmt.Fprintf(w, "%s(%s) %s", f.Name, fieldsList(f.Params), resultsList(f.Results))
+	fmt.Fprintf(w, "%s%s(%s) %s",
+		f.Name, typeParamsList(f.TypeParams, cfg), fieldsList(f.Params, cfg), resultsList(f.Results, cfg),
+	)
 
-	if f.Comment != "" {
-		fmt.Fprintf(w, " // %s", f.Comment)
-	}
+	printInternalComment(w, cfg, lineComment(f.Comment), funcTypes(f)...)
 }
 
 // String returns the function signature code.
@@ -227,18 +760,37 @@ func (f Func) String() string {
 
 // TypeDef represents a type definition.
 type TypeDef struct {
-	Type    string  `json:"type"`
-	Name    string  `json:"name"`
-	Doc     string  `json:"doc,omitempty"`
-	Key     string  `json:"key,omitempty"`
-	Value   string  `json:"value,omitempty"`
-	Dir     string  `json:"dir,omitempty"`
-	Elt     string  `json:"elt,omitempty"`
-	Len     string  `json:"len,omitempty"`
-	Params  []Field `json:"params,omitempty"`
-	Results []Field `json:"results,omitempty"`
-	Fields  []Field `json:"fields,omitempty"`
-	Methods []Func  `json:"methods,omitempty"`
+	Type       string    `json:"type"`
+	Name       string    `json:"name"`
+	Qualified  string    `json:"qualified,omitempty"`
+	Doc        string    `json:"doc,omitempty"`
+	Key        string    `json:"key,omitempty"`
+	Value      string    `json:"value,omitempty"`
+	Dir        string    `json:"dir,omitempty"`
+	Elt        string    `json:"elt,omitempty"`
+	Len        string    `json:"len,omitempty"`
+	Alias      bool      `json:"alias,omitempty"`
+	Underlying string    `json:"underlying,omitempty"`
+	TypeParams []Field   `json:"typeParams,omitempty"`
+	Params     []Field   `json:"params,omitempty"`
+	Results    []Field   `json:"results,omitempty"`
+	Fields     []Field   `json:"fields,omitempty"`
+	Methods    []Func    `json:"methods,omitempty"`
+	Examples   []Example `json:"examples,omitempty"`
+	rawDoc     string
+	ctors      []Func
+
+	// Source forms of types qualified by [WithQualifiedImports].
+	sourceTypes map[string]string
+}
+
+// sourceType returns typ, one of the types of td, as written in the source.
+func (td TypeDef) sourceType(typ string) string {
+	if src, ok := td.sourceTypes[typ]; ok {
+		return src
+	}
+
+	return typ
 }
 
 // Ident returns the type definition's name.
@@ -271,35 +823,64 @@ func (td TypeDef) SymbolType() SymbolType {
 	}
 }
 
+// FullDoc returns the type definition's full doc comment as written in the
+// source, or Doc if the type definition was not created by a [Parser].
+func (td TypeDef) FullDoc() string {
+	return fullDoc(td.rawDoc, td.Doc)
+}
+
 // Print writes unformatted type definition code to writer.
 func (td TypeDef) Print(w io.Writer) {
+	td.print(w, defaultPrintConfig)
+}
+
+func (td TypeDef) print(w io.Writer, cfg printConfig) {
+	td.Doc = docWithExamples(td.Doc, td.Examples)
+
 	switch td.Type {
 	case "struct":
-		printStructType(w, td)
+		printStructType(w, td, cfg)
 	case "interface":
-		printInterfaceType(w, td)
+		printInterfaceType(w, td, cfg)
 	case "func":
-		printFuncType(w, td)
+		printFuncType(w, td, cfg)
 	case "map":
-		printMapType(w, td)
+		printMapType(w, td, cfg)
 	case "chan":
-		printChanType(w, td)
+		printChanType(w, td, cfg)
 	case "array":
-		printArrayType(w, td)
+		printArrayType(w, td, cfg)
 	default:
 		if td.Doc != "" {
-			fmt.Fprint(w, mkComment(td.Doc))
+			fmt.Fprint(w, mkComment(td.Doc, cfg.wrap))
 		}
 
-		fmt.Fprintf(w, "type %s %s", td.Name, td.Type)
+		fmt.Fprintf(w, "type %s %s", td.declName(cfg), cfg.typeString(td.sourceType(td.Type)))
 
-		for _, m := range td.Methods {
-			fmt.Fprint(w, "\n\n")
-			m.Print(w)
+		var comment string
+
+		if cfg.underlying && td.Underlying != "" {
+			comment = "underlying: " + td.Underlying
 		}
+
+		printInternalComment(w, cfg, comment, append(fieldTypes(td.TypeParams), td.Type)...)
+
+		printMethods(w, td.ctors, td.Methods, cfg)
 	}
 }
 
+// declName returns the type definition's name with its type parameters,
+// followed by `=` if the type definition is an alias declaration.
+func (td TypeDef) declName(cfg printConfig) string {
+	name := td.Name + typeParamsList(td.TypeParams, cfg)
+
+	if td.Alias {
+		name += " ="
+	}
+
+	return name
+}
+
 // String returns the type definition code.
 func (td TypeDef) String() string {
 	var b strings.Builder
@@ -309,14 +890,123 @@ func (td TypeDef) String() string {
 	return b.String()
 }
 
-// Field represents a function parameter, result, or struct field.
+// Example represents a testable example function associated with a package,
+// function, type, or method, such as `ExampleMyFunc`.
+type Example struct {
+	Suffix string `json:"suffix,omitempty"`
+	Doc    string `json:"doc,omitempty"`
+	Code   string `json:"code"`
+	Output string `json:"output,omitempty"`
+}
+
+// docWithExamples returns doc followed by a section for each example, with
+// the example's code as an indented code block.
+func docWithExamples(doc string, examples []Example) string {
+	if len(examples) == 0 {
+		return doc
+	}
+
+	var b strings.Builder
+
+	b.WriteString(doc)
+
+	for _, ex := range examples {
+		if b.Len() != 0 {
+			b.WriteString("\n\n")
+		}
+
+		if ex.Suffix != "" {
+			fmt.Fprintf(&b, "Example (%s):\n\n", ex.Suffix)
+		} else {
+			b.WriteString("Example:\n\n")
+		}
+
+		if ex.Doc != "" {
+			fmt.Fprintf(&b, "%s\n\n", ex.Doc)
+		}
+
+		b.WriteString(indentLines(ex.Code, "\t"))
+	}
+
+	return b.String()
+}
+
+// CommandFlag represents a command-line flag defined by a main package with
+// the standard library flag package.
+//
+// Default is the source code of the flag's default value expression, if any.
+type CommandFlag struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Default string `json:"default,omitempty"`
+	Usage   string `json:"usage,omitempty"`
+}
+
+// docWithFlags returns doc followed by a section listing flags in the style
+// of [flag.PrintDefaults].
+func docWithFlags(doc string, flags []CommandFlag) string {
+	if len(flags) == 0 {
+		return doc
+	}
+
+	var b strings.Builder
+
+	b.WriteString(doc)
+
+	if b.Len() != 0 {
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString("Flags:\n")
+
+	for _, f := range flags {
+		fmt.Fprintf(&b, "\n\t-%s", f.Name)
+
+		if f.Type != "bool" {
+			fmt.Fprintf(&b, " %s", f.Type)
+		}
+
+		usage := f.Usage
+
+		switch f.Default {
+		case "", `""`, "0", "false":
+		default:
+			usage = strings.TrimSpace(fmt.Sprintf("%s (default %s)", usage, f.Default))
+		}
+
+		if usage != "" {
+			fmt.Fprintf(&b, "\n\t    %s", usage)
+		}
+	}
+
+	return b.String()
+}
+
+// Field represents a function parameter, result, type parameter, or struct
+// field.
+//
+// If the field's type is an inline anonymous struct or interface, Fields and
+// Methods contain its structured shape.
 type Field struct {
 	Type       string     `json:"type"`
 	Doc        string     `json:"doc,omitempty"`
 	Comment    string     `json:"comment,omitempty"`
 	Names      []string   `json:"names,omitempty"`
 	Tags       []FieldTag `json:"tags,omitempty"`
+	Fields     []Field    `json:"fields,omitempty"`
+	Methods    []Func     `json:"methods,omitempty"`
 	symbolType SymbolType
+	filterable bool
+	srcType    string // Source form of Type if qualified by [WithQualifiedImports].
+}
+
+// sourceType returns the field's type as written in the source.
+func (sf Field) sourceType() string {
+	if sf.srcType != "" {
+		return sf.srcType
+	}
+
+	return sf.Type
 }
 
 // Ident returns the name of the field.
@@ -329,23 +1019,37 @@ func (sf Field) Ident() string {
 }
 
 // IsExported returns true if the field is exported.
+//
+// Embedded fields are exported if their type name is exported.
 func (sf Field) IsExported() bool {
+	if len(sf.Names) == 0 {
+		return isExportedIdent(embeddedFieldName(sf.Type))
+	}
+
 	return isExportedIdent(sf.Names[0])
 }
 
-// SymbolType returns either [SymbolStructField], [SymbolParamField], or
-// [SymbolResultField].
+// SymbolType returns either [SymbolStructField], [SymbolParamField],
+// [SymbolResultField], [SymbolReceiverField], or [SymbolTypeParamField].
 func (sf Field) SymbolType() SymbolType {
 	return sf.symbolType
 }
 
 // Print writes unformatted field code fragment to writer.
 func (sf Field) Print(w io.Writer) {
+	sf.print(w, defaultPrintConfig)
+}
+
+func (sf Field) print(w io.Writer, cfg printConfig) {
 	if sf.Doc != "" {
-		fmt.Fprint(w, mkComment(sf.Doc))
+		fmt.Fprint(w, mkComment(sf.Doc, cfg.wrap))
 	}
 
-	fmt.Fprintf(w, "%s %s", strings.Join(sf.Names, ", "), sf.Type)
+	if len(sf.Names) == 0 || (sf.symbolType == SymbolReceiverField && cfg.noRecvNames) {
+		fmt.Fprint(w, cfg.typeString(sf.sourceType()))
+	} else {
+		fmt.Fprintf(w, "%s %s", strings.Join(sf.Names, ", "), cfg.typeString(sf.sourceType()))
+	}
 
 	if sf.symbolType == SymbolStructField && len(sf.Tags) != 0 {
 		fmt.Fprint(w, " `")
@@ -361,11 +1065,36 @@ func (sf Field) Print(w io.Writer) {
 		fmt.Fprint(w, "`")
 	}
 
-	if sf.Comment != "" {
-		fmt.Fprintf(w, " // %s", sf.Comment)
+	comment := lineComment(sf.Comment)
+
+	if sf.symbolType == SymbolStructField && cfg.zeroValues && len(sf.Names) != 0 {
+		comment = zeroValueComment(comment, sf.sourceType())
+	}
+
+	if sf.symbolType == SymbolStructField {
+		comment = cfg.internalComment(comment, sf.Type)
+	}
+
+	if comment != "" {
+		fmt.Fprintf(w, " // %s", comment)
 	}
 }
 
+// zeroValueComment returns comment annotated with the zero value of type typ,
+// or comment unchanged if the zero value cannot be inferred.
+func zeroValueComment(comment, typ string) string {
+	zero, ok := zeroValue(typ)
+	if !ok {
+		return comment
+	}
+
+	if comment == "" {
+		return "zero: " + zero
+	}
+
+	return fmt.Sprintf("%s (zero: %s)", comment, zero)
+}
+
 // String returns the unformatted field code fragment.
 func (sf Field) String() string {
 	var b strings.Builder
@@ -395,82 +1124,234 @@ func (ft FieldTag) String() string {
 	return b.String()
 }
 
-func printStructType(w io.Writer, s TypeDef) {
+func printStructType(w io.Writer, s TypeDef, cfg printConfig) {
 	if s.Doc != "" {
-		fmt.Fprint(w, mkComment(s.Doc))
+		fmt.Fprint(w, mkComment(s.Doc, cfg.wrap))
+	}
+
+	if cfg.noEmpty && len(s.Fields) == 0 {
+		fmt.Fprintf(w, "type %s struct{}", s.declName(cfg))
+		printMethods(w, s.ctors, s.Methods, cfg)
+
+		return
+	}
+
+	// Compact bodies have no comments, as a line comment would swallow the
+	// rest of the body.
+	if cfg.compact {
+		fields := make([]string, len(s.Fields))
+
+		for i, f := range s.Fields {
+			f.Doc, f.Comment = "", ""
+			fields[i] = f.String()
+		}
+
+		fmt.Fprintf(w, "type %s struct { %s }", s.declName(cfg), strings.Join(fields, "; "))
+		printMethods(w, s.ctors, s.Methods, cfg)
+
+		return
 	}
 
-	fmt.Fprintf(w, "type %s struct {", s.Name)
+	fmt.Fprintf(w, "type %s struct {", s.declName(cfg))
 
 	if len(s.Fields) != 0 {
 		fmt.Fprint(w, "\n")
 
 		for _, f := range s.Fields {
-			f.Print(w)
+			f.print(w, cfg)
 			fmt.Fprint(w, "\n")
 		}
 	}
 
 	fmt.Fprint(w, "}")
+	printMethods(w, s.ctors, s.Methods, cfg)
+}
 
-	if len(s.Methods) == 0 {
-		return
+// printMethods writes constructor functions of a type followed by methods
+// declared on it, truncated to the maximum number of methods in cfg.
+func printMethods(w io.Writer, ctors, methods []Func, cfg printConfig) {
+	shown, more := limitMethods(methods, cfg)
+
+	printFuncs(w, ctors, cfg)
+	printFuncs(w, shown, cfg)
+
+	if more != "" {
+		fmt.Fprintf(w, "\n\n%s", more)
 	}
+}
 
-	for _, fn := range s.Methods {
+// printFuncs writes functions, each separated from preceding code by a blank
+// line.
+func printFuncs(w io.Writer, fns []Func, cfg printConfig) {
+	for _, f := range fns {
 		fmt.Fprint(w, "\n\n")
-		fn.Print(w)
+		f.print(w, cfg)
+	}
+}
+
+// limitMethods returns the methods to print according to the maximum number
+// of methods in cfg, and a comment about the omitted methods, if any.
+func limitMethods(methods []Func, cfg printConfig) ([]Func, string) {
+	if cfg.maxMethods == 0 || len(methods) <= cfg.maxMethods {
+		return methods, ""
+	}
+
+	n := len(methods) - cfg.maxMethods
+	noun := "methods"
+
+	if n == 1 {
+		noun = "method"
+	}
+
+	return methods[:cfg.maxMethods], fmt.Sprintf("// ... and %d more %s", n, noun)
+}
+
+// interfaceMethods returns the methods of interface type iface to print,
+// sorted by name and with doc comments replaced by trailing synopsis comments
+// if configured in cfg.
+func interfaceMethods(iface TypeDef, cfg printConfig) []Func {
+	if !cfg.methodDocs && !cfg.sortMethods {
+		return iface.Methods
+	}
+
+	methods := append([]Func(nil), iface.Methods...)
+
+	if cfg.sortMethods {
+		sort.SliceStable(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+	}
+
+	if cfg.methodDocs {
+		for i, m := range methods {
+			if m.Comment == "" {
+				methods[i].Comment = (&doc.Package{}).Synopsis(m.Doc)
+			}
+
+			methods[i].Doc = ""
+		}
 	}
+
+	return methods
 }
 
-func printInterfaceType(w io.Writer, iface TypeDef) {
+func printInterfaceType(w io.Writer, iface TypeDef, cfg printConfig) {
 	if iface.Doc != "" {
-		fmt.Fprint(w, mkComment(iface.Doc))
+		fmt.Fprint(w, mkComment(iface.Doc, cfg.wrap))
+	}
+
+	iface.Methods = interfaceMethods(iface, cfg)
+
+	if cfg.noEmpty && len(iface.Methods) == 0 && len(iface.Fields) == 0 {
+		fmt.Fprintf(w, "type %s interface{}", iface.declName(cfg))
+		printFuncs(w, iface.ctors, cfg)
+
+		return
+	}
+
+	if cfg.compact {
+		methods, more := limitMethods(iface.Methods, cfg)
+		sigs := make([]string, 0, len(iface.Fields)+len(methods))
+
+		for _, f := range iface.Fields {
+			sigs = append(sigs, cfg.typeString(f.sourceType()))
+		}
+
+		for _, m := range methods {
+			var b strings.Builder
+
+			m.Doc, m.Comment = "", ""
+			m.printSpec(&b, cfg)
+			sigs = append(sigs, b.String())
+		}
+
+		fmt.Fprintf(w, "type %s interface { %s }", iface.declName(cfg), strings.Join(sigs, "; "))
+
+		if more != "" {
+			fmt.Fprintf(w, " %s", more)
+		}
+
+		printFuncs(w, iface.ctors, cfg)
+
+		return
 	}
 
-	fmt.Fprintf(w, "type %s interface {", iface.Name)
+	fmt.Fprintf(w, "type %s interface {", iface.declName(cfg))
 
-	if len(iface.Methods) != 0 {
+	if len(iface.Fields) != 0 || len(iface.Methods) != 0 {
 		fmt.Fprint(w, "\n")
 
-		for _, m := range iface.Methods {
-			fmt.Fprintf(w, "    %s\n", m)
+		// Embedded interfaces are written before methods.
+		for _, f := range iface.Fields {
+			fmt.Fprint(w, "\t")
+			f.print(w, cfg)
+			fmt.Fprint(w, "\n")
+		}
+
+		methods, more := limitMethods(iface.Methods, cfg)
+
+		for i, m := range methods {
+			// Documented methods are separated from preceding methods by a
+			// blank line with their doc comment on the lines above them.
+			if m.Doc != "" {
+				if i != 0 || len(iface.Fields) != 0 {
+					fmt.Fprint(w, "\n")
+				}
+
+				fmt.Fprint(w, indentLines(mkComment(m.Doc, cfg.wrap), "\t"))
+				m.Doc = ""
+			}
+
+			fmt.Fprint(w, "\t")
+			m.printSpec(w, cfg)
+			fmt.Fprint(w, "\n")
+		}
+
+		if more != "" {
+			fmt.Fprintf(w, "\t%s\n", more)
 		}
 	}
 
 	fmt.Fprint(w, "}")
+	printFuncs(w, iface.ctors, cfg)
 }
 
-func printFuncType(w io.Writer, f TypeDef) {
+// printInternalComment writes line comment text comment, with
+// [internalTypeNote] added if any of typs refers to an internal type of cfg.
+func printInternalComment(w io.Writer, cfg printConfig, comment string, typs ...string) {
+	if comment = cfg.internalComment(comment, typs...); comment != "" {
+		fmt.Fprintf(w, " // %s", comment)
+	}
+}
+
+func printFuncType(w io.Writer, f TypeDef, cfg printConfig) {
 	if f.Doc != "" {
-		fmt.Fprint(w, mkComment(f.Doc))
+		fmt.Fprint(w, mkComment(f.Doc, cfg.wrap))
 	}
 
-	fmt.Fprintf(w, "type %s func(%s) %s", f.Name, fieldsList(f.Params), resultsList(f.Results))
+	fmt.Fprintf(w, "type %s func(%s) %s", f.declName(cfg), fieldsList(f.Params, cfg), resultsList(f.Results, cfg))
+	typs := append(fieldTypes(f.TypeParams), fieldTypes(f.Params)...)
+	printInternalComment(w, cfg, "", append(typs, fieldTypes(f.Results)...)...)
+	printFuncs(w, f.ctors, cfg)
 }
 
-func printMapType(w io.Writer, mt TypeDef) {
+func printMapType(w io.Writer, mt TypeDef, cfg printConfig) {
 	if mt.Doc != "" {
-		fmt.Fprint(w, mkComment(mt.Doc))
+		fmt.Fprint(w, mkComment(mt.Doc, cfg.wrap))
 	}
 
-	fmt.Fprintf(w, "type %s map[%s]%s", mt.Name, mt.Key, mt.Value)
-
-	if len(mt.Methods) == 0 {
-		return
-	}
+	fmt.Fprintf(w, "type %s map[%s]%s", mt.declName(cfg),
+		cfg.typeString(mt.sourceType(mt.Key)), cfg.typeString(mt.sourceType(mt.Value)),
+	)
+	printInternalComment(w, cfg, "", append(fieldTypes(mt.TypeParams), mt.Key, mt.Value)...)
 
-	for _, m := range mt.Methods {
-		fmt.Printf("\n\n%s", m)
-	}
+	printMethods(w, mt.ctors, mt.Methods, cfg)
 }
 
-func printChanType(w io.Writer, ch TypeDef) {
+func printChanType(w io.Writer, ch TypeDef, cfg printConfig) {
 	if ch.Doc != "" {
-		fmt.Fprint(w, mkComment(ch.Doc))
+		fmt.Fprint(w, mkComment(ch.Doc, cfg.wrap))
 	}
 
-	fmt.Fprintf(w, "type %s ", ch.Name)
+	fmt.Fprintf(w, "type %s ", ch.declName(cfg))
 
 	switch ch.Dir {
 	case "recv":
@@ -481,21 +1362,18 @@ func printChanType(w io.Writer, ch TypeDef) {
 		fmt.Fprint(w, "chan ")
 	}
 
-	fmt.Fprint(w, ch.Value)
+	fmt.Fprint(w, cfg.typeString(ch.sourceType(ch.Value)))
+	printInternalComment(w, cfg, "", append(fieldTypes(ch.TypeParams), ch.Value)...)
+	printFuncs(w, ch.ctors, cfg)
 }
 
-func printArrayType(w io.Writer, a TypeDef) {
+func printArrayType(w io.Writer, a TypeDef, cfg printConfig) {
 	if a.Doc != "" {
-		fmt.Fprint(w, mkComment(a.Doc))
+		fmt.Fprint(w, mkComment(a.Doc, cfg.wrap))
 	}
 
-	fmt.Fprintf(w, "type %s [%s]%s", a.Name, a.Len, a.Elt)
+	fmt.Fprintf(w, "type %s [%s]%s", a.declName(cfg), a.Len, cfg.typeString(a.sourceType(a.Elt)))
+	printInternalComment(w, cfg, "", append(fieldTypes(a.TypeParams), a.Elt)...)
 
-	if len(a.Methods) == 0 {
-		return
-	}
-
-	for _, m := range a.Methods {
-		fmt.Printf("\n\n%s", m)
-	}
+	printMethods(w, a.ctors, a.Methods, cfg)
 }
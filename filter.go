@@ -31,31 +31,34 @@ func (fa FilterAction) GoString() string {
 type SymbolType int
 
 const (
-	SymbolUnknown       SymbolType = iota
-	SymbolPackage                  // `package mypackage`
-	SymbolConst                    // `const myConst = ...`
-	SymbolIdentType                // `type MyInt int`
-	SymbolFuncType                 // `type MyFunc func(...)`
-	SymbolStructType               // `type MyStruct { ... }`
-	SymbolInterfaceType            // `type MyInterface { ... }`
-	SymbolMapType                  // `type MyMap map[...]...`
-	SymbolChanType                 // `type MyChan chan ...`
-	SymbolArrayType                // `type MyArray []string`
-	SymbolFunc                     // `func MyFunc(...) { ... }`
-	SymbolMethod                   // `func (...) MyMethod(...) { ... }`
-	SymbolStructField              // Struct field.
-	SymbolParamField               // Function parameter field.
-	SymbolResultField              // Function result field.
-	SymbolReceiverField            // Function Receiver field.
+	SymbolUnknown        SymbolType = iota
+	SymbolPackage                   // `package mypackage`
+	SymbolConst                     // `const myConst = ...`
+	SymbolIdentType                 // `type MyInt int`
+	SymbolFuncType                  // `type MyFunc func(...)`
+	SymbolStructType                // `type MyStruct { ... }`
+	SymbolInterfaceType             // `type MyInterface { ... }`
+	SymbolMapType                   // `type MyMap map[...]...`
+	SymbolChanType                  // `type MyChan chan ...`
+	SymbolArrayType                 // `type MyArray []string`
+	SymbolFunc                      // `func MyFunc(...) { ... }`
+	SymbolMethod                    // `func (...) MyMethod(...) { ... }`
+	SymbolStructField               // Struct field.
+	SymbolParamField                // Function parameter field.
+	SymbolResultField               // Function result field.
+	SymbolReceiverField             // Function Receiver field.
+	SymbolTypeParamField            // Type parameter field.
+	SymbolVar                       // `var myVar = ...`
 )
 
 // unfilterableMap contains symbol types that filter functions should always
 // return true for.
 var unfilterableMap = map[SymbolType]struct{}{
-	SymbolPackage:       {},
-	SymbolParamField:    {},
-	SymbolResultField:   {},
-	SymbolReceiverField: {},
+	SymbolPackage:        {},
+	SymbolParamField:     {},
+	SymbolResultField:    {},
+	SymbolReceiverField:  {},
+	SymbolTypeParamField: {},
 }
 
 // String returns a string representation of a symbol type.
@@ -77,6 +80,8 @@ func (st SymbolType) String() string {
 		"SymbolParamField",
 		"SymbolResultField",
 		"SymbolReceiverField",
+		"SymbolTypeParamField",
+		"SymbolVar",
 	}[st]
 }
 
@@ -103,6 +108,13 @@ type SymbolFilter interface {
 	String() string
 }
 
+// FilterResult is the result of applying the symbol filters of a [Parser] to
+// a symbol.
+type FilterResult struct {
+	Symbol   Symbol
+	Included bool
+}
+
 // FilterUnexported creates a filter that determines whether to include or
 // exclude unexported symbols.
 func FilterUnexported(action FilterAction) SymbolFilter {
@@ -125,6 +137,63 @@ func (f *filterUnexported) String() string {
 	return fmt.Sprintf("filterUnexported(action=%s)", f.action)
 }
 
+// FilterUnexportedForTypes creates a filter that determines whether to
+// include or exclude unexported symbols, scoped to symbols of the provided
+// types.
+//
+// With the [Exclude] action, unexported symbols of the provided types are
+// excluded. With the [Include] action, unexported symbols are only included
+// if they are of the provided types, so symbols of any other type must be
+// exported.
+//
+// Example:
+//
+//	// Include unexported functions and methods, but only exported types.
+//	pkgdmp.FilterUnexportedForTypes(pkgdmp.Include, pkgdmp.SymbolFunc, pkgdmp.SymbolMethod)
+func FilterUnexportedForTypes(action FilterAction, types ...SymbolType) SymbolFilter {
+	stMap := make(map[SymbolType]struct{}, len(types))
+
+	for _, t := range types {
+		stMap[t] = struct{}{}
+	}
+
+	return &filterUnexportedForTypes{
+		stMap:  stMap,
+		action: action,
+	}
+}
+
+type filterUnexportedForTypes struct {
+	stMap  map[SymbolType]struct{}
+	action FilterAction
+}
+
+func (f *filterUnexportedForTypes) Include(s Symbol) bool {
+	if isUnfilterable(s) || s.IsExported() {
+		return true
+	}
+
+	_, ok := f.stMap[s.SymbolType()]
+
+	if f.action == Include {
+		return ok
+	}
+
+	return !ok
+}
+
+func (f *filterUnexportedForTypes) String() string {
+	sts := make([]string, 0, len(f.stMap))
+
+	for st := range f.stMap {
+		sts = append(sts, st.String())
+	}
+
+	sort.Strings(sts)
+
+	return fmt.Sprintf("filterUnexportedForTypes(action=%s,symbolTypes=%s)", f.action, strings.Join(sts, ","))
+}
+
 // FilterSymbolTypes creates a filter function that determines whether to
 // include or exclude symbols of different types.
 func FilterSymbolTypes(action FilterAction, types ...SymbolType) SymbolFilter {
@@ -187,7 +256,7 @@ type filterMatchingIdents struct {
 }
 
 func (f *filterMatchingIdents) Include(s Symbol) bool {
-	if isUnfilterable(s) {
+	if isUnfilterable(s) && !isIdentFilterable(s) {
 		return true
 	}
 
@@ -208,6 +277,350 @@ func (f *filterMatchingIdents) String() string {
 	return fmt.Sprintf("filterMatchingIdents(action=%s,pattern=%s)", f.action, f.pattern)
 }
 
+// FilterReceiver creates a filter that determines whether to include or
+// exclude methods with a receiver base type name matching a regular
+// expression.
+//
+// Symbols other than methods with a receiver are always included.
+func FilterReceiver(action FilterAction, p *regexp.Regexp) SymbolFilter {
+	return &filterReceiver{action: action, pattern: p}
+}
+
+type filterReceiver struct {
+	pattern *regexp.Regexp
+	action  FilterAction
+}
+
+func (f *filterReceiver) Include(s Symbol) bool {
+	if s.SymbolType() != SymbolMethod {
+		return true
+	}
+
+	rs, ok := s.(interface{ ReceiverType() string })
+	if !ok || rs.ReceiverType() == "" {
+		return true
+	}
+
+	match := f.pattern.MatchString(rs.ReceiverType())
+
+	if f.action == Include {
+		return match
+	}
+
+	return !match
+}
+
+func (f *filterReceiver) String() string {
+	return fmt.Sprintf("filterReceiver(action=%s,pattern=%s)", f.action, f.pattern)
+}
+
+// FilterMatchingTypes creates a filter that determines whether to include or
+// exclude symbols with type strings matching a regular expression.
+//
+// Struct fields are matched by their type, e.g. `*sql.DB`, while functions and
+// methods are matched by their full signature without doc comments, e.g.
+// `MyFunc(ctx context.Context) error`. Other symbols are always included.
+func FilterMatchingTypes(action FilterAction, p *regexp.Regexp) SymbolFilter {
+	return &filterMatchingTypes{action: action, pattern: p}
+}
+
+type filterMatchingTypes struct {
+	pattern *regexp.Regexp
+	action  FilterAction
+}
+
+func (f *filterMatchingTypes) Include(s Symbol) bool {
+	var match bool
+
+	switch ts := s.(type) {
+	case Field:
+		if ts.symbolType != SymbolStructField {
+			return true
+		}
+
+		match = f.pattern.MatchString(ts.Type)
+	case Func:
+		match = f.pattern.MatchString(funcSignature(ts))
+	default:
+		return true
+	}
+
+	if f.action == Include {
+		return match
+	}
+
+	return !match
+}
+
+func (f *filterMatchingTypes) String() string {
+	return fmt.Sprintf("filterMatchingTypes(action=%s,pattern=%s)", f.action, f.pattern)
+}
+
+// funcSignature returns the signature code of fn without doc and line
+// comments, with runs of whitespace collapsed to single spaces.
+func funcSignature(fn Func) string {
+	var b strings.Builder
+
+	fn.Doc, fn.Comment, fn.Examples = "", "", nil
+	fn.printSpec(&b, defaultPrintConfig)
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// FilterDocMarker creates a filter that determines whether to include or
+// exclude symbols with a doc comment containing a line starting with marker,
+// such as `Stable:` or `Experimental:`.
+//
+// Consts match the doc comments of both the const and its const group. Symbols
+// other than consts, type definitions, functions, and methods are always
+// included.
+func FilterDocMarker(action FilterAction, marker string) SymbolFilter {
+	marker = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(marker), "//"))
+
+	return &filterDocMarker{action: action, marker: marker}
+}
+
+type filterDocMarker struct {
+	marker string
+	action FilterAction
+}
+
+func (f *filterDocMarker) Include(s Symbol) bool {
+	ds, ok := s.(interface{ FullDoc() string })
+	if !ok {
+		return true
+	}
+
+	match := hasDocMarker(ds.FullDoc(), f.marker)
+
+	if f.action == Include {
+		return match
+	}
+
+	return !match
+}
+
+func (f *filterDocMarker) String() string {
+	return fmt.Sprintf("filterDocMarker(action=%s,marker=%s)", f.action, f.marker)
+}
+
+// hasDocMarker returns true if a line of doc starts with marker.
+func hasDocMarker(doc, marker string) bool {
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "//"))
+
+		if strings.HasPrefix(line, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ArityRange is an inclusive range of numbers of function parameters or
+// results.
+//
+// A negative Max means there is no upper limit.
+type ArityRange struct {
+	Min int
+	Max int
+}
+
+// AnyArity is an arity range matching any number of parameters or results.
+var AnyArity = ArityRange{Min: 0, Max: -1}
+
+// Contains returns true if n is within the range.
+func (r ArityRange) Contains(n int) bool {
+	return n >= r.Min && (r.Max < 0 || n <= r.Max)
+}
+
+// String returns a string representation of the range, e.g. `1..3` or `2..`
+// if there is no upper limit.
+func (r ArityRange) String() string {
+	if r.Max < 0 {
+		return fmt.Sprintf("%d..", r.Min)
+	}
+
+	return fmt.Sprintf("%d..%d", r.Min, r.Max)
+}
+
+// FilterArity creates a filter that determines whether to include or exclude
+// functions and methods with numbers of parameters and results within ranges.
+//
+// Parameters and results are counted by name, so `func(a, b int)` has two
+// parameters. Symbols other than functions and methods are always included.
+func FilterArity(action FilterAction, params, results ArityRange) SymbolFilter {
+	return &filterArity{action: action, params: params, results: results}
+}
+
+type filterArity struct {
+	params  ArityRange
+	results ArityRange
+	action  FilterAction
+}
+
+func (f *filterArity) Include(s Symbol) bool {
+	var match bool
+
+	switch fn := s.(type) {
+	case Func:
+		match = f.params.Contains(fieldCount(fn.Params)) && f.results.Contains(fieldCount(fn.Results))
+	default:
+		return true
+	}
+
+	if f.action == Include {
+		return match
+	}
+
+	return !match
+}
+
+func (f *filterArity) String() string {
+	return fmt.Sprintf("filterArity(action=%s,params=%s,results=%s)", f.action, f.params, f.results)
+}
+
+// fieldCount returns the number of names in fields, counting unnamed fields as
+// one.
+func fieldCount(fields []Field) int {
+	n := 0
+
+	for _, f := range fields {
+		if len(f.Names) == 0 {
+			n++
+			continue
+		}
+
+		n += len(f.Names)
+	}
+
+	return n
+}
+
+// FilterNamePredicate creates a filter that determines whether to include or
+// exclude symbols with names satisfying a predicate function.
+func FilterNamePredicate(action FilterAction, pred func(string) bool) SymbolFilter {
+	return &filterNamePredicate{action: action, pred: pred}
+}
+
+type filterNamePredicate struct {
+	pred   func(string) bool
+	action FilterAction
+}
+
+func (f *filterNamePredicate) Include(s Symbol) bool {
+	if isUnfilterable(s) && !isIdentFilterable(s) {
+		return true
+	}
+
+	if s.SymbolType() == SymbolStructField {
+		return true
+	}
+
+	match := f.pred(s.Ident())
+
+	if f.action == Include {
+		return match
+	}
+
+	return !match
+}
+
+func (f *filterNamePredicate) String() string {
+	return fmt.Sprintf("filterNamePredicate(action=%s)", f.action)
+}
+
+// FilterChain is a named set of symbol filters that is itself a
+// [SymbolFilter], which includes symbols included by all of its filters.
+//
+// Chains can be nested to compose reusable filter policies:
+//
+//	publicAPI := pkgdmp.NewFilterChain("publicAPI",
+//		pkgdmp.FilterUnexported(pkgdmp.Exclude),
+//	)
+//	stableAPI := pkgdmp.NewFilterChain("stableAPI", publicAPI).
+//		Add(pkgdmp.FilterDocMarker(pkgdmp.Include, "Stable:"))
+//
+//	parser, err := pkgdmp.NewParser(pkgdmp.WithSymbolFilters(stableAPI))
+type FilterChain struct {
+	name    string
+	filters []SymbolFilter
+}
+
+// NewFilterChain returns a new filter chain with name and filters.
+func NewFilterChain(name string, filters ...SymbolFilter) *FilterChain {
+	return &FilterChain{name: name, filters: filters}
+}
+
+// Name returns the name of the chain.
+func (fc *FilterChain) Name() string {
+	return fc.name
+}
+
+// Add appends filters to the chain and returns the chain.
+func (fc *FilterChain) Add(filters ...SymbolFilter) *FilterChain {
+	fc.filters = append(fc.filters, filters...)
+	return fc
+}
+
+// Include returns true if all filters in the chain include s, or if the
+// chain has no filters.
+func (fc *FilterChain) Include(s Symbol) bool {
+	for _, f := range fc.filters {
+		if !f.Include(s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns a string representation of the chain and its filters.
+func (fc *FilterChain) String() string {
+	filters := make([]string, 0, len(fc.filters))
+
+	for _, f := range fc.filters {
+		filters = append(filters, f.String())
+	}
+
+	return fmt.Sprintf("filterChain(name=%s,filters=%s)", fc.name, strings.Join(filters, ","))
+}
+
+// FilterNot creates a filter that includes symbols excluded by f, and
+// excludes symbols included by f.
+//
+// Symbols that are never filtered, such as packages, are always included.
+// Note that filters include symbols they do not apply to, e.g.
+// [FilterReceiver] includes functions without a receiver, so negating such a
+// filter excludes them.
+func FilterNot(f SymbolFilter) SymbolFilter {
+	return &filterNot{f: f}
+}
+
+type filterNot struct {
+	f SymbolFilter
+}
+
+func (f *filterNot) Include(s Symbol) bool {
+	if isUnfilterable(s) {
+		return true
+	}
+
+	return !f.f.Include(s)
+}
+
+func (f *filterNot) String() string {
+	return fmt.Sprintf("not(%s)", f.f)
+}
+
+// isIdentFilterable returns true if s is a param or result field made
+// subject to ident filters with [WithIncludeUnfilterable].
+func isIdentFilterable(s Symbol) bool {
+	f, ok := s.(Field)
+
+	return ok && f.filterable
+}
+
 func isUnfilterable(s Symbol) bool {
 	if _, ok := unfilterableMap[s.SymbolType()]; ok {
 		return true
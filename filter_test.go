@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/rand"
 	"regexp"
+	"sort"
 	"strings"
 	"testing"
 
@@ -21,11 +22,14 @@ var symbolTypes = []pkgdmp.SymbolType{
 	pkgdmp.SymbolArrayType,
 	pkgdmp.SymbolFunc,
 	pkgdmp.SymbolMethod,
+	pkgdmp.SymbolVar,
 }
 
 func TestFilterUnexported(t *testing.T) {
 	exported := newSymbol(t, "MyExported", randSymbolType(t))
 	unexported := newSymbol(t, "myUnexported", randSymbolType(t))
+	exportedVar := newSymbol(t, "MyExported", pkgdmp.SymbolVar)
+	unexportedVar := newSymbol(t, "myUnexported", pkgdmp.SymbolVar)
 	exportedSField := newSymbol(t, "MyExported", pkgdmp.SymbolStructField)
 	unexportedSField := newSymbol(t, "myUnexported", pkgdmp.SymbolStructField)
 
@@ -38,6 +42,10 @@ func TestFilterUnexported(t *testing.T) {
 		{exported, pkgdmp.Exclude, true},
 		{unexported, pkgdmp.Include, true},
 		{unexported, pkgdmp.Exclude, false},
+		{exportedVar, pkgdmp.Include, true},
+		{exportedVar, pkgdmp.Exclude, true},
+		{unexportedVar, pkgdmp.Include, true},
+		{unexportedVar, pkgdmp.Exclude, false},
 		{exportedSField, pkgdmp.Include, true},
 		{exportedSField, pkgdmp.Exclude, true},
 		{unexportedSField, pkgdmp.Include, true},
@@ -67,6 +75,51 @@ func TestFilterUnexported(t *testing.T) {
 	}
 }
 
+func TestFilterUnexportedForTypes(t *testing.T) {
+	scoped := []pkgdmp.SymbolType{pkgdmp.SymbolFunc, pkgdmp.SymbolMethod}
+
+	tt := []struct {
+		s      pkgdmp.Symbol
+		action pkgdmp.FilterAction
+		want   bool
+	}{
+		{newSymbol(t, "MyFunc", pkgdmp.SymbolFunc), pkgdmp.Include, true},
+		{newSymbol(t, "MyFunc", pkgdmp.SymbolFunc), pkgdmp.Exclude, true},
+		{newSymbol(t, "myFunc", pkgdmp.SymbolFunc), pkgdmp.Include, true},
+		{newSymbol(t, "myFunc", pkgdmp.SymbolFunc), pkgdmp.Exclude, false},
+		{newSymbol(t, "myMethod", pkgdmp.SymbolMethod), pkgdmp.Include, true},
+		{newSymbol(t, "myMethod", pkgdmp.SymbolMethod), pkgdmp.Exclude, false},
+		{newSymbol(t, "MyStruct", pkgdmp.SymbolStructType), pkgdmp.Include, true},
+		{newSymbol(t, "MyStruct", pkgdmp.SymbolStructType), pkgdmp.Exclude, true},
+		{newSymbol(t, "myStruct", pkgdmp.SymbolStructType), pkgdmp.Include, false},
+		{newSymbol(t, "myStruct", pkgdmp.SymbolStructType), pkgdmp.Exclude, true},
+		{newSymbol(t, "myField", pkgdmp.SymbolStructField), pkgdmp.Include, false},
+		{newSymbol(t, "myField", pkgdmp.SymbolStructField), pkgdmp.Exclude, true},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		name := fmt.Sprintf("returns %t for %s %s when action is %s",
+			tc.want, tc.s.SymbolType(), tc.s.Ident(), tc.action,
+		)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f := pkgdmp.FilterUnexportedForTypes(tc.action, scoped...)
+
+			if f.Include(tc.s) == tc.want {
+				return
+			}
+
+			t.Errorf("expected FilterUnexportedForTypes(%v, %v) to return %t for %s",
+				tc.action, scoped, tc.want, tc.s,
+			)
+		})
+	}
+}
+
 func TestFilterSymbolTypes(t *testing.T) {
 	tt := []pkgdmp.Symbol{
 		newSymbol(t, "myConst", pkgdmp.SymbolConst),
@@ -79,6 +132,7 @@ func TestFilterSymbolTypes(t *testing.T) {
 		newSymbol(t, "MyArray", pkgdmp.SymbolArrayType),
 		newSymbol(t, "MyFunc", pkgdmp.SymbolFunc),
 		newSymbol(t, "MyMethod", pkgdmp.SymbolMethod),
+		newSymbol(t, "myVar", pkgdmp.SymbolVar),
 	}
 
 	t.Run("returns true when all symbol types are included", func(t *testing.T) {
@@ -184,9 +238,277 @@ func TestFilterMatchingIdents(t *testing.T) {
 	}
 }
 
+func TestFilterReceiver(t *testing.T) {
+	p := regexp.MustCompile(`^My`)
+
+	tt := []struct {
+		s      pkgdmp.Symbol
+		action pkgdmp.FilterAction
+		want   bool
+	}{
+		{newMethodSymbol(t, "Do", "MyStruct"), pkgdmp.Include, true},
+		{newMethodSymbol(t, "Do", "OtherStruct"), pkgdmp.Include, false},
+		{newMethodSymbol(t, "Do", "MyStruct"), pkgdmp.Exclude, false},
+		{newMethodSymbol(t, "Do", "OtherStruct"), pkgdmp.Exclude, true},
+		{newMethodSymbol(t, "Do", ""), pkgdmp.Include, true},
+		{newSymbol(t, "OtherFunc", pkgdmp.SymbolFunc), pkgdmp.Include, true},
+		{newSymbol(t, "OtherStruct", pkgdmp.SymbolStructType), pkgdmp.Include, true},
+		{newSymbol(t, "MyStruct", pkgdmp.SymbolStructType), pkgdmp.Exclude, true},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		name := fmt.Sprintf("returns %t for %s with action %s", tc.want, tc.s, tc.action)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f := pkgdmp.FilterReceiver(tc.action, p)
+
+			if f.Include(tc.s) == tc.want {
+				return
+			}
+
+			t.Errorf("expected FilterReceiver(%v, %q) to return %t for %s",
+				tc.action, p, tc.want, tc.s,
+			)
+		})
+	}
+}
+
+func TestFilterMatchingTypes(t *testing.T) {
+	p := regexp.MustCompile(`\) error$`)
+
+	returnsErr := pkgdmp.Func{
+		Name:    "MyFunc",
+		Doc:     "MyFunc returns an error.",
+		Params:  []pkgdmp.Field{{Names: []string{"err"}, Type: "error"}},
+		Results: []pkgdmp.Field{{Type: "error"}},
+	}
+	returnsInt := pkgdmp.Func{
+		Name:    "MyFunc",
+		Params:  []pkgdmp.Field{{Names: []string{"err"}, Type: "error"}},
+		Results: []pkgdmp.Field{{Type: "int"}},
+	}
+	methodReturnsErr := pkgdmp.Func{
+		Name:     "Close",
+		Receiver: &pkgdmp.Field{Names: []string{"s"}, Type: "*MyStruct"},
+		Results:  []pkgdmp.Field{{Type: "error"}},
+	}
+
+	tt := []struct {
+		name   string
+		s      pkgdmp.Symbol
+		action pkgdmp.FilterAction
+		want   bool
+	}{
+		{"include matching return type", returnsErr, pkgdmp.Include, true},
+		{"include other return type", returnsInt, pkgdmp.Include, false},
+		{"exclude matching return type", returnsErr, pkgdmp.Exclude, false},
+		{"exclude other return type", returnsInt, pkgdmp.Exclude, true},
+		{"exclude method with matching return type", methodReturnsErr, pkgdmp.Exclude, false},
+		{"other symbol", newSymbol(t, "MyStruct", pkgdmp.SymbolStructType), pkgdmp.Include, true},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := pkgdmp.FilterMatchingTypes(tc.action, p)
+
+			if got := f.Include(tc.s); got != tc.want {
+				t.Errorf("expected %s to return %t for %s, but got %t", f, tc.want, tc.s, got)
+			}
+		})
+	}
+}
+
+func TestFilterDocMarker(t *testing.T) {
+	pkg := parseSource(t, `package mypackage
+
+// MyStable is a stable const.
+//
+// Stable: since v1.0.0.
+const MyStable = 1
+
+// Stable: consts in this group are stable.
+const (
+	MyGroupA = "a"
+	MyGroupB = "b"
+)
+
+// MyStruct is an example struct.
+//
+// Stable: since v1.2.0.
+type MyStruct struct{}
+
+// MyExperimental is an experimental function.
+//
+// Experimental: may change without notice.
+func MyExperimental() {}
+
+// MyFunc is an undocumented function.
+func MyFunc() {}
+`)
+
+	var symbols []pkgdmp.Symbol
+
+	for _, cg := range pkg.Consts {
+		for _, c := range cg.Consts {
+			symbols = append(symbols, c)
+		}
+	}
+
+	for _, td := range pkg.Types {
+		symbols = append(symbols, td)
+	}
+
+	for _, f := range pkg.Funcs {
+		symbols = append(symbols, f)
+	}
+
+	tt := []struct {
+		name   string
+		filter pkgdmp.SymbolFilter
+		want   []string
+	}{
+		{
+			name:   "include stable",
+			filter: pkgdmp.FilterDocMarker(pkgdmp.Include, "Stable:"),
+			want:   []string{"MyGroupA", "MyGroupB", "MyStable", "MyStruct"},
+		},
+		{
+			name:   "exclude experimental with comment marker",
+			filter: pkgdmp.FilterDocMarker(pkgdmp.Exclude, "// Experimental:"),
+			want:   []string{"MyFunc", "MyGroupA", "MyGroupB", "MyStable", "MyStruct"},
+		},
+		{
+			name:   "no matching marker",
+			filter: pkgdmp.FilterDocMarker(pkgdmp.Include, "Deprecated:"),
+			want:   nil,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+
+			for _, s := range symbols {
+				if tc.filter.Include(s) {
+					got = append(got, s.Ident())
+				}
+			}
+
+			sort.Strings(got)
+
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("expected %s to include %v, but got %v", tc.filter, tc.want, got)
+			}
+		})
+	}
+
+	fn := pkgdmp.Func{Name: "MyFunc", Doc: "Stable: MyFunc is created without a parser."}
+
+	if !pkgdmp.FilterDocMarker(pkgdmp.Include, "Stable:").Include(fn) {
+		t.Error("expected doc of symbol not created by a parser to be matched")
+	}
+}
+
+func TestFilterArity(t *testing.T) {
+	noArgs := pkgdmp.Func{Name: "MyFunc"}
+	twoParams := pkgdmp.Func{
+		Name:    "MyFunc",
+		Params:  []pkgdmp.Field{{Names: []string{"a", "b"}, Type: "int"}},
+		Results: []pkgdmp.Field{{Type: "error"}},
+	}
+	threeResults := pkgdmp.Func{
+		Name:    "MyFunc",
+		Params:  []pkgdmp.Field{{Type: "string"}},
+		Results: []pkgdmp.Field{{Type: "int"}, {Type: "int"}, {Type: "error"}},
+	}
+
+	tt := []struct {
+		name    string
+		s       pkgdmp.Symbol
+		action  pkgdmp.FilterAction
+		params  pkgdmp.ArityRange
+		results pkgdmp.ArityRange
+		want    bool
+	}{
+		{"no params", noArgs, pkgdmp.Include, pkgdmp.ArityRange{0, 0}, pkgdmp.AnyArity, true},
+		{"named params counted by name", twoParams, pkgdmp.Include, pkgdmp.ArityRange{0, 1}, pkgdmp.AnyArity, false},
+		{"params within range", twoParams, pkgdmp.Include, pkgdmp.ArityRange{2, 3}, pkgdmp.AnyArity, true},
+		{"unnamed results", threeResults, pkgdmp.Include, pkgdmp.AnyArity, pkgdmp.ArityRange{3, -1}, true},
+		{"results below min", twoParams, pkgdmp.Include, pkgdmp.AnyArity, pkgdmp.ArityRange{3, -1}, false},
+		{"exclude matching", noArgs, pkgdmp.Exclude, pkgdmp.ArityRange{0, 0}, pkgdmp.AnyArity, false},
+		{"exclude not matching", twoParams, pkgdmp.Exclude, pkgdmp.ArityRange{0, 0}, pkgdmp.AnyArity, true},
+		{"other symbol", newSymbol(t, "MyStruct", pkgdmp.SymbolStructType), pkgdmp.Include, pkgdmp.ArityRange{5, 5}, pkgdmp.AnyArity, true},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := pkgdmp.FilterArity(tc.action, tc.params, tc.results)
+
+			if got := f.Include(tc.s); got != tc.want {
+				t.Errorf("expected %s to return %t, but got %t", f, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFilterNamePredicate(t *testing.T) {
+	shorterThan3 := func(name string) bool { return len(name) < 3 }
+
+	tt := []struct {
+		s      pkgdmp.Symbol
+		action pkgdmp.FilterAction
+		want   bool
+	}{
+		{newSymbol(t, "Do", randSymbolType(t)), pkgdmp.Exclude, false},
+		{newSymbol(t, "X", randSymbolType(t)), pkgdmp.Exclude, false},
+		{newSymbol(t, "Run", randSymbolType(t)), pkgdmp.Exclude, true},
+		{newSymbol(t, "Do", randSymbolType(t)), pkgdmp.Include, true},
+		{newSymbol(t, "Run", randSymbolType(t)), pkgdmp.Include, false},
+		{newSymbol(t, "ID", pkgdmp.SymbolStructField), pkgdmp.Exclude, true},
+		{newSymbol(t, "a", pkgdmp.SymbolParamField), pkgdmp.Exclude, true},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		name := fmt.Sprintf("returns %t for %s with action %s", tc.want, tc.s, tc.action)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f := pkgdmp.FilterNamePredicate(tc.action, shorterThan3)
+
+			if f.Include(tc.s) == tc.want {
+				return
+			}
+
+			t.Errorf("expected FilterNamePredicate(%v, shorterThan3) to return %t for %s",
+				tc.action, tc.want, tc.s,
+			)
+		})
+	}
+}
+
 type stubSymbol struct {
 	ident string
 	st    pkgdmp.SymbolType
+	recv  string
 }
 
 func newSymbol(tb testing.TB, ident string, st pkgdmp.SymbolType) stubSymbol {
@@ -195,6 +517,16 @@ func newSymbol(tb testing.TB, ident string, st pkgdmp.SymbolType) stubSymbol {
 	return stubSymbol{ident: ident, st: st}
 }
 
+func newMethodSymbol(tb testing.TB, ident, recv string) stubSymbol {
+	tb.Helper()
+
+	return stubSymbol{ident: ident, st: pkgdmp.SymbolMethod, recv: recv}
+}
+
+func (ss stubSymbol) ReceiverType() string {
+	return ss.recv
+}
+
 func (ss stubSymbol) Ident() string {
 	return ss.ident
 }
@@ -252,3 +584,75 @@ func symbolTypesExcept(tb testing.TB, exclude ...pkgdmp.SymbolType) []pkgdmp.Sym
 
 	return result
 }
+
+func TestFilterChain(t *testing.T) {
+	publicAPI := pkgdmp.NewFilterChain("publicAPI", pkgdmp.FilterUnexported(pkgdmp.Exclude))
+	noMethods := pkgdmp.NewFilterChain("noMethods").
+		Add(pkgdmp.FilterSymbolTypes(pkgdmp.Exclude, pkgdmp.SymbolMethod))
+	chain := pkgdmp.NewFilterChain("publicFuncs", publicAPI, noMethods)
+
+	tt := []struct {
+		s    pkgdmp.Symbol
+		want bool
+	}{
+		{newSymbol(t, "MyFunc", pkgdmp.SymbolFunc), true},
+		{newSymbol(t, "myFunc", pkgdmp.SymbolFunc), false},
+		{newMethodSymbol(t, "MyMethod", "MyStruct"), false},
+		{newSymbol(t, "MyStruct", pkgdmp.SymbolStructType), true},
+	}
+
+	for _, tc := range tt {
+		if got := chain.Include(tc.s); got != tc.want {
+			t.Errorf("expected %s to return %t for %s, but got %t", chain, tc.want, tc.s, got)
+		}
+	}
+
+	if !pkgdmp.NewFilterChain("empty").Include(newSymbol(t, "myFunc", pkgdmp.SymbolFunc)) {
+		t.Error("expected empty chain to include all symbols")
+	}
+
+	want := "filterChain(name=publicFuncs,filters=" +
+		"filterChain(name=publicAPI,filters=filterUnexported(action=Exclude))," +
+		"filterChain(name=noMethods,filters=filterSymbolTypes(action=Exclude,symbolTypes=SymbolMethod)))"
+
+	if got := chain.String(); got != want {
+		t.Errorf("expected string %q, but got %q", want, got)
+	}
+}
+
+func TestFilterNot(t *testing.T) {
+	methods := pkgdmp.FilterSymbolTypes(pkgdmp.Include, pkgdmp.SymbolMethod)
+	notMethods := pkgdmp.FilterNot(methods)
+
+	tt := []struct {
+		s    pkgdmp.Symbol
+		want bool
+	}{
+		{newSymbol(t, "MyFunc", pkgdmp.SymbolFunc), true},
+		{newMethodSymbol(t, "MyMethod", "MyStruct"), false},
+		{newSymbol(t, "MyStruct", pkgdmp.SymbolStructType), true},
+		{newSymbol(t, "mypackage", pkgdmp.SymbolPackage), true},
+	}
+
+	for _, tc := range tt {
+		if got := notMethods.Include(tc.s); got != tc.want {
+			t.Errorf("expected %s to return %t for %s, but got %t", notMethods, tc.want, tc.s, got)
+		}
+	}
+
+	chain := pkgdmp.NewFilterChain("exportedNonMethods", pkgdmp.FilterUnexported(pkgdmp.Exclude), notMethods)
+
+	if chain.Include(newSymbol(t, "myFunc", pkgdmp.SymbolFunc)) {
+		t.Errorf("expected %s to exclude unexported function", chain)
+	}
+
+	if !pkgdmp.FilterNot(notMethods).Include(newMethodSymbol(t, "MyMethod", "MyStruct")) {
+		t.Error("expected double negation to include method")
+	}
+
+	want := "not(filterSymbolTypes(action=Include,symbolTypes=SymbolMethod))"
+
+	if got := notMethods.String(); got != want {
+		t.Errorf("expected string %q, but got %q", want, got)
+	}
+}
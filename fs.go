@@ -0,0 +1,167 @@
+package pkgdmp
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ParseFS parses the Go packages in directory dir of fsys, such as an
+// [embed.FS], an archive, or a directory opened with [os.DirFS], and returns
+// them sorted by name.
+//
+// Test files and files with build constraints excluding them from builds for
+// the current target are skipped. Test files are parsed for examples if the
+// parser is configured with [WithExamples], in which case the source files are
+// parsed with its file set. File names are the paths of the files in fsys.
+//
+// Warnings of all packages are available from [Parser.Warnings] afterwards.
+func (p *Parser) ParseFS(fsys fs.FS, dir string) ([]*Package, error) {
+	fset := p.exampleSet
+	if fset == nil {
+		fset = token.NewFileSet()
+	}
+
+	withTests := p.exampleSet != nil
+
+	files, err := ParseFSFiles(fset, fsys, dir, "", func(fi fs.FileInfo) bool {
+		return withTests || !strings.HasSuffix(fi.Name(), "_test.go")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+
+	for name := range files {
+		if !strings.HasSuffix(name, "_test") {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var (
+		pkgs     []*Package
+		warnings []error
+	)
+
+	for _, name := range names {
+		pkgFiles := append(files[name], files[name+"_test"]...)
+
+		dPkg, err := doc.NewFromFiles(fset, pkgFiles, "", doc.AllDecls|doc.PreserveAST)
+		if err != nil {
+			return nil, fmt.Errorf("creating documentation for %s package: %w", name, err)
+		}
+
+		pkg, err := p.Package(dPkg, pkgFiles...)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s package: %w", name, err)
+		}
+
+		pkgs = append(pkgs, pkg)
+		warnings = append(warnings, p.warnings...)
+	}
+
+	p.warnings = warnings
+
+	return pkgs, nil
+}
+
+// ParseDir parses the Go packages in directory dir on the OS filesystem, as
+// [Parser.ParseFS] does for [os.DirFS].
+func (p *Parser) ParseDir(dir string) ([]*Package, error) {
+	return p.ParseFS(os.DirFS(dir), ".")
+}
+
+// ParseFSFiles parses the Go source files in directory dir of fsys accepted
+// by include and matching the build constraints of the current target, and
+// returns them grouped by package name. All files are accepted if include is
+// nil.
+//
+// Files are parsed with fset and named by their paths in fsys, joined to
+// prefix with [filepath.Join] if it is not empty, so files of a directory
+// opened with [os.DirFS] can be named by their paths on the OS filesystem.
+func ParseFSFiles(
+	fset *token.FileSet,
+	fsys fs.FS,
+	dir, prefix string,
+	include func(fs.FileInfo) bool,
+) (map[string][]*ast.File, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %s: %w", fsName(dir, prefix), err)
+	}
+
+	ctx := build.Default
+	ctx.JoinPath = path.Join
+	ctx.OpenFile = func(name string) (io.ReadCloser, error) {
+		return fsys.Open(name) //nolint:wrapcheck // error is returned as is by go/build.
+	}
+
+	files := make(map[string][]*ast.File)
+
+	for _, e := range entries {
+		name := e.Name()
+
+		if e.IsDir() || path.Ext(name) != ".go" {
+			continue
+		}
+
+		if include != nil {
+			fi, err := e.Info()
+			if err != nil {
+				return nil, fmt.Errorf("getting file info for %s: %w", name, err)
+			}
+
+			if !include(fi) {
+				continue
+			}
+		}
+
+		filename := fsName(path.Join(dir, name), prefix)
+
+		match, err := ctx.MatchFile(dir, name)
+		if err != nil {
+			return nil, fmt.Errorf("matching build constraints of %s: %w", filename, err)
+		}
+
+		if !match {
+			continue
+		}
+
+		src, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", filename, err)
+		}
+
+		file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", filename, err)
+		}
+
+		files[file.Name.Name] = append(files[file.Name.Name], file)
+	}
+
+	return files, nil
+}
+
+// fsName returns slash-separated path name of a filesystem joined to prefix,
+// as documented on [ParseFSFiles].
+func fsName(name, prefix string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return filepath.Join(prefix, filepath.FromSlash(name))
+}
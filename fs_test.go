@@ -0,0 +1,115 @@
+package pkgdmp_test
+
+import (
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+func TestParser_ParseFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pkg/file.go":         {Data: []byte("package mypackage\n\n// MyFunc does things.\nfunc MyFunc() {}\n")},
+		"pkg/other.go":        {Data: []byte("package mypackage\n\n// MyStruct is a struct.\ntype MyStruct struct{}\n")},
+		"pkg/ignored.go":      {Data: []byte("//go:build ignore\n\npackage main\n\nfunc main() {}\n")},
+		"pkg/example_test.go": {Data: []byte("package mypackage_test\n\nfunc ExampleMyFunc() {\n\t// Output:\n}\n")},
+		"pkg/sub/sub.go":      {Data: []byte("package sub\n\nfunc MySubFunc() {}\n")},
+	}
+
+	t.Run("without examples", func(t *testing.T) {
+		pkgParser, _ := pkgdmp.NewParser()
+
+		pkgs, err := pkgParser.ParseFS(fsys, "pkg")
+		if err != nil {
+			t.Fatalf("expected no error, but got: %v", err)
+		}
+
+		if len(pkgs) != 1 || pkgs[0].Name != "mypackage" {
+			t.Fatalf("expected mypackage package, but got %d packages", len(pkgs))
+		}
+
+		if len(pkgs[0].Types) != 1 || len(pkgs[0].Funcs) != 1 {
+			t.Errorf("expected 1 type and 1 function, but got %d and %d", len(pkgs[0].Types), len(pkgs[0].Funcs))
+		}
+
+		if len(pkgs[0].Funcs) == 1 && len(pkgs[0].Funcs[0].Examples) != 0 {
+			t.Errorf("expected no examples, but got %d", len(pkgs[0].Funcs[0].Examples))
+		}
+	})
+
+	t.Run("with examples", func(t *testing.T) {
+		pkgParser, _ := pkgdmp.NewParser(pkgdmp.WithExamples(token.NewFileSet()))
+
+		pkgs, err := pkgParser.ParseFS(fsys, "pkg")
+		if err != nil {
+			t.Fatalf("expected no error, but got: %v", err)
+		}
+
+		if len(pkgs) != 1 || len(pkgs[0].Funcs) != 1 {
+			t.Fatalf("expected 1 package with 1 function, but got %d packages", len(pkgs))
+		}
+
+		if n := len(pkgs[0].Funcs[0].Examples); n != 1 {
+			t.Errorf("expected 1 example, but got %d", n)
+		}
+	})
+
+	t.Run("invalid directory", func(t *testing.T) {
+		pkgParser, _ := pkgdmp.NewParser()
+
+		if _, err := pkgParser.ParseFS(fsys, "missing"); err == nil {
+			t.Error("expected error for missing directory, but got no error")
+		}
+	})
+}
+
+func TestParser_ParseDir(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkgs, err := pkgParser.ParseDir("testdata/source")
+	if err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if len(pkgs) != 1 || pkgs[0].Name != "mypackage" {
+		t.Fatalf("expected mypackage package, but got %d packages", len(pkgs))
+	}
+}
+
+func TestParseFSFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pkg/file.go":    {Data: []byte("package mypackage\n")},
+		"pkg/other.go":   {Data: []byte("package mypackage\n")},
+		"pkg/ignored.go": {Data: []byte("//go:build ignore\n\npackage main\n")},
+		"pkg/doc.go":     {Data: []byte("package mypackage\n")},
+	}
+
+	fset := token.NewFileSet()
+
+	files, err := pkgdmp.ParseFSFiles(fset, fsys, "pkg", filepath.Join("root", "dir"), func(fi fs.FileInfo) bool {
+		return fi.Name() != "doc.go"
+	})
+	if err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if len(files) != 1 {
+		t.Fatalf("expected files of 1 package, but got %d packages", len(files))
+	}
+
+	var names []string
+
+	for _, file := range files["mypackage"] {
+		names = append(names, fset.File(file.Pos()).Name())
+	}
+
+	want := []string{filepath.Join("root", "dir", "pkg", "file.go"), filepath.Join("root", "dir", "pkg", "other.go")}
+
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected files %v, but got %v", want, names)
+	}
+}
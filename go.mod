@@ -1,7 +1,16 @@
 module github.com/michenriksen/pkgdmp
 
-go 1.19
+go 1.22.0
 
-require github.com/alecthomas/chroma v0.10.0
+require (
+	github.com/alecthomas/chroma v0.10.0
+	golang.org/x/mod v0.21.0
+	golang.org/x/term v0.25.0
+	golang.org/x/tools v0.26.0
+)
 
-require github.com/dlclark/regexp2 v1.4.0 // indirect
+require (
+	github.com/dlclark/regexp2 v1.4.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+)
@@ -0,0 +1,111 @@
+package pkgdmp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// hashPrintConfig is used when rendering signatures for hashing.
+//
+// Receiver names are omitted, as renaming a receiver does not change the
+// signature of a method.
+var hashPrintConfig = printConfig{noRecvNames: true}
+
+// SignatureHash returns a hex-encoded SHA-256 hash of the function's
+// normalized signature.
+//
+// The hash ignores doc and line comments and whitespace, so it only changes
+// if the rendered signature changes.
+func (f Func) SignatureHash() string {
+	return signatureHash(func(b *strings.Builder) {
+		stripFuncDocs(&f)
+		f.print(b, hashPrintConfig)
+	})
+}
+
+// SignatureHash returns a hex-encoded SHA-256 hash of the type definition's
+// normalized declaration, excluding methods declared on the type.
+//
+// Methods of interface types are part of the declaration. The hash ignores
+// doc and line comments and whitespace, so it only changes if the rendered
+// declaration changes.
+func (td TypeDef) SignatureHash() string {
+	return signatureHash(func(b *strings.Builder) {
+		td.Doc, td.Examples = "", nil
+		td.Fields = stripFieldDocs(td.Fields)
+		td.Params = stripFieldDocs(td.Params)
+		td.Results = stripFieldDocs(td.Results)
+
+		if td.Type == "interface" {
+			methods := make([]Func, len(td.Methods))
+
+			for i, m := range td.Methods {
+				stripFuncDocs(&m)
+				methods[i] = m
+			}
+
+			td.Methods = methods
+		} else {
+			td.Methods = nil
+		}
+
+		td.print(b, hashPrintConfig)
+	})
+}
+
+// SignatureHash returns a hex-encoded SHA-256 hash of the const's normalized
+// declaration, including the type and values implied by a preceding spec in
+// its group.
+//
+// The hash ignores doc and line comments and whitespace, so it only changes
+// if the declared type or values change.
+func (c Const) SignatureHash() string {
+	return signatureHash(func(b *strings.Builder) {
+		b.WriteString("const " + c.signature())
+	})
+}
+
+// SignatureHash returns a hex-encoded SHA-256 hash of the var's normalized
+// declaration.
+//
+// The hash ignores doc and line comments and whitespace, so it only changes
+// if the declared type or values change.
+func (v Var) SignatureHash() string {
+	return signatureHash(func(b *strings.Builder) {
+		b.WriteString("var " + v.signature())
+	})
+}
+
+func signatureHash(print func(*strings.Builder)) string {
+	var b strings.Builder
+
+	print(&b)
+
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(b.String()), " ")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func stripFuncDocs(f *Func) {
+	f.Doc, f.Comment, f.Examples = "", "", nil
+	f.Params = stripFieldDocs(f.Params)
+	f.Results = stripFieldDocs(f.Results)
+}
+
+// stripFieldDocs returns a copy of fields without doc and line comments.
+func stripFieldDocs(fields []Field) []Field {
+	if len(fields) == 0 {
+		return fields
+	}
+
+	res := make([]Field, len(fields))
+
+	for i, f := range fields {
+		f.Doc, f.Comment = "", ""
+		f.Fields = stripFieldDocs(f.Fields)
+		res[i] = f
+	}
+
+	return res
+}
@@ -0,0 +1,117 @@
+package pkgdmp_test
+
+import "testing"
+
+func TestSignatureHash(t *testing.T) {
+	base := parseSource(t, `package mypackage
+
+// MyStruct is an example struct.
+type MyStruct struct {
+	Name string // Name of the struct.
+}
+
+// String returns the name.
+func (s MyStruct) String() string { return s.Name }
+
+// MyFunc is an example function.
+func MyFunc(a int) error { return nil }
+
+const (
+	MyConst int = iota // First.
+	MyOtherConst
+)
+
+var MyVar string // MyVar is a var.
+`)
+
+	redocumented := parseSource(t, `package mypackage
+
+// MyStruct is a struct with a new doc comment.
+type MyStruct struct {
+	// Name has a new doc comment.
+	Name   string
+}
+
+// String returns something else.
+func (m MyStruct) String() string { return m.Name }
+
+func MyFunc(a   int) error { return nil }
+
+// Consts with a new doc comment.
+const (
+	MyConst   int = iota
+	MyOtherConst // Second.
+)
+
+var MyVar   string
+`)
+
+	changed := parseSource(t, `package mypackage
+
+type MyStruct struct {
+	Name string
+	Age  int
+}
+
+func (s *MyStruct) String() string { return s.Name }
+
+func MyFunc(a int) (int, error) { return 0, nil }
+
+const (
+	MyConst int64 = iota
+	MyOtherConst
+)
+
+var MyVar int
+`)
+
+	baseTD, redocTD, changedTD := base.Types[0], redocumented.Types[0], changed.Types[0]
+
+	if got, want := redocTD.SignatureHash(), baseTD.SignatureHash(); got != want {
+		t.Errorf("expected type hash to ignore doc changes; got %s, want %s", got, want)
+	}
+
+	if changedTD.SignatureHash() == baseTD.SignatureHash() {
+		t.Error("expected type hash to change when fields change")
+	}
+
+	if got, want := redocTD.Methods[0].SignatureHash(), baseTD.Methods[0].SignatureHash(); got != want {
+		t.Errorf("expected method hash to ignore doc and receiver name changes; got %s, want %s", got, want)
+	}
+
+	if changedTD.Methods[0].SignatureHash() == baseTD.Methods[0].SignatureHash() {
+		t.Error("expected method hash to change when receiver changes")
+	}
+
+	if got, want := redocumented.Funcs[0].SignatureHash(), base.Funcs[0].SignatureHash(); got != want {
+		t.Errorf("expected func hash to ignore doc and whitespace changes; got %s, want %s", got, want)
+	}
+
+	if changed.Funcs[0].SignatureHash() == base.Funcs[0].SignatureHash() {
+		t.Error("expected func hash to change when results change")
+	}
+
+	baseConst := base.Consts[0].Consts[1]
+	redocConst := redocumented.Consts[0].Consts[1]
+	changedConst := changed.Consts[0].Consts[1]
+
+	if got, want := redocConst.SignatureHash(), baseConst.SignatureHash(); got != want {
+		t.Errorf("expected const hash to ignore doc and whitespace changes; got %s, want %s", got, want)
+	}
+
+	if changedConst.SignatureHash() == baseConst.SignatureHash() {
+		t.Error("expected const hash to change when implied type changes")
+	}
+
+	if got, want := redocumented.Vars[0].Vars[0].SignatureHash(), base.Vars[0].Vars[0].SignatureHash(); got != want {
+		t.Errorf("expected var hash to ignore doc and whitespace changes; got %s, want %s", got, want)
+	}
+
+	if changed.Vars[0].Vars[0].SignatureHash() == base.Vars[0].Vars[0].SignatureHash() {
+		t.Error("expected var hash to change when type changes")
+	}
+
+	if got := base.Funcs[0].SignatureHash(); len(got) != 64 {
+		t.Errorf("expected hex-encoded SHA-256 hash; got %q", got)
+	}
+}
@@ -1,23 +1,67 @@
 package pkgdmp
 
 import (
+	"bytes"
 	"fmt"
 	"go/ast"
+	"go/constant"
+	"go/doc"
+	"go/format"
+	"go/parser"
 	"go/printer"
+	"go/scanner"
 	"go/token"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 var fieldSTMap = map[SymbolType]struct{}{
-	SymbolStructField:   {},
-	SymbolParamField:    {},
-	SymbolResultField:   {},
-	SymbolReceiverField: {},
+	SymbolStructField:    {},
+	SymbolParamField:     {},
+	SymbolResultField:    {},
+	SymbolReceiverField:  {},
+	SymbolTypeParamField: {},
 }
 
+// constTypeRank ranks default types of untyped constants for determining the
+// resulting type of a binary expression with mixed operands.
+var constTypeRank = map[string]int{
+	"int":        1,
+	"rune":       2,
+	"float64":    3,
+	"complex128": 4,
+}
+
+// numericTypes contains the predeclared numeric types.
+var numericTypes = map[string]struct{}{
+	"int": {}, "int8": {}, "int16": {}, "int32": {}, "int64": {},
+	"uint": {}, "uint8": {}, "uint16": {}, "uint32": {}, "uint64": {}, "uintptr": {},
+	"float32": {}, "float64": {}, "complex64": {}, "complex128": {},
+	"byte": {}, "rune": {},
+}
+
+// nilTypePrefixes contains prefixes of type strings for types with a nil zero
+// value.
+var nilTypePrefixes = []string{"*", "[]", "map[", "chan ", "chan<- ", "<-chan ", "func(", "interface{", "interface {"}
+
 var fieldTagRegexp = regexp.MustCompile(`(\w+):"(.*?)"`)
 
+// docLinkRegexp matches doc links such as `[Name]`, `[*Name]`, and
+// `[pkg.Name.Method]` in doc comments.
+var docLinkRegexp = regexp.MustCompile(`(^|[^\w\]])\[(\*?[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)*)\]([^\w(:\[]|$)`)
+
+// directiveLineRegexp matches comment lines with linter and compiler
+// directives, such as `nolint:errcheck`, `lint:ignore SA1019 reason`, and
+// `go:noinline`.
+var directiveLineRegexp = regexp.MustCompile(`^(?:nolint\b|lint:(?:file-)?ignore\b|go:[a-z]\w*)`)
+
+// trailingDirectiveRegexp matches linter directives trailing the text of a
+// comment line, such as `// unique ID //nolint:lll`.
+var trailingDirectiveRegexp = regexp.MustCompile(`\s*//\s*(?:nolint\b|lint:(?:file-)?ignore\b).*$`)
+
 func identNames(idents []*ast.Ident) []string {
 	iLen := len(idents)
 	if iLen == 0 {
@@ -33,6 +77,142 @@ func identNames(idents []*ast.Ident) []string {
 	return res
 }
 
+// structTypes returns the struct types of the type declarations in types,
+// keyed by type name.
+func structTypes(types []*doc.Type) map[string]*ast.StructType {
+	structs := make(map[string]*ast.StructType)
+
+	for _, t := range types {
+		for _, spec := range t.Decl.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				structs[ts.Name.Name] = st
+			}
+		}
+	}
+
+	return structs
+}
+
+// interfaceTypes returns the interface types declared in types by name.
+func interfaceTypes(types []*doc.Type) map[string]*ast.InterfaceType {
+	ifaces := make(map[string]*ast.InterfaceType)
+
+	for _, t := range types {
+		for _, spec := range t.Decl.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			if it, ok := ts.Type.(*ast.InterfaceType); ok {
+				ifaces[ts.Name.Name] = it
+			}
+		}
+	}
+
+	return ifaces
+}
+
+// promotedComment returns a field comment noting that the field is promoted
+// from embedded type origin.
+func promotedComment(comment, origin string) string {
+	note := "promoted from " + origin
+
+	if comment == "" {
+		return note + "."
+	}
+
+	return comment + " (" + note + ")"
+}
+
+// embeddedFieldName returns the implicit field name of an embedded field
+// with type typ, e.g. `Reader` for `*io.Reader`.
+func embeddedFieldName(typ string) string {
+	typ = receiverTypeName(typ)
+
+	if i := strings.LastIndexByte(typ, '.'); i != -1 {
+		typ = typ[i+1:]
+	}
+
+	return typ
+}
+
+// isEmbeddedInterface returns true if interface element expr is an embedded
+// named interface type, e.g. `Reader`, `io.Closer`, or `Getter[T]`, as
+// opposed to a type union or approximation element of a constraint.
+func isEmbeddedInterface(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		return true
+	case *ast.IndexExpr:
+		return isEmbeddedInterface(e.X)
+	case *ast.IndexListExpr:
+		return isEmbeddedInterface(e.X)
+	default:
+		return false
+	}
+}
+
+// uniqueSorted returns a sorted copy of strs with duplicates removed.
+func uniqueSorted(strs []string) []string {
+	if len(strs) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(strs))
+	res := make([]string, 0, len(strs))
+
+	for _, s := range strs {
+		if _, ok := seen[s]; ok {
+			continue
+		}
+
+		seen[s] = struct{}{}
+		res = append(res, s)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// cgoIdentPrefixes are prefixes of identifiers declared by cgo in generated
+// code for the `C` pseudo-package.
+var cgoIdentPrefixes = []string{"_Ctype_", "_Cfunc_", "_Cvar_", "_Cmacro_", "_Cgo_", "_cgo_"}
+
+// isCgoIdent returns true if name is an identifier declared by cgo.
+func isCgoIdent(name string) bool {
+	for _, prfx := range cgoIdentPrefixes {
+		if strings.HasPrefix(name, prfx) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withoutCgoImport returns imports without the `C` pseudo-package of cgo.
+func withoutCgoImport(imports []string) []string {
+	res := imports[:0]
+
+	for _, imp := range imports {
+		if imp != "C" {
+			res = append(res, imp)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	return res
+}
+
 func isExportedIdent(name string) bool {
 	return strings.ToUpper(name[:1]) == name[:1]
 }
@@ -42,45 +222,226 @@ func isFieldSymbolType(st SymbolType) bool {
 	return ok
 }
 
-func mkComment(s string) string {
+// fullDoc returns the raw doc comment of a symbol if it has one, and its
+// processed doc comment otherwise.
+func fullDoc(raw, doc string) string {
+	if raw != "" {
+		return raw
+	}
+
+	return doc
+}
+
+// lineComment returns s as the text of a trailing line comment, with line
+// breaks and runs of whitespace collapsed to single spaces.
+//
+// Trailing comments spanning multiple lines would otherwise produce invalid
+// code or break the alignment of struct fields when the code is formatted.
+func lineComment(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// mkComment returns s as a line comment wrapped at column width.
+//
+// Lines of multi-line text that are indented are considered preformatted and
+// left as they are. Paragraphs keep their original line breaks if all lines
+// fit within width. If width is 0, each paragraph is written as a single line.
+func mkComment(s string, width int) string {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return ""
 	}
 
-	var b strings.Builder
+	var (
+		b    strings.Builder
+		para []string
+	)
 
-	lines := strings.Split(s, "\n")
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+
+		if width != 0 && len(para) != 1 && linesFit(para, width) {
+			for _, line := range para {
+				fmt.Fprintf(&b, "// %s\n", line)
+			}
+		} else {
+			writeCommentPara(&b, strings.Join(para, " "), width)
+		}
 
-	if len(lines) > 1 {
-		for _, line := range lines {
+		para = para[:0]
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+			b.WriteString("//\n")
+		case line[0] == '\t':
+			flush()
+			fmt.Fprintf(&b, "//%s\n", line)
+		case line[0] == ' ':
+			flush()
 			fmt.Fprintf(&b, "// %s\n", line)
+		default:
+			para = append(para, strings.TrimSpace(line))
 		}
+	}
 
-		return b.String()
+	flush()
+
+	return b.String()
+}
+
+// internalTypePlaceholder replaces references to unexported types when
+// rendering with internal types stripped.
+const internalTypePlaceholder = "any"
+
+// internalTypeNote is added to the line comment of code in which references
+// to unexported types were replaced by [internalTypePlaceholder].
+const internalTypeNote = "internal types replaced by any"
+
+// typeString returns typ with references to the internal types of cfg
+// replaced by [internalTypePlaceholder].
+//
+// Identifiers qualified by a package name, such as `pkg.myType`, are not
+// replaced.
+func (cfg printConfig) typeString(typ string) string {
+	typ, _ = cfg.replaceInternalTypes(typ)
+
+	return typ
+}
+
+// internalComment returns line comment text comment with [internalTypeNote]
+// added if any of typs refers to an internal type of cfg, or comment
+// unchanged otherwise.
+func (cfg printConfig) internalComment(comment string, typs ...string) string {
+	replaced := false
+
+	for _, typ := range typs {
+		if _, replaced = cfg.replaceInternalTypes(typ); replaced {
+			break
+		}
 	}
 
-	lineLen, _ := fmt.Fprintf(&b, "// ")
-	words := strings.Fields(s)
+	switch {
+	case !replaced:
+		return comment
+	case comment == "":
+		return internalTypeNote + "."
+	default:
+		return comment + " (" + internalTypeNote + ")"
+	}
+}
 
-	for _, word := range words {
-		wLen := len(word)
-		if lineLen+wLen+1 < 80 {
-			n, _ := fmt.Fprintf(&b, "%s ", word)
-			lineLen += n
+// replaceInternalTypes returns typ with references to the internal types of
+// cfg replaced by [internalTypePlaceholder], and whether any were replaced.
+func (cfg printConfig) replaceInternalTypes(typ string) (string, bool) {
+	if len(cfg.internalTypes) == 0 {
+		return typ, false
+	}
+
+	var (
+		b    strings.Builder
+		last int
+	)
 
+	for _, ref := range typeRefOffsets(typ) {
+		if _, ok := cfg.internalTypes[ref.name]; !ok {
 			continue
 		}
 
-		lineLen, _ = fmt.Fprintf(&b, "\n// %s ", word)
+		b.WriteString(typ[last:ref.offset])
+		b.WriteString(internalTypePlaceholder)
+		last = ref.offset + len(ref.name)
 	}
 
-	b.WriteRune('\n')
+	if last == 0 {
+		return typ, false
+	}
 
-	return b.String()
+	b.WriteString(typ[last:])
+
+	return b.String(), true
+}
+
+// unexportedTypeNames returns the set of names of unexported types.
+func unexportedTypeNames(types []*doc.Type) map[string]struct{} {
+	names := make(map[string]struct{})
+
+	for _, t := range types {
+		if !isExportedIdent(t.Name) {
+			names[t.Name] = struct{}{}
+		}
+	}
+
+	return names
 }
 
-func fieldsList(fl []Field) string {
+// unindentBlock returns the statements of a printed block statement, without
+// the enclosing braces and one level of indentation.
+func unindentBlock(block string) string {
+	block = strings.TrimSpace(block)
+	block = strings.TrimSuffix(strings.TrimPrefix(block, "{"), "}")
+	lines := strings.Split(strings.Trim(block, "\n"), "\n")
+
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "\t")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// indentLines returns s with each non-empty line prefixed by indent.
+func indentLines(s, indent string) string {
+	lines := strings.SplitAfter(s, "\n")
+
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+
+	return strings.Join(lines, "")
+}
+
+// linesFit returns true if all lines fit within column width when written as
+// line comments.
+func linesFit(lines []string, width int) bool {
+	for _, line := range lines {
+		if len("// ")+len(line) >= width {
+			return false
+		}
+	}
+
+	return true
+}
+
+// writeCommentPara writes a paragraph of text as line comments wrapped at
+// column width, or as a single line comment if width is 0.
+func writeCommentPara(b *strings.Builder, s string, width int) {
+	if width == 0 {
+		fmt.Fprintf(b, "// %s\n", s)
+		return
+	}
+
+	lineLen, _ := fmt.Fprint(b, "//")
+
+	for _, word := range strings.Fields(s) {
+		if lineLen > len("//") && lineLen+len(word)+2 >= width {
+			fmt.Fprint(b, "\n//")
+			lineLen = len("//")
+		}
+
+		n, _ := fmt.Fprintf(b, " %s", word)
+		lineLen += n
+	}
+
+	b.WriteRune('\n')
+}
+
+func fieldsList(fl []Field, cfg printConfig) string {
 	fLen := len(fl)
 	if fLen == 0 {
 		return ""
@@ -89,22 +450,62 @@ func fieldsList(fl []Field) string {
 	res := make([]string, fLen)
 
 	for i, f := range fl {
-		res[i] = f.String()
+		var b strings.Builder
+
+		f.print(&b, cfg)
+		res[i] = b.String()
 	}
 
 	return strings.Join(res, ", ")
 }
 
-func resultsList(fl []Field) string {
-	s := fieldsList(fl)
+func typeParamsList(fl []Field, cfg printConfig) string {
+	if len(fl) == 0 {
+		return ""
+	}
 
-	if len(fl) > 1 {
+	return fmt.Sprintf("[%s]", fieldsList(fl, cfg))
+}
+
+// resultsList returns the code of function results fl, parenthesized unless
+// there is a single unnamed result, e.g. `error` and `(err error)`.
+func resultsList(fl []Field, cfg printConfig) string {
+	s := fieldsList(fl, cfg)
+
+	if len(fl) > 1 || (len(fl) == 1 && len(fl[0].Names) != 0) {
 		return fmt.Sprintf("(%s)", s)
 	}
 
 	return s
 }
 
+// inlineType returns the innermost element type of pointer, slice, and array
+// type expressions.
+func inlineType(expr ast.Expr) ast.Expr {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ArrayType:
+			expr = e.Elt
+		default:
+			return expr
+		}
+	}
+}
+
+// receiverTypeName returns the base type name of a method receiver type such
+// as `*MyStruct` or `Stack[T]`.
+func receiverTypeName(typ string) string {
+	typ = strings.TrimLeft(typ, "*")
+
+	if i := strings.IndexByte(typ, '['); i != -1 {
+		typ = typ[:i]
+	}
+
+	return typ
+}
+
 func printNodes(nodes any) string {
 	var b strings.Builder
 
@@ -115,6 +516,511 @@ func printNodes(nodes any) string {
 	return strings.TrimSpace(b.String())
 }
 
+// callExprValue returns the value of call expression call in a const
+// declaration.
+//
+// Conversions of a single value, such as `time.Duration(0)`, are split into
+// the converted value and the type converted to. Calls of builtin functions,
+// such as `complex(0, 1)`, and any other calls are rendered in full, with the
+// type inferred where possible.
+func callExprValue(call *ast.CallExpr) Value {
+	fun := printNodes(call.Fun)
+
+	switch fun {
+	case "len", "unsafe.Sizeof", "unsafe.Alignof", "unsafe.Offsetof":
+		typ := "int"
+		if fun != "len" {
+			typ = "uintptr"
+		}
+
+		return Value{Value: printNodes(call), Type: typ}
+	case "complex", "real", "imag":
+		val := Value{Value: printNodes(call)}
+
+		for _, arg := range call.Args {
+			if constExprType(arg) == "" {
+				return val
+			}
+		}
+
+		val.Type = "float64"
+		if fun == "complex" {
+			val.Type = "complex128"
+		}
+
+		return val
+	}
+
+	if len(call.Args) != 1 || call.Ellipsis.IsValid() {
+		return Value{Value: printNodes(call)}
+	}
+
+	return Value{Value: printNodes(call.Args[0]), Type: fun, Specific: true}
+}
+
+// constExprType infers the default type of an untyped constant expression.
+//
+// Returns an empty string if the type cannot be inferred from the expression
+// alone, e.g. when it references other constants.
+func constExprType(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return typeNames[e.Kind]
+	case *ast.ParenExpr:
+		return constExprType(e.X)
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			return "bool"
+		}
+
+		return constExprType(e.X)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ, token.LAND, token.LOR:
+			return "bool"
+		case token.SHL, token.SHR:
+			return constExprType(e.X)
+		}
+
+		x, y := constExprType(e.X), constExprType(e.Y)
+		if x == "" || y == "" {
+			return ""
+		}
+
+		if constTypeRank[x] >= constTypeRank[y] {
+			return x
+		}
+
+		return y
+	case *ast.Ident:
+		switch e.Name {
+		case "iota":
+			return "int"
+		case "true", "false":
+			return "bool"
+		}
+	}
+
+	return ""
+}
+
+// iotaEnumType returns the type of const declaration decl if it is an
+// iota-style enum, or an empty string otherwise.
+//
+// A const declaration is an enum if it is a group where the first spec
+// declares a single const of an explicit type with a value derived from iota,
+// and every following spec declares a single const with an implicit value or
+// another value of the same type derived from iota.
+func iotaEnumType(decl *ast.GenDecl) string {
+	if !decl.Lparen.IsValid() || len(decl.Specs) == 0 {
+		return ""
+	}
+
+	var typ string
+
+	for i, s := range decl.Specs {
+		vs, ok := s.(*ast.ValueSpec)
+		if !ok || len(vs.Names) != 1 {
+			return ""
+		}
+
+		if i != 0 && vs.Type == nil && len(vs.Values) == 0 {
+			continue
+		}
+
+		if vs.Type == nil || len(vs.Values) != 1 || !usesIota(vs.Values[0]) {
+			return ""
+		}
+
+		if t := printNodes(vs.Type); i == 0 {
+			typ = t
+		} else if t != typ {
+			return ""
+		}
+	}
+
+	return typ
+}
+
+// usesIota returns true if expression expr refers to iota.
+func usesIota(expr ast.Expr) bool {
+	var found bool
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Name == "iota" {
+			found = true
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// evalConstExpr returns the value of const expression expr with iota as the
+// value of iota, or false if it cannot be evaluated, e.g. because it refers
+// to other consts.
+func evalConstExpr(expr ast.Expr, iota int) (constant.Value, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		v := constant.MakeFromLiteral(e.Value, e.Kind, 0)
+		return v, v.Kind() != constant.Unknown
+	case *ast.Ident:
+		switch e.Name {
+		case "iota":
+			return constant.MakeInt64(int64(iota)), true
+		case "true", "false":
+			return constant.MakeBool(e.Name == "true"), true
+		}
+	case *ast.ParenExpr:
+		return evalConstExpr(e.X, iota)
+	case *ast.CallExpr:
+		// Conversions such as `MyType(iota)`.
+		if _, ok := e.Fun.(*ast.Ident); ok && len(e.Args) == 1 && !e.Ellipsis.IsValid() {
+			return evalConstExpr(e.Args[0], iota)
+		}
+	case *ast.UnaryExpr:
+		x, ok := evalConstExpr(e.X, iota)
+		if !ok {
+			return nil, false
+		}
+
+		switch {
+		case e.Op == token.NOT && x.Kind() == constant.Bool,
+			(e.Op == token.ADD || e.Op == token.SUB) && isNumericConst(x),
+			e.Op == token.XOR && x.Kind() == constant.Int:
+			return constant.UnaryOp(e.Op, x, 0), true
+		}
+	case *ast.BinaryExpr:
+		x, xOK := evalConstExpr(e.X, iota)
+		y, yOK := evalConstExpr(e.Y, iota)
+
+		if !xOK || !yOK {
+			return nil, false
+		}
+
+		return evalBinaryConst(x, e.Op, y)
+	}
+
+	return nil, false
+}
+
+// evalBinaryConst returns the value of binary operation x op y, or false if
+// the operation is invalid for the operands.
+func evalBinaryConst(x constant.Value, op token.Token, y constant.Value) (constant.Value, bool) {
+	bothInts := x.Kind() == constant.Int && y.Kind() == constant.Int
+
+	switch op {
+	case token.SHL, token.SHR:
+		s, ok := constant.Uint64Val(y)
+		if x.Kind() != constant.Int || y.Kind() != constant.Int || !ok || s > 1024 {
+			return nil, false
+		}
+
+		return constant.Shift(x, op, uint(s)), true
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		if x.Kind() != y.Kind() && (!isNumericConst(x) || !isNumericConst(y)) {
+			return nil, false
+		}
+
+		return constant.MakeBool(constant.Compare(x, op, y)), true
+	case token.LAND, token.LOR:
+		if x.Kind() != constant.Bool || y.Kind() != constant.Bool {
+			return nil, false
+		}
+	case token.ADD:
+		if !(x.Kind() == constant.String && y.Kind() == constant.String) && (!isNumericConst(x) || !isNumericConst(y)) {
+			return nil, false
+		}
+	case token.SUB, token.MUL:
+		if !isNumericConst(x) || !isNumericConst(y) {
+			return nil, false
+		}
+	case token.QUO:
+		if !isNumericConst(x) || !isNumericConst(y) || constant.Sign(y) == 0 {
+			return nil, false
+		}
+
+		if bothInts {
+			op = token.QUO_ASSIGN // Integer division.
+		}
+	case token.REM:
+		if !bothInts || constant.Sign(y) == 0 {
+			return nil, false
+		}
+	case token.AND, token.OR, token.XOR, token.AND_NOT:
+		if !bothInts {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	return constant.BinaryOp(x, op, y), true
+}
+
+func isNumericConst(v constant.Value) bool {
+	switch v.Kind() {
+	case constant.Int, constant.Float, constant.Complex:
+		return true
+	default:
+		return false
+	}
+}
+
+// zeroValue returns the zero value of the type with type string typ, or
+// false if it cannot be inferred from the type string alone, e.g. for named
+// types.
+func zeroValue(typ string) (string, bool) {
+	if _, ok := numericTypes[typ]; ok {
+		return "0", true
+	}
+
+	switch typ {
+	case "bool":
+		return "false", true
+	case "string":
+		return `""`, true
+	case "error", "any":
+		return "nil", true
+	}
+
+	for _, prefix := range nilTypePrefixes {
+		if strings.HasPrefix(typ, prefix) {
+			return "nil", true
+		}
+	}
+
+	// Array types.
+	if strings.HasPrefix(typ, "[") {
+		return typ + "{}", true
+	}
+
+	return "", false
+}
+
+// localTypeDefs returns the type expressions of type declarations in types
+// by type name.
+func localTypeDefs(types []*doc.Type) map[string]ast.Expr {
+	defs := make(map[string]ast.Expr, len(types))
+
+	for _, t := range types {
+		for _, spec := range t.Decl.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok {
+				defs[ts.Name.Name] = ts.Type
+			}
+		}
+	}
+
+	return defs
+}
+
+// underlyingType returns the underlying type of the package-local type
+// named name by following type definitions in defs.
+//
+// Returns an empty string if name is not a local type, or if the type
+// definitions are cyclic.
+func underlyingType(name string, defs map[string]ast.Expr) string {
+	seen := make(map[string]bool)
+
+	for {
+		expr, ok := defs[name]
+		if !ok || seen[name] {
+			return ""
+		}
+
+		seen[name] = true
+
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			return strings.Join(strings.Fields(printType(expr)), " ")
+		}
+
+		if _, ok := defs[ident.Name]; !ok {
+			return ident.Name
+		}
+
+		name = ident.Name
+	}
+}
+
+// printType returns the code of a type expression without any doc or line
+// comments on fields of inline struct and interface types.
+//
+// Comments are removed as the printer cannot position them correctly without
+// the original file set, which can result in broken code.
+func printType(expr ast.Expr) string {
+	type comments struct {
+		field *ast.Field
+		doc   *ast.CommentGroup
+		line  *ast.CommentGroup
+	}
+
+	var stripped []comments
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if f, ok := n.(*ast.Field); ok && (f.Doc != nil || f.Comment != nil) {
+			stripped = append(stripped, comments{field: f, doc: f.Doc, line: f.Comment})
+			f.Doc, f.Comment = nil, nil
+		}
+
+		return true
+	})
+
+	res := printNodes(expr)
+
+	for _, c := range stripped {
+		c.field.Doc, c.field.Comment = c.doc, c.line
+	}
+
+	return res
+}
+
+// stripDocLinks removes the square brackets of doc links in s.
+//
+// Indented lines are considered preformatted code and left as they are.
+func stripDocLinks(s string) string {
+	lines := strings.Split(s, "\n")
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+
+		// Run replacement twice as adjacent links share the separator between
+		// them, which prevents the second link from matching the first time.
+		for j := 0; j < 2; j++ {
+			line = docLinkRegexp.ReplaceAllString(line, "$1$2$3")
+		}
+
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// stripDirectives removes linter and compiler directives from comment text s,
+// both as separate lines and trailing other text.
+//
+// Indented lines are considered preformatted code and left as they are.
+func stripDirectives(s string) string {
+	lines := strings.Split(s, "\n")
+	res := lines[:0]
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			res = append(res, line)
+			continue
+		}
+
+		if directiveLineRegexp.MatchString(line) {
+			continue
+		}
+
+		res = append(res, trailingDirectiveRegexp.ReplaceAllString(line, ""))
+	}
+
+	return strings.TrimSpace(strings.Join(res, "\n"))
+}
+
+// majorVersionRegexp matches the major version suffix element of an import
+// path, such as `v2` in `github.com/x/y/v2`.
+var majorVersionRegexp = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName returns the assumed name of the package imported with import
+// path, which is the last element of the path without a major version suffix,
+// a `go-` prefix, and anything from the first character not valid in an
+// identifier, e.g. `yaml` for both `github.com/x/yaml/v3` and
+// `gopkg.in/yaml.v3`, and `sqlite3` for `github.com/mattn/go-sqlite3`.
+//
+// The name may differ from the declared package name, which can only be known
+// from the package's source.
+func importName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+
+	if len(elems) > 1 && majorVersionRegexp.MatchString(name) {
+		name = elems[len(elems)-2]
+	}
+
+	name = strings.TrimPrefix(name, "go-")
+
+	if i := strings.IndexFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}); i >= 0 {
+		name = name[:i]
+	}
+
+	return name
+}
+
+// qualifyTypeString returns typ with package qualifiers replaced by the
+// import paths they map to in paths.
+func qualifyTypeString(typ string, paths map[string]string) string {
+	if !strings.Contains(typ, ".") {
+		return typ
+	}
+
+	var (
+		s       scanner.Scanner
+		b       strings.Builder
+		last    int
+		prev    token.Token
+		prevPos token.Pos
+		prevLit string
+		before  token.Token
+	)
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(typ))
+
+	s.Init(file, []byte(typ), nil, 0)
+
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		if tok == token.PERIOD && prev == token.IDENT && before != token.PERIOD {
+			if path, ok := paths[prevLit]; ok {
+				offset := file.Offset(prevPos)
+
+				b.WriteString(typ[last:offset])
+				b.WriteString(path)
+				last = offset + len(prevLit)
+			}
+		}
+
+		before, prev, prevPos, prevLit = prev, tok, pos, lit
+	}
+
+	b.WriteString(typ[last:])
+
+	return b.String()
+}
+
+// embedPatterns returns the patterns of the `//go:embed` directives in
+// comment group cg, one entry per directive.
+func embedPatterns(cg *ast.CommentGroup) []string {
+	if cg == nil {
+		return nil
+	}
+
+	var res []string
+
+	for _, c := range cg.List {
+		rest, ok := strings.CutPrefix(c.Text, "//go:embed")
+		if !ok || strings.TrimLeft(rest, " \t") == rest {
+			continue
+		}
+
+		res = append(res, strings.TrimSpace(rest))
+	}
+
+	return res
+}
+
 func parseFieldTags(s string) [][]string {
 	s = strings.Trim(s, "`")
 
@@ -135,3 +1041,241 @@ func parseFieldTags(s string) [][]string {
 
 	return tags
 }
+
+// compactTypes returns formatted Go source src with the bodies of struct and
+// interface types in type declarations collapsed onto a single line, e.g.
+// `type T struct { A int; B string }`.
+//
+// Formatting expands struct and interface types with multiple fields or
+// methods onto multiple lines, so compact types are restored after it.
+// Comments inside type bodies are dropped.
+func compactTypes(src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing formatted source: %w", err)
+	}
+
+	var (
+		b    bytes.Buffer
+		last int
+	)
+
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			switch ts.Type.(type) {
+			case *ast.StructType, *ast.InterfaceType:
+			default:
+				continue
+			}
+
+			start, end := fset.Position(ts.Type.Pos()).Offset, fset.Position(ts.Type.End()).Offset
+
+			b.Write(src[last:start])
+			b.WriteString(compactNode(src, fset, ts.Type))
+
+			last = end
+		}
+	}
+
+	b.Write(src[last:])
+
+	return b.Bytes(), nil
+}
+
+// wrapParams returns src with the parameters of function declarations and
+// interface methods on lines longer than width columns rendered on separate
+// lines, e.g.:
+//
+//	func MyFunction(
+//		a int,
+//		b string,
+//	) error
+//
+// Only signatures written on a single line are wrapped, and fields declaring
+// multiple parameter names, e.g. `a, b int`, are kept on the same line.
+//
+// Line breaks are inserted before the parameters and closing parenthesis of
+// the parsed signatures, and the result formatted, so comments in parameter
+// lists are kept.
+func wrapParams(src []byte, width int) ([]byte, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing formatted source: %w", err)
+	}
+
+	// Offsets of parameters and closing parentheses to insert line breaks
+	// before, with a comma before closing parentheses.
+	var breaks, closings []int
+
+	wrap := func(ft *ast.FuncType) {
+		params := ft.Params
+		if params == nil || len(params.List) == 0 {
+			return
+		}
+
+		opening := fset.Position(params.Opening)
+
+		if fset.Position(params.Closing).Line != opening.Line || lineWidth(src, opening.Offset) <= width {
+			return
+		}
+
+		for _, f := range params.List {
+			breaks = append(breaks, fset.Position(f.Pos()).Offset)
+		}
+
+		closings = append(closings, fset.Position(params.Closing).Offset)
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch t := n.(type) {
+		case *ast.FuncDecl:
+			wrap(t.Type)
+		case *ast.InterfaceType:
+			for _, m := range t.Methods.List {
+				if ft, ok := m.Type.(*ast.FuncType); ok && len(m.Names) != 0 {
+					wrap(ft)
+				}
+			}
+		}
+
+		return true
+	})
+
+	if len(closings) == 0 {
+		return src, nil
+	}
+
+	breaks = append(breaks, closings...)
+	sort.Ints(breaks)
+	sort.Ints(closings)
+
+	var (
+		b    bytes.Buffer
+		last int
+	)
+
+	for _, offset := range breaks {
+		b.Write(src[last:offset])
+
+		if len(closings) != 0 && closings[0] == offset {
+			b.WriteByte(',')
+			closings = closings[1:]
+		}
+
+		b.WriteByte('\n')
+
+		last = offset
+	}
+
+	b.Write(src[last:])
+
+	wrapped, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting wrapped source: %w", err)
+	}
+
+	return wrapped, nil
+}
+
+// lineWidth returns the number of characters in the line of src containing
+// offset.
+func lineWidth(src []byte, offset int) int {
+	start := bytes.LastIndexByte(src[:offset], '\n') + 1
+
+	end := bytes.IndexByte(src[offset:], '\n')
+	if end == -1 {
+		end = len(src) - offset
+	}
+
+	return utf8.RuneCount(src[start : offset+end])
+}
+
+// compactNode returns the source code of node in src with the bodies of all
+// struct and interface types in it collapsed onto a single line.
+func compactNode(src []byte, fset *token.FileSet, node ast.Node) string {
+	var (
+		b    strings.Builder
+		last = fset.Position(node.Pos()).Offset
+	)
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		var (
+			kw string
+			fl *ast.FieldList
+		)
+
+		switch t := n.(type) {
+		case *ast.StructType:
+			kw, fl = "struct", t.Fields
+		case *ast.InterfaceType:
+			kw, fl = "interface", t.Methods
+		default:
+			return true
+		}
+
+		b.Write(src[last:fset.Position(n.Pos()).Offset])
+		b.WriteString(kw)
+
+		if fl.NumFields() == 0 {
+			b.WriteString("{}")
+		} else {
+			fields := make([]string, len(fl.List))
+
+			for i, f := range fl.List {
+				fields[i] = compactField(src, fset, f, kw == "interface")
+			}
+
+			fmt.Fprintf(&b, " { %s }", strings.Join(fields, "; "))
+		}
+
+		last = fset.Position(n.End()).Offset
+
+		return false
+	})
+
+	b.Write(src[last:fset.Position(node.End()).Offset])
+
+	return b.String()
+}
+
+// compactField returns the source code of f in src as compacted by
+// [compactNode], without the alignment padding added by formatting.
+//
+// If method is true, f is an element of an interface type.
+func compactField(src []byte, fset *token.FileSet, f *ast.Field, method bool) string {
+	typ := compactNode(src, fset, f.Type)
+
+	if method && len(f.Names) != 0 {
+		// The func type of a method has no func keyword, so its source
+		// starts with the parameters that directly follow the name.
+		return f.Names[0].Name + typ
+	}
+
+	var b strings.Builder
+
+	if len(f.Names) != 0 {
+		b.WriteString(strings.Join(identNames(f.Names), ", ") + " ")
+	}
+
+	b.WriteString(typ)
+
+	if f.Tag != nil {
+		b.WriteString(" " + f.Tag.Value)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,325 @@
+package cli
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// archiveExts are the file extensions of supported archives.
+var archiveExts = []string{".zip", ".tar.gz", ".tgz"}
+
+// IsArchive returns true if path is a regular file with the extension of a
+// supported archive: `.zip`, `.tar.gz`, or `.tgz`.
+func IsArchive(path string) bool {
+	if !hasArchiveExt(path) {
+		return false
+	}
+
+	fi, err := os.Stat(path)
+
+	return err == nil && fi.Mode().IsRegular()
+}
+
+func hasArchiveExt(name string) bool {
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OpenArchive reads the zip or gzipped tar archive at path into memory and
+// returns it as a filesystem, together with the directory of the package to
+// load from it.
+//
+// The package directory is the shallowest directory containing a go.mod
+// file, such as `example.com/mymodule@v1.2.3` in module zips from the module
+// cache, or the deepest directory containing all files of the archive if
+// there is no go.mod file.
+func OpenArchive(path string) (fs.FS, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading archive: %w", err)
+	}
+
+	var fsys fs.FS
+
+	if strings.HasSuffix(path, ".zip") {
+		fsys, err = zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	} else {
+		fsys, err = readTarGz(data)
+	}
+
+	if err != nil {
+		return nil, "", fmt.Errorf("reading archive %s: %w", path, err)
+	}
+
+	dir, err := archiveRoot(fsys)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading archive %s: %w", path, err)
+	}
+
+	return fsys, dir, nil
+}
+
+// readTarGz returns the regular files of the gzipped tar archive data as an
+// in-memory filesystem.
+func readTarGz(data []byte) (fs.FS, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing: %w", err)
+	}
+
+	fsys := make(memFS)
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("reading tar entry: %w", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "/"))
+		if !fs.ValidPath(name) {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", hdr.Name, err)
+		}
+
+		fsys[name] = memFile{data: content, modTime: hdr.ModTime}
+	}
+
+	return fsys, nil
+}
+
+// archiveRoot returns the directory of the package to load from archive
+// filesystem fsys, as documented on [OpenArchive].
+func archiveRoot(fsys fs.FS) (string, error) {
+	var (
+		modDir string
+		common string
+		found  bool
+	)
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		dir := path.Dir(name)
+
+		if d.Name() == "go.mod" && (modDir == "" || depth(dir) < depth(modDir)) {
+			modDir = dir
+		}
+
+		if !found {
+			common, found = dir, true
+		}
+
+		for common != "." && dir != common && !strings.HasPrefix(dir, common+"/") {
+			common = path.Dir(common)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("walking archive: %w", err)
+	}
+
+	if modDir != "" {
+		return modDir, nil
+	}
+
+	if !found {
+		return ".", nil
+	}
+
+	return common, nil
+}
+
+// depth returns the number of path elements of slash-separated dir.
+func depth(dir string) int {
+	if dir == "." {
+		return 0
+	}
+
+	return strings.Count(dir, "/") + 1
+}
+
+// memFS is a read-only in-memory filesystem of regular files keyed by their
+// slash-separated paths. Directories are implied by the paths of the files.
+type memFS map[string]memFile
+
+// memFile is a regular file of a [memFS].
+type memFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+// Open opens the named file or directory.
+func (m memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if f, ok := m[name]; ok {
+		return &openMemFile{info: m.fileInfo(name, f), r: bytes.NewReader(f.data)}, nil
+	}
+
+	entries, err := m.ReadDir(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &openMemDir{info: memFileInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+// ReadDir returns the entries of the named directory sorted by name.
+func (m memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+
+	seen := make(map[string]struct{})
+
+	var entries []fs.DirEntry
+
+	for fname, f := range m {
+		rest, ok := strings.CutPrefix(fname, prefix)
+		if !ok {
+			continue
+		}
+
+		elem, _, isDir := strings.Cut(rest, "/")
+		if _, ok := seen[elem]; ok {
+			continue
+		}
+
+		seen[elem] = struct{}{}
+
+		info := memFileInfo{name: elem, dir: true}
+		if !isDir {
+			info = m.fileInfo(fname, f)
+		}
+
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+
+	if len(entries) == 0 && name != "." {
+		if _, ok := m[name]; ok {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+		}
+
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	return entries, nil
+}
+
+func (m memFS) fileInfo(name string, f memFile) memFileInfo {
+	return memFileInfo{name: path.Base(name), size: int64(len(f.data)), modTime: f.modTime}
+}
+
+// memFileInfo describes a file or directory of a [memFS].
+type memFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (fi memFileInfo) Name() string       { return fi.name }
+func (fi memFileInfo) Size() int64        { return fi.size }
+func (fi memFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi memFileInfo) IsDir() bool        { return fi.dir }
+func (fi memFileInfo) Sys() any           { return nil }
+
+func (fi memFileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0o555
+	}
+
+	return 0o444
+}
+
+// openMemFile is an open regular file of a [memFS].
+type openMemFile struct {
+	info memFileInfo
+	r    *bytes.Reader
+}
+
+func (f *openMemFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *openMemFile) Close() error               { return nil }
+
+func (f *openMemFile) Read(b []byte) (int, error) {
+	return f.r.Read(b) //nolint:wrapcheck // io.EOF must be returned as is.
+}
+
+// openMemDir is an open directory of a [memFS].
+type openMemDir struct {
+	info    memFileInfo
+	entries []fs.DirEntry
+}
+
+func (d *openMemDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *openMemDir) Close() error               { return nil }
+
+func (d *openMemDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir returns the next n entries of the directory, or all remaining
+// entries if n is not positive.
+func (d *openMemDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+
+		return entries, nil
+	}
+
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+
+	return entries, nil
+}
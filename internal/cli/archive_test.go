@@ -0,0 +1,149 @@
+package cli_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+// archiveFiles are the files of the module archives written by the tests.
+var archiveFiles = map[string]string{
+	"example.com/mymodule@v1.0.0/go.mod":        "module example.com/mymodule\n",
+	"example.com/mymodule@v1.0.0/file.go":       "package mymodule\n\nfunc MyFunc() {}\n",
+	"example.com/mymodule@v1.0.0/file_test.go":  "package mymodule\n",
+	"example.com/mymodule@v1.0.0/sub/nested.go": "package sub\n",
+}
+
+func TestConfig_LoadPackages_Archive(t *testing.T) {
+	dir := t.TempDir()
+
+	tt := []struct {
+		name  string
+		file  string
+		write func(io.Writer) error
+	}{
+		{"zip", "mymodule.zip", writeZip},
+		{"tar.gz", "mymodule.tar.gz", writeTarGz},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			file := filepath.Join(dir, tc.file)
+
+			f, err := os.Create(file)
+			if err != nil {
+				t.Fatalf("error creating archive: %v", err)
+			}
+
+			if err := tc.write(f); err != nil {
+				t.Fatalf("error writing archive: %v", err)
+			}
+
+			if err := f.Close(); err != nil {
+				t.Fatalf("error closing archive: %v", err)
+			}
+
+			if !cli.IsArchive(file) {
+				t.Fatalf("expected %s to be an archive", tc.file)
+			}
+
+			pkgs, _, err := (&cli.Config{}).LoadPackages(file)
+			if err != nil {
+				t.Fatalf("expected no error, but got: %v", err)
+			}
+
+			if len(pkgs) != 1 || pkgs[0].Name != "mymodule" {
+				t.Fatalf("expected mymodule package, but got %d packages", len(pkgs))
+			}
+
+			want := filepath.Join(file, "example.com", "mymodule@v1.0.0", "file.go")
+
+			if _, ok := pkgs[0].Files[want]; !ok || len(pkgs[0].Files) != 1 {
+				t.Errorf("expected only %s to be parsed, but got %d files", want, len(pkgs[0].Files))
+			}
+		})
+	}
+}
+
+func TestOpenArchive_TarGz(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "mymodule.tar.gz")
+
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatalf("error creating archive: %v", err)
+	}
+
+	if err := writeTarGz(f); err != nil {
+		t.Fatalf("error writing archive: %v", err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatalf("error closing archive: %v", err)
+	}
+
+	fsys, dir, err := cli.OpenArchive(file)
+	if err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if want := "example.com/mymodule@v1.0.0"; dir != want {
+		t.Errorf("expected package directory %q, but got %q", want, dir)
+	}
+
+	names := make([]string, 0, len(archiveFiles))
+
+	for name := range archiveFiles {
+		names = append(names, name)
+	}
+
+	if err := fstest.TestFS(fsys, names...); err != nil {
+		t.Errorf("expected archive filesystem to be valid, but got: %v", err)
+	}
+}
+
+func writeZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	for name, content := range archiveFiles {
+		fw, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(fw, content); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeTarGz(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for name, content := range archiveFiles {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(tw, content); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
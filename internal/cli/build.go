@@ -59,3 +59,22 @@ func BuildGoVersion() string {
 
 	return buildGoVersion
 }
+
+// BuildInfo describes the pkgdmp build that generated an output.
+type BuildInfo struct {
+	Version     string `json:"version"`
+	Commit      string `json:"commit"`
+	GoVersion   string `json:"goVersion"`
+	GeneratedAt string `json:"generatedAt"`
+}
+
+// NewBuildInfo returns build information of the running pkgdmp build with
+// the current UTC time as generation time.
+func NewBuildInfo() BuildInfo {
+	return BuildInfo{
+		Version:     Version(),
+		Commit:      BuildCommit(),
+		GoVersion:   BuildGoVersion(),
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Cache is an on-disk cache of encoded packages keyed by a hash of the
+// source files they were parsed from and the active configuration.
+type Cache struct {
+	dir string
+}
+
+// NewCache returns a cache storing entries in dir, creating the directory if
+// it does not exist.
+func NewCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	return &Cache{dir: dir}, nil
+}
+
+// Key returns a cache key for the source files in srcDir accepted by include
+// when parsed with configuration cfg.
+//
+// The key changes if the name, size, modification time, or content of any of
+// the files changes, or if any configuration affecting parsing changes.
+func (c *Cache) Key(cfg *Config, srcDir string, include func(fs.FileInfo) bool) (string, error) {
+	opts, err := ParserOptsFromCfg(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+
+	fmt.Fprintf(h, "%s\x00%s\x00%t\x00%t\x00%s\x00%s\x00%t\x00%d\x00%d\x00",
+		Version(), cfg.Loader, cfg.Header, cfg.DetectFlags, cfg.OnlyPackages, cfg.ExcludePackages, cfg.ByImportPath,
+		cfg.MinNameLen, cfg.MaxNameLen,
+	)
+
+	for _, opt := range opts {
+		fmt.Fprintf(h, "%s\x00", opt)
+	}
+
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return "", fmt.Errorf("reading source directory: %w", err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".go" {
+			continue
+		}
+
+		fi, err := e.Info()
+		if err != nil {
+			return "", fmt.Errorf("getting file info for %s: %w", e.Name(), err)
+		}
+
+		if include != nil && !include(fi) {
+			continue
+		}
+
+		fmt.Fprintf(h, "%s\x00%d\x00%d\x00", fi.Name(), fi.Size(), fi.ModTime().UnixNano())
+
+		if err := hashFile(h, filepath.Join(srcDir, fi.Name())); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Get returns the cached data for key, or false if there is no entry for key.
+func (c *Cache) Get(key string) ([]byte, bool, error) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("reading cache entry: %w", err)
+	}
+
+	return data, true, nil
+}
+
+// Put stores data under key.
+//
+// The entry is written to a temporary file and renamed, so concurrent
+// readers never see a partially written entry.
+func (c *Cache) Put(key string, data []byte) error {
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("storing cache entry: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+func hashFile(w io.Writer, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", name, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package cli_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestCache(t *testing.T) {
+	srcDir := t.TempDir()
+	srcFile := filepath.Join(srcDir, "file.go")
+
+	writeFile(t, srcFile, "package mypackage\n\nfunc MyFunc() {}\n")
+	writeFile(t, filepath.Join(srcDir, "file_test.go"), "package mypackage\n")
+
+	cache, err := cli.NewCache(filepath.Join(t.TempDir(), "cache"))
+	if err != nil {
+		t.Fatalf("expected no error when creating cache, but got: %v", err)
+	}
+
+	cfg := &cli.Config{Wrap: 80}
+
+	key := cacheKey(t, cache, cfg, srcDir)
+
+	if _, ok, err := cache.Get(key); ok || err != nil {
+		t.Fatalf("expected cache miss without error, but got ok=%t, err=%v", ok, err)
+	}
+
+	if err := cache.Put(key, []byte(`[]`)); err != nil {
+		t.Fatalf("expected no error when storing cache entry, but got: %v", err)
+	}
+
+	t.Run("hit with unchanged files", func(t *testing.T) {
+		if k := cacheKey(t, cache, cfg, srcDir); k != key {
+			t.Fatalf("expected key %s, but got %s", key, k)
+		}
+
+		data, ok, err := cache.Get(key)
+		if !ok || err != nil {
+			t.Fatalf("expected cache hit without error, but got ok=%t, err=%v", ok, err)
+		}
+
+		if string(data) != `[]` {
+			t.Errorf("expected cached data %q, but got %q", `[]`, data)
+		}
+	})
+
+	t.Run("miss with excluded file changed", func(t *testing.T) {
+		writeFile(t, filepath.Join(srcDir, "file_test.go"), "package mypackage\n\nfunc TestX() {}\n")
+
+		if k := cacheKey(t, cache, cfg, srcDir); k != key {
+			t.Errorf("expected key %s, but got %s", key, k)
+		}
+	})
+
+	t.Run("miss with changed options", func(t *testing.T) {
+		for _, c := range []*cli.Config{
+			{Wrap: 80, NoDocs: true},
+			{Wrap: 80, MinNameLen: 3},
+			{Wrap: 80, OnlyPackages: "mypackage"},
+			{Wrap: 80, Loader: cli.LoaderPackages},
+		} {
+			if k := cacheKey(t, cache, c, srcDir); k == key {
+				t.Errorf("expected key to change with config %+v", c)
+			}
+		}
+	})
+
+	t.Run("miss with changed modification time", func(t *testing.T) {
+		mtime := time.Now().Add(time.Hour)
+
+		if err := os.Chtimes(srcFile, mtime, mtime); err != nil {
+			t.Fatalf("error changing modification time: %v", err)
+		}
+
+		if k := cacheKey(t, cache, cfg, srcDir); k == key {
+			t.Error("expected key to change with modification time")
+		}
+	})
+
+	t.Run("miss with changed content", func(t *testing.T) {
+		before := cacheKey(t, cache, cfg, srcDir)
+
+		writeFile(t, srcFile, "package mypackage\n\nfunc MyFunc() int {}\n")
+
+		if k := cacheKey(t, cache, cfg, srcDir); k == before {
+			t.Error("expected key to change with file content")
+		}
+	})
+}
+
+func cacheKey(tb testing.TB, cache *cli.Cache, cfg *cli.Config, srcDir string) string {
+	tb.Helper()
+
+	key, err := cache.Key(cfg, srcDir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	})
+	if err != nil {
+		tb.Fatalf("expected no error when computing cache key, but got: %v", err)
+	}
+
+	return key
+}
+
+func writeFile(tb testing.TB, name, content string) {
+	tb.Helper()
+
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		tb.Fatalf("error writing %s: %v", name, err)
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+// ChecksumsEncoder writes signature checksums of the consts, vars, type
+// definitions, functions, and methods of packages to an output stream.
+//
+// Each symbol is written on a separate line as its qualified name followed by
+// its signature hash, sorted by qualified name. The hashes ignore doc and line
+// comments, so they are suited for detecting API changes between versions.
+type ChecksumsEncoder struct {
+	w io.Writer
+}
+
+// NewChecksumsEncoder returns a new encoder that writes to w.
+func NewChecksumsEncoder(w io.Writer) *ChecksumsEncoder {
+	return &ChecksumsEncoder{w: w}
+}
+
+// Encode writes the signature checksums of the symbols in pkg.
+func (e *ChecksumsEncoder) Encode(pkg *pkgdmp.Package) error {
+	var lines []string
+
+	add := func(qualified, hash string) {
+		lines = append(lines, qualified+"  "+hash)
+	}
+
+	for _, cg := range pkg.Consts {
+		for _, c := range cg.Consts {
+			for _, name := range c.Names {
+				add(qualifiedName(pkg.Name, "", name), c.SignatureHash())
+			}
+		}
+	}
+
+	for _, vg := range pkg.Vars {
+		for _, v := range vg.Vars {
+			for _, name := range v.Names {
+				add(qualifiedName(pkg.Name, "", name), v.SignatureHash())
+			}
+		}
+	}
+
+	for _, td := range pkg.Types {
+		add(qualifiedName(pkg.Name, td.Qualified, td.Name), td.SignatureHash())
+
+		if td.Type == "interface" {
+			continue
+		}
+
+		for _, m := range td.Methods {
+			add(qualifiedName(pkg.Name, m.Qualified, td.Name+"."+m.Name), m.SignatureHash())
+		}
+	}
+
+	for _, f := range pkg.Funcs {
+		name := f.Name
+
+		if rt := f.ReceiverType(); rt != "" {
+			name = rt + "." + f.Name
+		}
+
+		add(qualifiedName(pkg.Name, f.Qualified, name), f.SignatureHash())
+	}
+
+	sort.Strings(lines)
+
+	var b strings.Builder
+
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+
+	if _, err := io.WriteString(e.w, b.String()); err != nil {
+		return fmt.Errorf("writing checksums for %s package: %w", pkg.Name, err)
+	}
+
+	return nil
+}
+
+// qualifiedName returns qualified if set, or name qualified with pkgName.
+func qualifiedName(pkgName, qualified, name string) string {
+	if qualified != "" {
+		return qualified
+	}
+
+	return pkgName + "." + name
+}
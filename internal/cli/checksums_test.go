@@ -0,0 +1,74 @@
+package cli_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestChecksumsEncoder(t *testing.T) {
+	pkg := &pkgdmp.Package{
+		Name: "mypackage",
+		Consts: []pkgdmp.ConstGroup{
+			{Consts: []pkgdmp.Const{{Names: []string{"MaxUsers"}, Spec: "MaxUsers = 10"}}},
+		},
+		Vars: []pkgdmp.VarGroup{
+			{Vars: []pkgdmp.Var{{Names: []string{"ErrA", "ErrB"}, Spec: "ErrA, ErrB error"}}},
+		},
+		Types: []pkgdmp.TypeDef{
+			{
+				Type:    "struct",
+				Name:    "User",
+				Fields:  []pkgdmp.Field{{Names: []string{"ID"}, Type: "int"}},
+				Methods: []pkgdmp.Func{{Name: "Save", Results: []pkgdmp.Field{{Type: "error"}}}},
+			},
+			{
+				Type:    "interface",
+				Name:    "Store",
+				Methods: []pkgdmp.Func{{Name: "Get"}},
+			},
+		},
+		Funcs: []pkgdmp.Func{
+			{Name: "NewUser", Results: []pkgdmp.Field{{Type: "*User"}}},
+			{Name: "Close", Receiver: &pkgdmp.Field{Names: []string{"s"}, Type: "*Server"}},
+		},
+	}
+
+	var b strings.Builder
+
+	if err := cli.NewChecksumsEncoder(&b).Encode(pkg); err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	wantNames := []string{
+		"mypackage.ErrA",
+		"mypackage.ErrB",
+		"mypackage.MaxUsers",
+		"mypackage.NewUser",
+		"mypackage.Server.Close",
+		"mypackage.Store",
+		"mypackage.User",
+		"mypackage.User.Save",
+	}
+
+	if len(lines) != len(wantNames) {
+		t.Fatalf("expected %d lines; got %d:\n%s", len(wantNames), len(lines), b.String())
+	}
+
+	lineRe := regexp.MustCompile(`^(\S+)  ([0-9a-f]{64})$`)
+
+	for i, line := range lines {
+		m := lineRe.FindStringSubmatch(line)
+		if m == nil {
+			t.Fatalf("expected line %d to match %s; got %q", i+1, lineRe, line)
+		}
+
+		if m[1] != wantNames[i] {
+			t.Errorf("expected line %d to be for %s; got %s", i+1, wantNames[i], m[1])
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+// flagFunc describes a function or [flag.FlagSet] method defining a flag.
+type flagFunc struct {
+	typ     string // Type of the flag's value.
+	name    int    // Index of the flag name argument.
+	value   int    // Index of the default value argument, or -1 if none.
+	usage   int    // Index of the usage argument.
+	numArgs int    // Number of arguments.
+}
+
+// flagFuncs maps names of functions and [flag.FlagSet] methods defining
+// flags to how their arguments are interpreted.
+var flagFuncs = map[string]flagFunc{
+	"Bool":        {typ: "bool", name: 0, value: 1, usage: 2, numArgs: 3},
+	"BoolVar":     {typ: "bool", name: 1, value: 2, usage: 3, numArgs: 4},
+	"Duration":    {typ: "duration", name: 0, value: 1, usage: 2, numArgs: 3},
+	"DurationVar": {typ: "duration", name: 1, value: 2, usage: 3, numArgs: 4},
+	"Float64":     {typ: "float64", name: 0, value: 1, usage: 2, numArgs: 3},
+	"Float64Var":  {typ: "float64", name: 1, value: 2, usage: 3, numArgs: 4},
+	"Int":         {typ: "int", name: 0, value: 1, usage: 2, numArgs: 3},
+	"IntVar":      {typ: "int", name: 1, value: 2, usage: 3, numArgs: 4},
+	"Int64":       {typ: "int64", name: 0, value: 1, usage: 2, numArgs: 3},
+	"Int64Var":    {typ: "int64", name: 1, value: 2, usage: 3, numArgs: 4},
+	"String":      {typ: "string", name: 0, value: 1, usage: 2, numArgs: 3},
+	"StringVar":   {typ: "string", name: 1, value: 2, usage: 3, numArgs: 4},
+	"Uint":        {typ: "uint", name: 0, value: 1, usage: 2, numArgs: 3},
+	"UintVar":     {typ: "uint", name: 1, value: 2, usage: 3, numArgs: 4},
+	"Uint64":      {typ: "uint64", name: 0, value: 1, usage: 2, numArgs: 3},
+	"Uint64Var":   {typ: "uint64", name: 1, value: 2, usage: 3, numArgs: 4},
+	"Func":        {typ: "value", name: 0, value: -1, usage: 1, numArgs: 3},
+	"BoolFunc":    {typ: "bool", name: 0, value: -1, usage: 1, numArgs: 3},
+	"TextVar":     {typ: "value", name: 1, value: 2, usage: 3, numArgs: 4},
+	"Var":         {typ: "value", name: 1, value: -1, usage: 2, numArgs: 3},
+}
+
+// DetectFlags returns the command-line flags defined in the function bodies
+// of pkg with the standard library flag package, sorted by name.
+//
+// Flags are detected from calls to functions of the flag package and to
+// methods of the same names, such as [flag.FlagSet.StringVar], in files
+// importing the flag package. Only flags with names given as string literals
+// are detected, and usage strings that are not constant string expressions
+// are left empty. The first definition of a flag wins.
+//
+// DetectFlags must be called before the package is passed to [doc.New], as it
+// discards function bodies.
+//
+//nolint:staticcheck // ast.Package is required by doc.New.
+func DetectFlags(pkg *ast.Package) []pkgdmp.CommandFlag {
+	names := make([]string, 0, len(pkg.Files))
+
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	seen := make(map[string]struct{})
+
+	var res []pkgdmp.CommandFlag
+
+	for _, name := range names {
+		file := pkg.Files[name]
+
+		if !importsFlag(file) {
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			f, ok := commandFlag(call)
+			if !ok {
+				return true
+			}
+
+			if _, ok := seen[f.Name]; !ok {
+				seen[f.Name] = struct{}{}
+				res = append(res, f)
+			}
+
+			return true
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res
+}
+
+// importsFlag returns true if file imports the flag package.
+func importsFlag(file *ast.File) bool {
+	for _, imp := range file.Imports {
+		if imp.Path.Value == `"flag"` {
+			return true
+		}
+	}
+
+	return false
+}
+
+// commandFlag returns the flag defined by call, if it is a call to a function
+// or method defining a flag with a string literal name.
+func commandFlag(call *ast.CallExpr) (pkgdmp.CommandFlag, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return pkgdmp.CommandFlag{}, false
+	}
+
+	ff, ok := flagFuncs[sel.Sel.Name]
+	if !ok || len(call.Args) != ff.numArgs {
+		return pkgdmp.CommandFlag{}, false
+	}
+
+	lit, ok := call.Args[ff.name].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return pkgdmp.CommandFlag{}, false
+	}
+
+	name, err := strconv.Unquote(lit.Value)
+	if err != nil || name == "" {
+		return pkgdmp.CommandFlag{}, false
+	}
+
+	f := pkgdmp.CommandFlag{
+		Name:  name,
+		Type:  ff.typ,
+		Usage: constString(call.Args[ff.usage]),
+	}
+
+	if ff.value != -1 {
+		f.Default = types.ExprString(call.Args[ff.value])
+	}
+
+	return f, true
+}
+
+// constString returns the value of a string literal or a concatenation of
+// string literals, or an empty string for any other expression.
+func constString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return ""
+		}
+
+		s, err := strconv.Unquote(e.Value)
+		if err != nil {
+			return ""
+		}
+
+		return s
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return ""
+		}
+
+		return constString(e.X) + constString(e.Y)
+	case *ast.ParenExpr:
+		return constString(e.X)
+	default:
+		return ""
+	}
+}
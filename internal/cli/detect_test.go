@@ -0,0 +1,81 @@
+package cli_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestDetectFlags(t *testing.T) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "main.go", `package main
+
+import (
+	"flag"
+	"time"
+)
+
+const defaultName = "world"
+
+func main() {
+	_ = flag.String("name", defaultName, "name to greet")
+
+	var verbose bool
+	flag.BoolVar(&verbose, "v", false, "enable " + "verbose output")
+	flag.BoolVar(&verbose, "v", true, "duplicate definition")
+
+	fs := flag.NewFlagSet("sub", flag.ExitOnError)
+	fs.Duration("timeout", 5*time.Second, "request timeout")
+	fs.Func("header", usage(), func(string) error { return nil })
+
+	name := "dynamic"
+	fs.Int(name, 0, "not detected")
+}
+
+func usage() string { return "add a header" }
+`, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("error parsing source: %v", err)
+	}
+
+	//nolint:staticcheck // ast.Package is required by doc.New.
+	pkg := &ast.Package{Name: "main", Files: map[string]*ast.File{"main.go": file}}
+
+	want := []pkgdmp.CommandFlag{
+		{Name: "header", Type: "value"},
+		{Name: "name", Type: "string", Default: "defaultName", Usage: "name to greet"},
+		{Name: "timeout", Type: "duration", Default: "5 * time.Second", Usage: "request timeout"},
+		{Name: "v", Type: "bool", Default: "false", Usage: "enable verbose output"},
+	}
+
+	if got := cli.DetectFlags(pkg); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected flags:\n\n%+v\n\nbut got:\n\n%+v", want, got)
+	}
+}
+
+func TestDetectFlags_NoFlagImport(t *testing.T) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "main.go", `package main
+
+func main() {
+	cfg.String("name", "", "not a flag")
+}
+`, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("error parsing source: %v", err)
+	}
+
+	//nolint:staticcheck // ast.Package is required by doc.New.
+	pkg := &ast.Package{Name: "main", Files: map[string]*ast.File{"main.go": file}}
+
+	if got := cli.DetectFlags(pkg); len(got) != 0 {
+		t.Errorf("expected no flags, but got: %+v", got)
+	}
+}
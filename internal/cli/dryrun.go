@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+// WriteFilterCounts writes a table with counts of included and excluded
+// symbols per symbol type in filter results of the named package.
+//
+// Symbol types without any results are omitted.
+func WriteFilterCounts(w io.Writer, pkgName string, results []pkgdmp.FilterResult) error {
+	included := make(map[pkgdmp.SymbolType]int)
+	excluded := make(map[pkgdmp.SymbolType]int)
+
+	var types []pkgdmp.SymbolType
+
+	for _, r := range results {
+		st := r.Symbol.SymbolType()
+
+		if included[st] == 0 && excluded[st] == 0 {
+			types = append(types, st)
+		}
+
+		if r.Included {
+			included[st]++
+		} else {
+			excluded[st]++
+		}
+	}
+
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "package %s\n", pkgName)
+	fmt.Fprintf(tw, "\tSYMBOL TYPE\tINCLUDED\tEXCLUDED\n")
+
+	for _, st := range types {
+		fmt.Fprintf(tw, "\t%s\t%d\t%d\n", st, included[st], excluded[st])
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("writing filter counts for %s package: %w", pkgName, err)
+	}
+
+	return nil
+}
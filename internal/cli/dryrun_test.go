@@ -0,0 +1,66 @@
+package cli_test
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestWriteFilterCounts(t *testing.T) {
+	src := `package mypackage
+
+const MyConst = 1
+
+const myConst = 2
+
+func MyFunc(a int) {}
+
+func myFunc() {}
+
+func myOtherFunc() {}
+`
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "mypackage.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("error parsing source: %v", err)
+	}
+
+	dPkg, err := doc.NewFromFiles(fset, []*ast.File{file}, "example.com/mypackage", doc.AllDecls)
+	if err != nil {
+		t.Fatalf("error creating doc package: %v", err)
+	}
+
+	pkgParser, _ := pkgdmp.NewParser(
+		pkgdmp.WithFilterResults(),
+		pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude)),
+	)
+
+	if _, err := pkgParser.Package(dPkg); err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	var b strings.Builder
+
+	if err := cli.WriteFilterCounts(&b, "mypackage", pkgParser.FilterResults()); err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	want := `package mypackage
+  SYMBOL TYPE       INCLUDED  EXCLUDED
+  SymbolConst       1         1
+  SymbolFunc        1         2
+  SymbolParamField  1         0
+`
+
+	if got := b.String(); got != want {
+		t.Errorf("expected output:\n\n%s\nbut got:\n\n%s", want, got)
+	}
+}
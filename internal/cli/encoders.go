@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/michenriksen/pkgdmp"
+
+	"github.com/alecthomas/chroma/quick"
+)
+
+func init() {
+	pkgdmp.RegisterEncoder(FormatFlatJSON, pkgdmp.EncoderFunc(encodeFlatJSON))
+	pkgdmp.RegisterEncoder(FormatProto, packageEncoder(NewProtoEncoder))
+	pkgdmp.RegisterEncoder(FormatChecksums, packageEncoder(NewChecksumsEncoder))
+	pkgdmp.RegisterEncoder(FormatPlantUML, packageEncoder(NewPlantUMLEncoder))
+	pkgdmp.RegisterEncoder(FormatGodoc, packageEncoder(NewGodocEncoder))
+	pkgdmp.RegisterEncoder(FormatSummary, packageEncoder(NewSummaryEncoder))
+	pkgdmp.RegisterEncoder(FormatMarkdown, packageEncoder(NewMarkdownEncoder))
+}
+
+// StreamEncoder is a [pkgdmp.Encoder] that can write packages as they are
+// produced, so they do not all need to be held in memory.
+type StreamEncoder interface {
+	pkgdmp.Encoder
+
+	// EncodeStream writes the packages passed to the function given to each,
+	// returning the first error returned by each or from writing a package.
+	EncodeStream(w io.Writer, each func(fn func(*pkgdmp.Package) error) error) error
+}
+
+// packageEncoder returns a [pkgdmp.Encoder] encoding packages one at a time
+// with an encoder created by newEnc.
+func packageEncoder[E interface{ Encode(*pkgdmp.Package) error }](newEnc func(io.Writer) E) pkgdmp.Encoder {
+	return pkgdmp.EncoderFunc(func(w io.Writer, pkgs []*pkgdmp.Package) error {
+		enc := newEnc(w)
+
+		for _, pkg := range pkgs {
+			if err := enc.Encode(pkg); err != nil {
+				return err //nolint:wrapcheck // error is already wrapped.
+			}
+		}
+
+		return nil
+	})
+}
+
+// encodeFlatJSON writes the symbols of all packages as a single indented JSON
+// array of [pkgdmp.FlatSymbol].
+func encodeFlatJSON(w io.Writer, pkgs []*pkgdmp.Package) error {
+	// Symbols are encoded as an empty array rather than null if there are
+	// none, e.g. because all packages were skipped.
+	symbols := make([]pkgdmp.FlatSymbol, 0)
+
+	for _, pkg := range pkgs {
+		symbols = append(symbols, pkg.Symbols()...)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(symbols); err != nil {
+		return fmt.Errorf("encoding as JSON: %w", err)
+	}
+
+	return nil
+}
+
+// highlightEncoder writes the output of an encoder syntax highlighted as Go
+// source code.
+type highlightEncoder struct {
+	enc   pkgdmp.Encoder
+	theme string
+}
+
+// Encode writes pkgs with the wrapped encoder, syntax highlighted.
+func (e *highlightEncoder) Encode(w io.Writer, pkgs []*pkgdmp.Package) error {
+	var b strings.Builder
+
+	if err := e.enc.Encode(&b, pkgs); err != nil {
+		return err //nolint:wrapcheck // error is already wrapped.
+	}
+
+	if err := quick.Highlight(w, b.String(), "go", "terminal", e.theme); err != nil {
+		return fmt.Errorf("syntax highlighting source: %w", err)
+	}
+
+	return nil
+}
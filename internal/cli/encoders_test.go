@@ -0,0 +1,28 @@
+package cli_test
+
+import (
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestEncoders(t *testing.T) {
+	formats := []string{
+		cli.FormatText,
+		cli.FormatJSON,
+		cli.FormatFlatJSON,
+		cli.FormatProto,
+		cli.FormatChecksums,
+		cli.FormatPlantUML,
+		cli.FormatGodoc,
+		cli.FormatSummary,
+		cli.FormatMarkdown,
+	}
+
+	for _, format := range formats {
+		if _, ok := pkgdmp.LookupEncoder(format); !ok {
+			t.Errorf("expected encoder to be registered for %s format", format)
+		}
+	}
+}
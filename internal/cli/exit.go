@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"errors"
+	"go/scanner"
+	"go/types"
+	"io/fs"
+	"os"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Exit codes of the command, distinguishing classes of errors so scripts can
+// react to them differently.
+const (
+	ExitOK    = 0 // Success, or help or version requested.
+	ExitError = 1 // Any error not covered by the other exit codes.
+	ExitUsage = 2 // Invalid flags or arguments.
+	ExitParse = 3 // Source code could not be parsed or type checked.
+	ExitIO    = 4 // Files or directories could not be read or written.
+)
+
+// parseError wraps an error from parsing a package.
+type parseError struct {
+	err error
+}
+
+// ParseError returns err marked as an error from parsing a package, so
+// [ExitCode] returns [ExitParse] for it.
+func ParseError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &parseError{err: err}
+}
+
+func (e *parseError) Error() string {
+	return e.err.Error()
+}
+
+func (e *parseError) Unwrap() error {
+	return e.err
+}
+
+// usageError wraps an error from invalid flags or arguments.
+type usageError struct {
+	err error
+}
+
+// UsageError returns err marked as an error from invalid flags or arguments,
+// so [ExitCode] returns [ExitUsage] for it.
+func UsageError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &usageError{err: err}
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode returns the exit code to use for err.
+//
+// Errors marked with [UsageError] result in [ExitUsage]. Errors marked with
+// [ParseError] and syntax and type errors in source code
+// result in [ExitParse], and file system errors in [ExitIO]. Any other error
+// results in [ExitError].
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	if errors.As(err, new(*usageError)) {
+		return ExitUsage
+	}
+
+	var (
+		pErr    *parseError
+		sErr    scanner.ErrorList
+		sErrOne *scanner.Error
+		pkgErr  packages.Error
+		tErr    types.Error
+	)
+
+	if errors.As(err, &pErr) || errors.As(err, &sErr) || errors.As(err, &sErrOne) ||
+		errors.As(err, &pkgErr) || errors.As(err, &tErr) {
+		return ExitParse
+	}
+
+	var (
+		pathErr    *fs.PathError
+		linkErr    *os.LinkError
+		syscallErr *os.SyscallError
+	)
+
+	if errors.As(err, &pathErr) || errors.As(err, &linkErr) || errors.As(err, &syscallErr) {
+		return ExitIO
+	}
+
+	return ExitError
+}
@@ -0,0 +1,76 @@
+package cli_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestExitCode(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.go")
+
+	writeFile(t, invalid, "package mypackage\n\nfunc MyFunc( {}\n")
+
+	_, _, syntaxErr := (&cli.Config{}).LoadPackages(invalid)
+	_, _, ioErr := (&cli.Config{}).LoadPackages(filepath.Join(dir, "nonexistent"))
+	_, usageCode, _ := cli.ParseFlags([]string{"-format", "invalid", "directory"}, io.Discard)
+	_, helpCode, _ := cli.ParseFlags([]string{"-help"}, io.Discard)
+	_, _, flagValueErr := cli.ParseFlags([]string{"-max-methods", "-1", "directory"}, io.Discard)
+
+	if usageCode != cli.ExitUsage {
+		t.Errorf("expected exit code %d for usage error, but got %d", cli.ExitUsage, usageCode)
+	}
+
+	if helpCode != cli.ExitOK {
+		t.Errorf("expected exit code %d for help, but got %d", cli.ExitOK, helpCode)
+	}
+
+	tt := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"no error", nil, cli.ExitOK},
+		{"other error", errors.New("something went wrong"), cli.ExitError},
+		{"syntax error", syntaxErr, cli.ExitParse},
+		{"marked parse error", cli.ParseError(errors.New("mypackage package has 1 unsupported declarations")), cli.ExitParse},
+		{"wrapped parse error", fmt.Errorf("parsing: %w", cli.ParseError(errors.New("error"))), cli.ExitParse},
+		{"file system error", ioErr, cli.ExitIO},
+		{"marked usage error", cli.UsageError(errors.New("max methods must be a non-negative integer")), cli.ExitUsage},
+		{"invalid flag value", flagValueErr, cli.ExitUsage},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.want != cli.ExitOK && tc.err == nil {
+				t.Fatal("expected test case to have an error")
+			}
+
+			if got := cli.ExitCode(tc.err); got != tc.want {
+				t.Errorf("expected exit code %d for %v, but got %d", tc.want, tc.err, got)
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	if err := cli.ParseError(nil); err != nil {
+		t.Errorf("expected nil error, but got: %v", err)
+	}
+
+	wrapped := errors.New("parsing mypackage package: error")
+	err := cli.ParseError(wrapped)
+
+	if err.Error() != wrapped.Error() {
+		t.Errorf("expected error message %q, but got %q", wrapped.Error(), err.Error())
+	}
+
+	if !errors.Is(err, wrapped) {
+		t.Error("expected parse error to wrap original error")
+	}
+}
@@ -1,16 +1,24 @@
 package cli
 
 import (
+	"bufio"
 	"errors"
 	"flag"
 	"fmt"
+	"go/build"
+	"go/token"
 	"io"
+	"io/fs"
 	"os"
+	"path"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/michenriksen/pkgdmp"
 )
@@ -20,8 +28,42 @@ const flagEnvPrfx = "PKGDMP"
 const (
 	themesURL    = "https://xyproto.github.io/splash/docs/"
 	defaultTheme = "swapoff"
+	defaultWrap  = 80
+
+	defaultStdinName = "stdin.go"
+)
+
+// Output formats of the built-in encoders and the encoders registered by the
+// package. Any format registered with [pkgdmp.RegisterEncoder] is supported.
+const (
+	FormatText      = "text"
+	FormatJSON      = "json"
+	FormatFlatJSON  = "flat-json"
+	FormatProto     = "proto"
+	FormatChecksums = "checksums"
+	FormatPlantUML  = "plantuml"
+	FormatGodoc     = "godoc"
+	FormatSummary   = "summary-json"
+	FormatMarkdown  = "markdown"
+)
+
+// Supported color modes.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+var supportedColors = []string{ColorAuto, ColorAlways, ColorNever}
+
+// Supported package loaders.
+const (
+	LoaderParser   = "parser"
+	LoaderPackages = "packages"
 )
 
+var supportedLoaders = []string{LoaderParser, LoaderPackages}
+
 const versionTmpl = `%s:
   Version:    %s
   Go version: %s
@@ -40,6 +82,7 @@ var symbolTypeMap = map[string]pkgdmp.SymbolType{
 	"mapType":   pkgdmp.SymbolMapType,
 	"method":    pkgdmp.SymbolMethod,
 	"struct":    pkgdmp.SymbolStructType,
+	"var":       pkgdmp.SymbolVar,
 }
 
 var (
@@ -48,30 +91,140 @@ var (
 
 	// ErrVersion is returned by [ParseFlags] if the -version flag is specified.
 	ErrVersion = errors.New("version")
+
+	// ErrFormat is returned by [ParseFlags] if the -format flag specifies an
+	// unsupported output format.
+	ErrFormat = errors.New("unsupported output format")
+
+	// ErrColor is returned by [ParseFlags] if the -color flag specifies an
+	// unsupported color mode.
+	ErrColor = errors.New("unsupported color mode")
+
+	// ErrLoader is returned by [ParseFlags] if the -loader flag specifies an
+	// unsupported package loader.
+	ErrLoader = errors.New("unsupported package loader")
+
+	// ErrPackageName is returned by [ParseFlags] if the -as flag specifies an
+	// invalid package name.
+	ErrPackageName = errors.New("invalid package name")
+
+	// ErrSymbolName is returned by [ParseFlags] if the -closure or
+	// -method-diff flag or a symbol selector specifies an invalid symbol
+	// name.
+	ErrSymbolName = errors.New("invalid symbol name")
+
+	// ErrExportedConflict is returned by [ParseFlags] if the -exported flag is
+	// combined with the -unexported or -unexported-for flag.
+	ErrExportedConflict = errors.New("conflicting exported and unexported flags")
+
+	// ErrMethodDiffFormat is returned by [ParseFlags] if the -method-diff flag
+	// is combined with an output format other than text.
+	ErrMethodDiffFormat = errors.New("method diff only supports text output")
+
+	// ErrFilePattern is returned by [ParseFlags] if the -only-files or
+	// -exclude-files flag contains a malformed glob pattern.
+	ErrFilePattern = errors.New("malformed file pattern")
+
+	// ErrFlagValue is returned by [ParseFlags] if a flag value is rejected by
+	// the parser options it configures, such as a negative -max-methods or a
+	// malformed -matching regular expression.
+	ErrFlagValue = errors.New("invalid flag value")
 )
 
 var flagSet *flag.FlagSet
 
 // Config represents CLI configuration from flags.
 type Config struct {
-	onlyPackages    map[string]struct{}
-	excludePackages map[string]struct{}
-	ExcludePackages string
-	Only            string
-	ExcludeMatching string
-	Theme           string
-	Matching        string
-	OnlyPackages    string
-	Exclude         string
-	Dirs            []string `env:"skip"`
-	NoDocs          bool
-	NoTags          bool
-	NoHighlight     bool
-	FullDocs        bool
-	Unexported      bool
-	Version         bool `env:"skip"`
-	NoEnv           bool `env:"skip"`
-	JSON            bool
+	onlyPackages        map[string]struct{}
+	excludePackages     map[string]struct{}
+	onlyFiles           []string
+	excludeFiles        []string
+	forceColor          bool
+	fset                *token.FileSet
+	ExcludePackages     string
+	OnlyFiles           string
+	ExcludeFiles        string
+	ExcludeGenerated    bool
+	Only                string
+	ExcludeMatching     string
+	Theme               string
+	Color               string
+	Matching            string
+	MatchingType        string
+	ExcludeMatchingType string
+	Marker              string
+	ExcludeMarker       string
+	Receiver            string
+	ExcludeReceiver     string
+	OnlyPackages        string
+	Exclude             string
+	Format              string
+	TagsKeep            string
+	TagsDrop            string
+	StdinName           string
+	Cache               string
+	Loader              string
+	As                  string
+	Closure             string
+	MethodDiff          string `env:"skip"`
+	MethodDiffWith      string `env:"skip"`
+	ByImportPath        bool
+	Dedupe              bool
+	SkipEmpty           bool
+	GroupByKind         bool
+	Imports             bool
+	QualifyImports      bool
+	PromoteEmbedded     bool
+	ExpandInterfaces    bool
+	FlattenConsts       bool
+	DryRun              bool
+	FilterParams        bool
+	Strict              bool
+	ShowZeroValues      bool
+	ResolveUnderlying   bool
+	JSONEnvelope        bool
+	BuildInfo           bool
+	Header              bool
+	NoEmptyGroups       bool
+	Compact             bool
+	MethodComments      bool
+	Sort                bool
+	GroupConstructors   bool
+	IncludeExamples     bool
+	DetectFlags         bool
+	MinParams           string
+	MaxParams           string
+	MinResults          string
+	MaxResults          string
+	NoReceiverNames     bool
+	NoReceiver          bool
+	Dirs                []string `env:"skip"`
+	Symbols             []string `env:"skip"`
+	MinNameLen          int
+	MaxNameLen          int
+	MaxMethods          int
+	Wrap                int
+	WrapSignatures      int
+	Signatures          bool
+	NoDocs              bool
+	NoComments          bool
+	NoTags              bool
+	NoMethods           bool
+	PlainDocs           bool
+	KeepDirectives      bool
+	ParseDocSections    bool
+	NoHighlight         bool
+	FullDocs            bool
+	FullPackageDoc      bool
+	Exported            bool
+	Unexported          bool
+	UnexportedFor       string
+	StripInternalTypes  bool
+	RelativePaths       bool
+	API                 bool
+	Version             bool `env:"skip"`
+	NoEnv               bool `env:"skip"`
+	JSON                bool
 }
 
 // IncludePackage returns true if package with provided name should be included
@@ -91,6 +244,144 @@ func (c *Config) IncludePackage(name string) bool {
 	return true
 }
 
+// IncludeSourcePackage returns true if pkg should be included in the report
+// according to configuration, or false otherwise.
+//
+// If the -by-import-path flag is specified, the package is matched by its
+// import path, e.g. `net/http`, instead of its name, falling back to its name
+// if the import path is not known.
+func (c *Config) IncludeSourcePackage(pkg SourcePackage) bool {
+	if c.ByImportPath && pkg.ImportPath != "" {
+		return c.IncludePackage(pkg.ImportPath)
+	}
+
+	return c.IncludePackage(pkg.Name)
+}
+
+// IncludeFile returns true if a source file with provided base name should be
+// parsed according to configuration, or false otherwise.
+func (c *Config) IncludeFile(name string) bool {
+	if len(c.onlyFiles) != 0 && !matchAny(c.onlyFiles, name) {
+		return false
+	}
+
+	return !matchAny(c.excludeFiles, name)
+}
+
+// SourceFileFilter returns a function for filtering source files in dir to
+// parse, suitable for [parser.ParseDir].
+//
+// Test files, files excluded by configuration, and files with build
+// constraints excluding them from builds for the current target, such as
+// `//go:build ignore` or `//go:build tools`, are filtered out. Test files are
+// kept if the -include-examples flag is specified.
+func (c *Config) SourceFileFilter(dir string) func(fs.FileInfo) bool {
+	return c.FSFileFilter(os.DirFS(dir), ".")
+}
+
+// FSFileFilter returns a function for filtering source files in directory
+// dir of fsys to parse, like [Config.SourceFileFilter] does for directories
+// on the OS filesystem.
+func (c *Config) FSFileFilter(fsys fs.FS, dir string) func(fs.FileInfo) bool {
+	ctx := build.Default
+	ctx.JoinPath = path.Join
+	ctx.OpenFile = func(name string) (io.ReadCloser, error) {
+		return fsys.Open(name) //nolint:wrapcheck // error is returned as is by go/build.
+	}
+
+	return func(fi fs.FileInfo) bool {
+		if strings.HasSuffix(fi.Name(), "_test.go") && !c.IncludeExamples {
+			return false
+		}
+
+		if !c.IncludeFile(fi.Name()) {
+			return false
+		}
+
+		if c.ExcludeGenerated && isGeneratedFile(fsys, path.Join(dir, fi.Name())) {
+			return false
+		}
+
+		// Files that cannot be read are included to let the parser report
+		// the error.
+		match, err := ctx.MatchFile(dir, fi.Name())
+
+		return match || err != nil
+	}
+}
+
+// FileSet returns the file set to parse source files with.
+//
+// The same file set is returned on every call, as it is needed both when
+// loading packages and when printing the code of examples.
+func (c *Config) FileSet() *token.FileSet {
+	if c.fset == nil {
+		c.fset = token.NewFileSet()
+	}
+
+	return c.fset
+}
+
+// OutputFormat returns the configured output format.
+//
+// Returns [FormatJSON] if the -json flag is specified.
+func (c *Config) OutputFormat() string {
+	if c.JSON {
+		return FormatJSON
+	}
+
+	return c.Format
+}
+
+// Encoder returns the encoder registered with [pkgdmp.RegisterEncoder] for
+// the configured output format, with the configured output options applied,
+// where terminal indicates whether output is written to a terminal.
+//
+// JSON output is written by a [JSONEncoder] with the configured envelope and
+// grouping, and text output is syntax highlighted if configured.
+func (c *Config) Encoder(terminal bool) (pkgdmp.Encoder, error) {
+	format := c.OutputFormat()
+
+	enc, ok := pkgdmp.LookupEncoder(format)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrFormat, format)
+	}
+
+	switch {
+	case format == FormatJSON:
+		jsonEnc := &JSONEncoder{Envelope: c.JSONEnvelope, GroupByKind: c.GroupByKind}
+
+		if c.BuildInfo {
+			info := NewBuildInfo()
+			jsonEnc.BuildInfo = &info
+		}
+
+		return jsonEnc, nil
+	case format == FormatText && c.Highlight(terminal):
+		return &highlightEncoder{enc: enc, theme: c.Theme}, nil
+	}
+
+	return enc, nil
+}
+
+// Highlight returns true if source output should be syntax highlighted
+// according to the color mode, where terminal indicates whether output is
+// written to a terminal.
+//
+// In auto mode, output is highlighted if it is written to a terminal, or if
+// the CLICOLOR_FORCE environment variable is set, and highlighting is not
+// disabled by environment variables.
+func (c *Config) Highlight(terminal bool) bool {
+	switch c.Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		return (terminal || c.forceColor) && !c.NoHighlight
+	}
+}
+
 // ParseFlags parses command line arguments as flags and returns a CLI
 // configuration together with exit code to use if error is also returned.
 func ParseFlags(args []string, output io.Writer) (*Config, int, error) {
@@ -103,28 +394,105 @@ func ParseFlags(args []string, output io.Writer) (*Config, int, error) {
 			fmt.Fprintf(output, "%v\n\n", err)
 			flagSet.Usage()
 
-			return nil, 1, err //nolint:wrapcheck // no need to wrap error.
+			return nil, ExitUsage, err //nolint:wrapcheck // no need to wrap error.
 		}
 
-		return nil, 0, err //nolint:wrapcheck // no need to wrap error.
+		return nil, ExitOK, err //nolint:wrapcheck // no need to wrap error.
 	}
 
 	if cfg.Version {
 		fmt.Fprintf(output, versionTmpl, AppName, Version(), BuildGoVersion(), BuildCommit(), BuildTime())
-		return nil, 0, ErrVersion
+		return nil, ExitOK, ErrVersion
 	}
 
-	if len(flagSet.Args()) == 0 {
+	posArgs := flagSet.Args()
+
+	// The -method-diff flag takes the name of the second type as the first
+	// argument after it.
+	if cfg.MethodDiff != "" {
+		if len(posArgs) != 0 {
+			cfg.MethodDiffWith, posArgs = posArgs[0], posArgs[1:]
+		}
+
+		for _, name := range []string{cfg.MethodDiff, cfg.MethodDiffWith} {
+			if !token.IsIdentifier(name) {
+				fmt.Fprintf(output, "invalid symbol name: %q\n\n", name)
+				flagSet.Usage()
+
+				return nil, ExitUsage, ErrSymbolName
+			}
+		}
+	}
+
+	cfg.Dirs, cfg.Symbols = splitArgs(posArgs)
+
+	if len(cfg.Dirs) == 0 {
 		fmt.Fprintf(output, "no directories specified\n\n")
 		flagSet.Usage()
 
-		return nil, 1, ErrNoDirs
+		return nil, ExitUsage, ErrNoDirs
 	}
 
-	cfg.Dirs = flagSet.Args()
+	for _, sel := range cfg.Symbols {
+		if !isSymbolSelector(sel) {
+			fmt.Fprintf(output, "invalid symbol name: %q\n\n", sel)
+			flagSet.Usage()
+
+			return nil, ExitUsage, ErrSymbolName
+		}
+	}
 
 	envConfig(cfg)
 
+	if !isSupported(pkgdmp.Encoders(), cfg.Format) {
+		fmt.Fprintf(output, "unsupported output format: %q\n\n", cfg.Format)
+		flagSet.Usage()
+
+		return nil, ExitUsage, ErrFormat
+	}
+
+	if !isSupported(supportedColors, cfg.Color) {
+		fmt.Fprintf(output, "unsupported color mode: %q\n\n", cfg.Color)
+		flagSet.Usage()
+
+		return nil, ExitUsage, ErrColor
+	}
+
+	if !isSupported(supportedLoaders, cfg.Loader) {
+		fmt.Fprintf(output, "unsupported package loader: %q\n\n", cfg.Loader)
+		flagSet.Usage()
+
+		return nil, ExitUsage, ErrLoader
+	}
+
+	if cfg.As != "" && (!token.IsIdentifier(cfg.As) || cfg.As == "_") {
+		fmt.Fprintf(output, "invalid package name: %q\n\n", cfg.As)
+		flagSet.Usage()
+
+		return nil, ExitUsage, ErrPackageName
+	}
+
+	if cfg.Closure != "" && !token.IsIdentifier(cfg.Closure) {
+		fmt.Fprintf(output, "invalid symbol name: %q\n\n", cfg.Closure)
+		flagSet.Usage()
+
+		return nil, ExitUsage, ErrSymbolName
+	}
+
+	if cfg.Exported && (cfg.Unexported || cfg.UnexportedFor != "") {
+		fmt.Fprintf(output, "-exported cannot be combined with -unexported or -unexported-for\n\n")
+		flagSet.Usage()
+
+		return nil, ExitUsage, ErrExportedConflict
+	}
+
+	if cfg.MethodDiff != "" && cfg.OutputFormat() != FormatText {
+		fmt.Fprintf(output, "-method-diff only supports the %s output format\n\n", FormatText)
+		flagSet.Usage()
+
+		return nil, ExitUsage, ErrMethodDiffFormat
+	}
+
 	if cfg.OnlyPackages != "" {
 		names := strings.Split(cfg.OnlyPackages, ",")
 		cfg.onlyPackages = make(map[string]struct{}, len(names))
@@ -153,7 +521,36 @@ func ParseFlags(args []string, output io.Writer) (*Config, int, error) {
 		}
 	}
 
-	return cfg, 0, nil
+	cfg.onlyFiles = splitList(cfg.OnlyFiles)
+	cfg.excludeFiles = splitList(cfg.ExcludeFiles)
+
+	for _, patterns := range [][]string{cfg.onlyFiles, cfg.excludeFiles} {
+		for _, pattern := range patterns {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				fmt.Fprintf(output, "malformed file pattern: %q\n\n", pattern)
+				flagSet.Usage()
+
+				return nil, ExitUsage, ErrFilePattern
+			}
+		}
+	}
+
+	// Values of flags configuring parser options are validated by the
+	// options, so a parser is created to report invalid values as usage
+	// errors before any packages are loaded.
+	opts, err := ParserOptsFromCfg(cfg)
+	if err == nil {
+		_, err = pkgdmp.NewParser(opts...)
+	}
+
+	if err != nil {
+		fmt.Fprintf(output, "invalid flag value: %v\n\n", err)
+		flagSet.Usage()
+
+		return nil, ExitUsage, UsageError(fmt.Errorf("%w: %w", ErrFlagValue, err))
+	}
+
+	return cfg, ExitOK, nil
 }
 
 // ParserOptsFromCfg constructs parser options from CLI configuration.
@@ -164,14 +561,135 @@ func ParserOptsFromCfg(cfg *Config) ([]pkgdmp.ParserOption, error) {
 		opts = append(opts, pkgdmp.WithFullDocs())
 	}
 
-	if cfg.NoDocs {
+	if cfg.FullPackageDoc {
+		opts = append(opts, pkgdmp.WithFullPackageDoc())
+	}
+
+	// The -signatures preset implies -no-docs, -no-tags, and -no-methods.
+	if cfg.NoDocs || cfg.Signatures {
 		opts = append(opts, pkgdmp.WithNoDocs())
 	}
 
-	if cfg.NoTags {
+	if cfg.NoComments {
+		opts = append(opts, pkgdmp.WithNoComments())
+	}
+
+	if cfg.PlainDocs {
+		opts = append(opts, pkgdmp.WithPlainDocs())
+	}
+
+	if cfg.KeepDirectives {
+		opts = append(opts, pkgdmp.WithKeepDirectives())
+	}
+
+	if cfg.ParseDocSections {
+		opts = append(opts, pkgdmp.WithDocSections())
+	}
+
+	if cfg.NoTags || cfg.Signatures {
 		opts = append(opts, pkgdmp.WithNoTags())
 	}
 
+	if keys := splitList(cfg.TagsKeep); len(keys) != 0 {
+		opts = append(opts, pkgdmp.WithTagKeys(pkgdmp.Include, keys...))
+	}
+
+	if keys := splitList(cfg.TagsDrop); len(keys) != 0 {
+		opts = append(opts, pkgdmp.WithTagKeys(pkgdmp.Exclude, keys...))
+	}
+
+	if cfg.NoMethods || cfg.Signatures {
+		opts = append(opts, pkgdmp.WithNoMethods())
+	}
+
+	if cfg.NoReceiverNames {
+		opts = append(opts, pkgdmp.WithNoReceiverNames())
+	}
+
+	if cfg.NoReceiver {
+		opts = append(opts, pkgdmp.WithNoReceivers())
+	}
+
+	if cfg.GroupByKind {
+		opts = append(opts, pkgdmp.WithGroupByKind())
+	}
+
+	if cfg.Imports {
+		opts = append(opts, pkgdmp.WithImports())
+	}
+
+	if cfg.QualifyImports {
+		opts = append(opts, pkgdmp.WithQualifiedImports())
+	}
+
+	if cfg.FilterParams {
+		opts = append(opts, pkgdmp.WithIncludeUnfilterable())
+	}
+
+	if cfg.DryRun {
+		opts = append(opts, pkgdmp.WithFilterResults())
+	}
+
+	if cfg.FlattenConsts {
+		opts = append(opts, pkgdmp.WithFlattenSingleConsts())
+	}
+
+	if cfg.PromoteEmbedded {
+		opts = append(opts, pkgdmp.WithPromoteEmbedded())
+	}
+
+	if cfg.ExpandInterfaces {
+		opts = append(opts, pkgdmp.WithExpandEmbeddedInterfaces())
+	}
+
+	if cfg.ResolveUnderlying {
+		opts = append(opts, pkgdmp.WithResolveUnderlying())
+	}
+
+	if cfg.ShowZeroValues {
+		opts = append(opts, pkgdmp.WithZeroValues())
+	}
+
+	if cfg.NoEmptyGroups {
+		opts = append(opts, pkgdmp.WithNoEmptyGroups())
+	}
+
+	if cfg.Compact {
+		opts = append(opts, pkgdmp.WithCompact())
+	}
+
+	if cfg.MethodComments {
+		opts = append(opts, pkgdmp.WithMethodComments())
+	}
+
+	if cfg.Sort {
+		opts = append(opts, pkgdmp.WithSortMethods())
+	}
+
+	if cfg.GroupConstructors {
+		opts = append(opts, pkgdmp.WithGroupConstructors())
+	}
+
+	if cfg.IncludeExamples {
+		opts = append(opts, pkgdmp.WithExamples(cfg.FileSet()))
+	}
+
+	if cfg.StripInternalTypes {
+		opts = append(opts, pkgdmp.WithStripInternalTypes())
+	}
+
+	if cfg.MaxMethods != 0 {
+		opts = append(opts, pkgdmp.WithMaxMethods(cfg.MaxMethods))
+	}
+
+	if cfg.Wrap != defaultWrap {
+		opts = append(opts, pkgdmp.WithWrap(cfg.Wrap))
+	}
+
+	if cfg.WrapSignatures != 0 {
+		opts = append(opts, pkgdmp.WithWrapSignatures(cfg.WrapSignatures))
+	}
+
 	filters, err := filtersFromCfg(cfg)
 	if err != nil {
 		return nil, err
@@ -187,7 +705,18 @@ func ParserOptsFromCfg(cfg *Config) ([]pkgdmp.ParserOption, error) {
 func filtersFromCfg(cfg *Config) ([]pkgdmp.SymbolFilter, error) {
 	var filters []pkgdmp.SymbolFilter
 
-	if !cfg.Unexported {
+	switch {
+	case cfg.API, cfg.Exported:
+		filters = append(filters, pkgdmp.FilterUnexported(pkgdmp.Exclude))
+	case cfg.Unexported:
+	case cfg.UnexportedFor != "":
+		st, err := strToSymbolTypes(cfg.UnexportedFor)
+		if err != nil {
+			return nil, fmt.Errorf("parsing symbol types: %w", err)
+		}
+
+		filters = append(filters, pkgdmp.FilterUnexportedForTypes(pkgdmp.Include, st...))
+	default:
 		filters = append(filters, pkgdmp.FilterUnexported(pkgdmp.Exclude))
 	}
 
@@ -227,6 +756,83 @@ func filtersFromCfg(cfg *Config) ([]pkgdmp.SymbolFilter, error) {
 		filters = append(filters, pkgdmp.FilterMatchingIdents(pkgdmp.Exclude, p))
 	}
 
+	if cfg.MatchingType != "" {
+		p, err := regexp.Compile(cfg.MatchingType)
+		if err != nil {
+			return nil, fmt.Errorf("parsing matching type regular expression: %w", err)
+		}
+
+		filters = append(filters, pkgdmp.FilterMatchingTypes(pkgdmp.Include, p))
+	}
+
+	if cfg.ExcludeMatchingType != "" {
+		p, err := regexp.Compile(cfg.ExcludeMatchingType)
+		if err != nil {
+			return nil, fmt.Errorf("parsing exclude matching type regular expression: %w", err)
+		}
+
+		filters = append(filters, pkgdmp.FilterMatchingTypes(pkgdmp.Exclude, p))
+	}
+
+	if cfg.Receiver != "" {
+		p, err := regexp.Compile(cfg.Receiver)
+		if err != nil {
+			return nil, fmt.Errorf("parsing receiver regular expression: %w", err)
+		}
+
+		filters = append(filters, pkgdmp.FilterReceiver(pkgdmp.Include, p))
+	}
+
+	if cfg.ExcludeReceiver != "" {
+		p, err := regexp.Compile(cfg.ExcludeReceiver)
+		if err != nil {
+			return nil, fmt.Errorf("parsing exclude receiver regular expression: %w", err)
+		}
+
+		filters = append(filters, pkgdmp.FilterReceiver(pkgdmp.Exclude, p))
+	}
+
+	if cfg.Marker != "" {
+		filters = append(filters, pkgdmp.FilterDocMarker(pkgdmp.Include, cfg.Marker))
+	}
+
+	if cfg.ExcludeMarker != "" {
+		filters = append(filters, pkgdmp.FilterDocMarker(pkgdmp.Exclude, cfg.ExcludeMarker))
+	}
+
+	arity, err := arityFilterFromCfg(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if arity != nil {
+		filters = append(filters, arity)
+	}
+
+	if cfg.MinNameLen < 0 {
+		return nil, fmt.Errorf("minimum name length must be a positive integer, got %d", cfg.MinNameLen)
+	}
+
+	if cfg.MinNameLen > 0 {
+		minLen := cfg.MinNameLen
+
+		filters = append(filters, pkgdmp.FilterNamePredicate(pkgdmp.Exclude, func(name string) bool {
+			return utf8.RuneCountInString(name) < minLen
+		}))
+	}
+
+	if cfg.MaxNameLen < 0 {
+		return nil, fmt.Errorf("maximum name length must be a positive integer, got %d", cfg.MaxNameLen)
+	}
+
+	if cfg.MaxNameLen > 0 {
+		maxLen := cfg.MaxNameLen
+
+		filters = append(filters, pkgdmp.FilterNamePredicate(pkgdmp.Exclude, func(name string) bool {
+			return utf8.RuneCountInString(name) > maxLen
+		}))
+	}
+
 	return filters, nil
 }
 
@@ -243,35 +849,249 @@ func initFlagSet(cfg *Config, output io.Writer) {
 	flagSet.StringVar(&cfg.ExcludeMatching, "exclude-matching", "",
 		flagDescf("ExcludeMatching", "exclude symbols with names matching regular expression"),
 	)
+	flagSet.StringVar(&cfg.MatchingType, "matching-type", "",
+		flagDescf("MatchingType",
+			"only include struct fields and functions with types or signatures matching regular expression",
+		),
+	)
+	flagSet.StringVar(&cfg.ExcludeMatchingType, "exclude-matching-type", "",
+		flagDescf("ExcludeMatchingType",
+			"exclude struct fields and functions with types or signatures matching regular expression",
+		),
+	)
+	flagSet.BoolVar(&cfg.FilterParams, "filter-params", false,
+		flagDescf("FilterParams", "apply name filters to function parameters and results"),
+	)
+	flagSet.StringVar(&cfg.Receiver, "receiver", "",
+		flagDescf("Receiver", "only include methods with receiver type names matching regular expression"),
+	)
+	flagSet.StringVar(&cfg.ExcludeReceiver, "exclude-receiver", "",
+		flagDescf("ExcludeReceiver", "exclude methods with receiver type names matching regular expression"),
+	)
+	flagSet.StringVar(&cfg.Marker, "marker", "",
+		flagDescf("Marker", "only include symbols with a doc comment line starting with `MARKER`, e.g. \"Stable:\""),
+	)
+	flagSet.StringVar(&cfg.ExcludeMarker, "exclude-marker", "",
+		flagDescf("ExcludeMarker", "exclude symbols with a doc comment line starting with `MARKER`, e.g. \"Experimental:\""),
+	)
+	flagSet.BoolVar(&cfg.Exported, "exported", false,
+		flagDescf("Exported",
+			"only include exported entities, as by default; cannot be combined with -unexported or -unexported-for",
+		),
+	)
 	flagSet.BoolVar(&cfg.Unexported, "unexported", false,
 		flagDescf("Unexported", "include unexported entities"),
 	)
+	flagSet.BoolVar(&cfg.StripInternalTypes, "strip-internal-types", false,
+		flagDescf("StripInternalTypes",
+			"render references to unexported types in signatures as 'any', marked with a comment",
+		),
+	)
+	flagSet.StringVar(&cfg.UnexportedFor, "unexported-for", "",
+		flagDescf("UnexportedFor", "comma-separated list of symbol types to include unexported entities of"),
+	)
+	flagSet.StringVar(&cfg.MinParams, "min-params", "",
+		flagDescf("MinParams", "only include functions with at least `N` parameters"),
+	)
+	flagSet.StringVar(&cfg.MaxParams, "max-params", "",
+		flagDescf("MaxParams", "only include functions with at most `N` parameters"),
+	)
+	flagSet.StringVar(&cfg.MinResults, "min-results", "",
+		flagDescf("MinResults", "only include functions with at least `N` results"),
+	)
+	flagSet.StringVar(&cfg.MaxResults, "max-results", "",
+		flagDescf("MaxResults", "only include functions with at most `N` results"),
+	)
+	flagSet.IntVar(&cfg.MinNameLen, "min-name-len", 0,
+		flagDescf("MinNameLen", "exclude symbols with names shorter than N characters"),
+	)
+	flagSet.IntVar(&cfg.MaxNameLen, "max-name-len", 0,
+		flagDescf("MaxNameLen", "exclude symbols with names longer than N characters"),
+	)
 	flagSet.StringVar(&cfg.Only, "only", "",
 		flagDescf("Only", "comma-separated list of symbol types to include"),
 	)
 	flagSet.StringVar(&cfg.Exclude, "exclude", "",
 		flagDescf("Exclude", "comma-separated list of symbol types to exclude"),
 	)
+	flagSet.StringVar(&cfg.ExcludeFiles, "exclude-files", "",
+		flagDescf("ExcludeFiles", "comma-separated list of glob patterns for file names to exclude"),
+	)
+	flagSet.StringVar(&cfg.OnlyFiles, "only-files", "",
+		flagDescf("OnlyFiles", "comma-separated list of glob patterns for file names to include"),
+	)
+	flagSet.BoolVar(&cfg.ExcludeGenerated, "exclude-generated", false,
+		flagDescf("ExcludeGenerated", "exclude files marked as generated code"),
+	)
 	flagSet.StringVar(&cfg.ExcludePackages, "exclude-packages", "",
 		flagDescf("ExcludePackages", "comma-separated list of package names to exclude"),
 	)
 	flagSet.StringVar(&cfg.OnlyPackages, "only-packages", "",
 		flagDescf("OnlyPackages", "comma-separated list of package names to include"),
 	)
+	flagSet.BoolVar(&cfg.ByImportPath, "by-import-path", false,
+		flagDescf("ByImportPath", "match -only-packages and -exclude-packages against import paths instead of names"),
+	)
+	flagSet.BoolVar(&cfg.Dedupe, "dedupe", false,
+		flagDescf("Dedupe", "omit symbols identical to symbols of previously dumped packages"),
+	)
+	flagSet.BoolVar(&cfg.SkipEmpty, "skip-empty", false,
+		flagDescf("SkipEmpty", "omit packages without any symbols left after filtering"),
+	)
+	flagSet.BoolVar(&cfg.Signatures, "signatures", false,
+		flagDescf("Signatures", "only include signatures; shorthand for -no-docs -no-tags -no-methods"),
+	)
 	flagSet.BoolVar(&cfg.NoDocs, "no-docs", false,
 		flagDescf("NoDocs", "exclude doc comments"),
 	)
+	flagSet.BoolVar(&cfg.NoComments, "no-comments", false,
+		flagDescf("NoComments", "exclude trailing line comments of fields and methods"),
+	)
 	flagSet.BoolVar(&cfg.NoTags, "no-tags", false,
 		flagDescf("NoTags", "exclude struct field tags"),
 	)
+	flagSet.StringVar(&cfg.TagsKeep, "tags-keep", "",
+		flagDescf("TagsKeep", "comma-separated list of struct field tag keys to include"),
+	)
+	flagSet.StringVar(&cfg.TagsDrop, "tags-drop", "",
+		flagDescf("TagsDrop", "comma-separated list of struct field tag keys to exclude"),
+	)
+	flagSet.BoolVar(&cfg.NoMethods, "no-methods", false,
+		flagDescf("NoMethods", "exclude methods declared on types"),
+	)
+	flagSet.BoolVar(&cfg.NoReceiverNames, "no-receiver-names", false,
+		flagDescf("NoReceiverNames", "omit variable names of method receivers"),
+	)
+	flagSet.BoolVar(&cfg.NoReceiver, "no-receiver", false,
+		flagDescf("NoReceiver", "render methods without receivers, as if they were functions"),
+	)
 	flagSet.BoolVar(&cfg.FullDocs, "full-docs", false,
 		flagDescf("FullDocs", "include full doc comments instead of synopsis"),
 	)
+	flagSet.BoolVar(&cfg.FullPackageDoc, "full-package-doc", false,
+		flagDescf("FullPackageDoc", "include full package doc comments while keeping synopsis for symbols"),
+	)
+	flagSet.BoolVar(&cfg.GroupByKind, "group-by-kind", false,
+		flagDescf("GroupByKind", "group symbols by kind instead of source order"),
+	)
+	flagSet.BoolVar(&cfg.FlattenConsts, "flatten-single-const", false,
+		flagDescf("FlattenConsts", "group single const declarations of the same type"),
+	)
+	flagSet.BoolVar(&cfg.PromoteEmbedded, "promote-embedded", false,
+		flagDescf("PromoteEmbedded", "replace embedded struct fields with their promoted fields"),
+	)
+	flagSet.BoolVar(&cfg.ExpandInterfaces, "expand-embedded-interfaces", false,
+		flagDescf("ExpandInterfaces", "replace embedded interfaces with their methods"),
+	)
+	flagSet.BoolVar(&cfg.Imports, "imports", false,
+		flagDescf("Imports", "include an import declaration with the import paths of package files"),
+	)
+	flagSet.BoolVar(&cfg.QualifyImports, "qualify-imports", false,
+		flagDescf("QualifyImports",
+			"qualify types of other packages with import paths instead of package names in JSON output",
+		),
+	)
+	flagSet.BoolVar(&cfg.ResolveUnderlying, "resolve-underlying", false,
+		flagDescf("ResolveUnderlying", "annotate types defined in terms of other types with their underlying type"),
+	)
+	flagSet.BoolVar(&cfg.ShowZeroValues, "show-zero-values", false,
+		flagDescf("ShowZeroValues", "annotate struct fields with the zero value of their type"),
+	)
+	flagSet.BoolVar(&cfg.NoEmptyGroups, "no-empty-groups", false,
+		flagDescf("NoEmptyGroups", "omit struct and interface types left empty by filters"),
+	)
+	flagSet.BoolVar(&cfg.GroupConstructors, "group-constructors", false,
+		flagDescf("GroupConstructors", "render functions returning a type of the package after the type"),
+	)
+	flagSet.BoolVar(&cfg.Compact, "compact", false,
+		flagDescf("Compact", "render struct and interface types on a single line without field comments"),
+	)
+	flagSet.BoolVar(&cfg.MethodComments, "method-comments", false,
+		flagDescf("MethodComments", "render interface method synopses as trailing line comments"),
+	)
+	flagSet.BoolVar(&cfg.Sort, "sort", false,
+		flagDescf("Sort", "render interface methods in alphabetical order"),
+	)
+	flagSet.IntVar(&cfg.MaxMethods, "max-methods", 0,
+		flagDescf("MaxMethods", "render at most N methods per type, or 0 for all methods"),
+	)
+	flagSet.IntVar(&cfg.Wrap, "wrap", defaultWrap,
+		flagDescf("Wrap", "wrap doc comments at column N, or 0 to disable wrapping"),
+	)
+	flagSet.IntVar(&cfg.WrapSignatures, "wrap-signatures", 0,
+		flagDescf("WrapSignatures", "put each parameter of signatures longer than N columns on its own line"),
+	)
+	flagSet.BoolVar(&cfg.IncludeExamples, "include-examples", false,
+		flagDescf("IncludeExamples",
+			"render testable examples from test files in the doc comments of the symbols they belong to",
+		),
+	)
+	flagSet.BoolVar(&cfg.DetectFlags, "detect-flags", false,
+		flagDescf("DetectFlags", "summarize command-line flags defined with the flag package in main package docs"),
+	)
+	flagSet.BoolVar(&cfg.Header, "header", false,
+		flagDescf("Header", "include the comments preceding the package clause of the first file, such as a license header"),
+	)
+	flagSet.BoolVar(&cfg.PlainDocs, "plain-docs", false,
+		flagDescf("PlainDocs", "strip square brackets of doc links in doc comments"),
+	)
+	flagSet.BoolVar(&cfg.KeepDirectives, "keep-directives", false,
+		flagDescf("KeepDirectives", "keep linter and compiler directives in doc and line comments"),
+	)
+	flagSet.BoolVar(&cfg.ParseDocSections, "parse-doc-sections", false,
+		flagDescf("ParseDocSections",
+			"parse Parameters:, Returns:, and Example usage: sections of function docs into JSON output",
+		),
+	)
+	flagSet.StringVar(&cfg.Color, "color", ColorAuto,
+		flagDescf("Color", "when to syntax highlight output - one of %s", strings.Join(supportedColors, ", ")),
+	)
 	flagSet.StringVar(&cfg.Theme, "theme", defaultTheme,
 		flagDescf("Theme", "syntax highlighting theme to use - see %s", themesURL),
 	)
+	flagSet.StringVar(&cfg.Format, "format", FormatText,
+		flagDescf("Format", "output format - one of %s", strings.Join(pkgdmp.Encoders(), ", ")),
+	)
 	flagSet.BoolVar(&cfg.JSON, "json", false,
-		flagDescf("JSON", "output as JSON"),
+		flagDescf("JSON", "output as JSON (shorthand for -format json)"),
+	)
+	flagSet.BoolVar(&cfg.DryRun, "dry-run", false,
+		flagDescf("DryRun", "report counts of included and excluded symbols instead of printing them"),
+	)
+	flagSet.BoolVar(&cfg.Strict, "strict", false,
+		flagDescf("Strict", "exit with an error if any declarations are unsupported"),
+	)
+	flagSet.BoolVar(&cfg.JSONEnvelope, "json-envelope", false,
+		flagDescf("JSONEnvelope", "wrap JSON output in an object with a schema version"),
+	)
+	flagSet.BoolVar(&cfg.BuildInfo, "build-info", false,
+		flagDescf("BuildInfo", "wrap JSON output in an object with a schema version and pkgdmp build information"),
+	)
+	flagSet.StringVar(&cfg.As, "as", "",
+		flagDescf("As", "rename dumped packages to `NAME` in package clauses"),
+	)
+	flagSet.StringVar(&cfg.Closure, "closure", "",
+		flagDescf("Closure", "only dump function or type `SYMBOL` and the package types it transitively refers to"),
+	)
+	flagSet.StringVar(&cfg.MethodDiff, "method-diff", "",
+		"compare methods of type `TYPE` with those of the type named by the next argument instead of "+
+			"dumping packages; only text output is supported",
+	)
+	flagSet.StringVar(&cfg.Loader, "loader", LoaderParser,
+		flagDescf("Loader", "package loader to use - one of %s; %q resolves types but requires a Go module",
+			strings.Join(supportedLoaders, ", "), LoaderPackages),
+	)
+	flagSet.StringVar(&cfg.Cache, "cache", "",
+		flagDescf("Cache", "cache parsed packages in directory DIR"),
+	)
+	flagSet.BoolVar(&cfg.API, "api", false,
+		flagDescf("API", "dump exported symbols of all non-internal packages under the directories"),
+	)
+	flagSet.BoolVar(&cfg.RelativePaths, "relative-paths", false,
+		flagDescf("RelativePaths", "report file positions relative to the module root of the first directory"),
+	)
+	flagSet.StringVar(&cfg.StdinName, "stdin-name", defaultStdinName,
+		flagDescf("StdinName", "file name to use for source read from stdin with '-' as directory"),
 	)
 	flagSet.BoolVar(&cfg.NoEnv, "no-env", false,
 		fmt.Sprintf("skip loading of configuration from '%s_*' environment variables", flagEnvPrfx),
@@ -310,12 +1130,18 @@ func envConfig(cfg *Config) {
 			field.SetBool(isTruthy(val))
 		case reflect.String:
 			field.SetString(val)
+		case reflect.Int:
+			if n, err := strconv.Atoi(val); err == nil {
+				field.SetInt(int64(n))
+			}
 		}
 	}
 
 	if envNoColor() {
 		cfg.NoHighlight = true
 	}
+
+	cfg.forceColor = envForceColor()
 }
 
 func envNoColor() bool {
@@ -329,8 +1155,7 @@ func envNoColor() bool {
 		return true
 	}
 
-	// See https://bixense.com/clicolors/
-	if _, ok := os.LookupEnv("CLICOLOR_FORCE"); ok {
+	if envForceColor() {
 		return false
 	}
 
@@ -344,6 +1169,16 @@ func envNoColor() bool {
 	return false
 }
 
+// envForceColor returns true if colored output is forced regardless of
+// whether output is written to a terminal.
+//
+// See https://bixense.com/clicolors/
+func envForceColor() bool {
+	val, ok := os.LookupEnv("CLICOLOR_FORCE")
+
+	return ok && val != "0"
+}
+
 func isTruthy(val string) bool {
 	val = strings.ToLower(val)
 	truthies := []string{"1", "true", "t", "yes"}
@@ -368,6 +1203,9 @@ func cfgEnvKey(field string) string {
 	return fmt.Sprintf("%s_%s", flagEnvPrfx, field)
 }
 
+// splitCamelCase splits s into words at lower to upper case transitions.
+// Runs of upper case letters are kept as one word, so "JSONEnvelope" is split
+// into "JSON" and "Envelope".
 func splitCamelCase(s string) []string {
 	if strings.ToUpper(s) == s {
 		return []string{s}
@@ -375,31 +1213,102 @@ func splitCamelCase(s string) []string {
 
 	var words []string
 
+	runes := []rune(s)
 	wordStart := 0
 
-	for i, char := range s {
-		if unicode.IsUpper(char) {
-			if i > wordStart {
-				words = append(words, s[wordStart:i])
-			}
+	for i := 1; i < len(runes); i++ {
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+
+		prevLower := !unicode.IsUpper(runes[i-1])
+		nextLower := i+1 < len(runes) && !unicode.IsUpper(runes[i+1])
 
+		if prevLower || nextLower {
+			words = append(words, string(runes[wordStart:i]))
 			wordStart = i
 		}
 	}
 
-	if len(s) > wordStart {
-		words = append(words, s[wordStart:])
-	}
-
-	return words
+	return append(words, string(runes[wordStart:]))
 }
 
 func usage() {
-	fmt.Fprintf(flagSet.Output(), "%s v%s\n\nUSAGE:\n\n  %s [FLAGS] DIRECTORY [DIRECTORY2] ...\n\nFLAGS:\n\n",
+	fmt.Fprintf(flagSet.Output(), "%s v%s\n\nUSAGE:\n\n  %s [FLAGS] DIRECTORY [DIRECTORY2] ... [-- SYMBOL ...]\n\n"+
+		"  Use '-' as directory to read source from stdin, the path of a .go file\n"+
+		"  to parse a single file, or the path of a .zip, .tar.gz, or .tgz module\n"+
+		"  archive to parse its root package without extracting it.\n\n"+
+		"  Symbol names following a '--' separator, such as MyStruct or\n"+
+		"  MyStruct.MyMethod, select the symbols to dump. Types are dumped with\n"+
+		"  their methods. Symbols are selected after filtering, so symbols\n"+
+		"  excluded by flags such as -matching are not dumped even if selected,\n"+
+		"  and it is an error if a selected symbol is in none of the packages.\n\nFLAGS:\n\n",
 		AppName, Version(), AppName,
 	)
 	flagSet.PrintDefaults()
 	fmt.Fprintf(flagSet.Output(), "\nSYMBOL TYPES:\n\n  %s\n\n", strings.Join(supportedSymbolTypes(), ", "))
+	fmt.Fprintf(flagSet.Output(), "EXIT CODES:\n\n"+
+		"  %d  success\n  %d  error\n  %d  invalid flags or arguments\n"+
+		"  %d  source code could not be parsed\n  %d  files could not be read or written\n\n",
+		ExitOK, ExitError, ExitUsage, ExitParse, ExitIO,
+	)
+}
+
+// splitArgs splits command line arguments into directories and the symbol
+// selectors following a `--` separator.
+func splitArgs(args []string) ([]string, []string) {
+	for i, arg := range args {
+		if arg == "--" {
+			return args[:i], args[i+1:]
+		}
+	}
+
+	return args, nil
+}
+
+// isSymbolSelector returns true if arg is an identifier, or identifiers of a
+// type and method separated by a dot.
+func isSymbolSelector(arg string) bool {
+	typ, method, dotted := strings.Cut(arg, ".")
+
+	return token.IsIdentifier(typ) && (!dotted || token.IsIdentifier(method))
+}
+
+// arityFilterFromCfg returns a filter including functions with numbers of
+// parameters and results within the configured limits, or nil if no limits
+// are configured.
+func arityFilterFromCfg(cfg *Config) (pkgdmp.SymbolFilter, error) {
+	params, results := pkgdmp.AnyArity, pkgdmp.AnyArity
+	limited := false
+
+	for _, l := range []struct {
+		name string
+		val  string
+		dst  *int
+	}{
+		{"min-params", cfg.MinParams, &params.Min},
+		{"max-params", cfg.MaxParams, &params.Max},
+		{"min-results", cfg.MinResults, &results.Min},
+		{"max-results", cfg.MaxResults, &results.Max},
+	} {
+		if l.val == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(l.val)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%s must be a positive integer, got %q", l.name, l.val)
+		}
+
+		*l.dst = n
+		limited = true
+	}
+
+	if !limited {
+		return nil, nil
+	}
+
+	return pkgdmp.FilterArity(pkgdmp.Include, params, results), nil
 }
 
 func strToSymbolTypes(list string) ([]pkgdmp.SymbolType, error) {
@@ -423,6 +1332,75 @@ func strToSymbolTypes(list string) ([]pkgdmp.SymbolType, error) {
 	return res, nil
 }
 
+// splitList splits a comma-separated list and returns the non-empty, trimmed
+// items.
+func splitList(list string) []string {
+	var res []string
+
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		res = append(res, item)
+	}
+
+	return res
+}
+
+// generatedRegexp matches the standard comment marking generated code.
+//
+// See https://go.dev/s/generatedcode
+var generatedRegexp = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// isGeneratedFile returns true if the file with provided name in fsys has a
+// comment marking it as generated before the package clause.
+func isGeneratedFile(fsys fs.FS, name string) bool {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "package ") {
+			return false
+		}
+
+		if generatedRegexp.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchAny returns true if name matches any of the glob patterns.
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isSupported(supported []string, val string) bool {
+	for _, s := range supported {
+		if val == s {
+			return true
+		}
+	}
+
+	return false
+}
+
 func supportedSymbolTypes() []string {
 	res := make([]string, 0, len(symbolTypeMap))
 
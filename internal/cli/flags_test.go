@@ -1,15 +1,20 @@
 package cli_test
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"go/ast"
 	"io"
+	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/michenriksen/pkgdmp"
 	"github.com/michenriksen/pkgdmp/internal/cli"
 )
 
@@ -23,25 +28,25 @@ func TestParseFlags(t *testing.T) {
 	}{
 		{
 			name:         "no args",
-			wantExitCode: 1,
+			wantExitCode: cli.ExitUsage,
 			wantErr:      cli.ErrNoDirs,
 		},
 		{
 			name:         "help flag",
 			args:         []string{"-help"},
-			wantExitCode: 0,
+			wantExitCode: cli.ExitOK,
 			wantErr:      flag.ErrHelp,
 		},
 		{
 			name:         "version flag",
 			args:         []string{"-version"},
-			wantExitCode: 0,
+			wantExitCode: cli.ExitOK,
 			wantErr:      cli.ErrVersion,
 		},
 		{
 			name:         "flags but no directories",
 			args:         []string{"-unexported", "-full-docs"},
-			wantExitCode: 1,
+			wantExitCode: cli.ExitUsage,
 			wantErr:      cli.ErrNoDirs,
 		},
 		{
@@ -53,8 +58,221 @@ func TestParseFlags(t *testing.T) {
 				Exclude:    "interface",
 				Dirs:       []string{"directory1", "directory2"},
 				Theme:      "swapoff",
+				Color:      "auto",
+				Wrap:       80,
+				Format:     "text",
+				StdinName:  "stdin.go",
+				Loader:     "parser",
 			},
 		},
+		{
+			name: "format flag",
+			args: []string{"-format", "flat-json", "directory"},
+			wantCfg: &cli.Config{
+				Dirs:      []string{"directory"},
+				Theme:     "swapoff",
+				Color:     "auto",
+				Wrap:      80,
+				Format:    "flat-json",
+				StdinName: "stdin.go",
+				Loader:    "parser",
+			},
+		},
+		{
+			name: "stdin name flag",
+			args: []string{"-stdin-name", "main.go", "-"},
+			wantCfg: &cli.Config{
+				Dirs:      []string{"-"},
+				Theme:     "swapoff",
+				Color:     "auto",
+				Wrap:      80,
+				Format:    "text",
+				StdinName: "main.go",
+				Loader:    "parser",
+			},
+		},
+		{
+			name:         "malformed file pattern",
+			args:         []string{"-only-files", "client*.go,[a-", "directory"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrFilePattern,
+		},
+		{
+			name:         "unsupported color mode",
+			args:         []string{"-color", "sometimes", "directory"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrColor,
+		},
+		{
+			name:         "unsupported format",
+			args:         []string{"-format", "yaml", "directory"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrFormat,
+		},
+		{
+			name:         "negative max methods",
+			args:         []string{"-max-methods", "-1", "directory"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrFlagValue,
+		},
+		{
+			name:         "malformed matching pattern",
+			args:         []string{"-matching", "[", "directory"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrFlagValue,
+		},
+		{
+			name:         "negative signature wrap width",
+			args:         []string{"-wrap-signatures", "-3", "directory"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrFlagValue,
+		},
+		{
+			name:         "unsupported loader",
+			args:         []string{"-loader", "gopls", "directory"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrLoader,
+		},
+		{
+			name: "skip empty flag",
+			args: []string{"-skip-empty", "directory"},
+			wantCfg: &cli.Config{
+				Dirs:      []string{"directory"},
+				Theme:     "swapoff",
+				Color:     "auto",
+				Wrap:      80,
+				Format:    "text",
+				StdinName: "stdin.go",
+				Loader:    "parser",
+				SkipEmpty: true,
+			},
+		},
+		{
+			name: "package name flag",
+			args: []string{"-as", "otherpkg", "directory"},
+			wantCfg: &cli.Config{
+				Dirs:      []string{"directory"},
+				Theme:     "swapoff",
+				Color:     "auto",
+				Wrap:      80,
+				Format:    "text",
+				StdinName: "stdin.go",
+				Loader:    "parser",
+				As:        "otherpkg",
+			},
+		},
+		{
+			name:         "invalid package name",
+			args:         []string{"-as", "my-package", "directory"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrPackageName,
+		},
+		{
+			name:         "keyword package name",
+			args:         []string{"-as", "func", "directory"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrPackageName,
+		},
+		{
+			name: "symbol selectors",
+			args: []string{"-matching", "^My", "directory", "--", "MyStruct", "MyStruct.MyMethod"},
+			wantCfg: &cli.Config{
+				Dirs:      []string{"directory"},
+				Symbols:   []string{"MyStruct", "MyStruct.MyMethod"},
+				Matching:  "^My",
+				Theme:     "swapoff",
+				Color:     "auto",
+				Wrap:      80,
+				Format:    "text",
+				StdinName: "stdin.go",
+				Loader:    "parser",
+			},
+		},
+		{
+			name: "symbol selectors after flag terminator",
+			args: []string{"--", "directory", "MyStruct", "--", "MyFunction"},
+			wantCfg: &cli.Config{
+				Dirs:      []string{"directory", "MyStruct"},
+				Symbols:   []string{"MyFunction"},
+				Theme:     "swapoff",
+				Color:     "auto",
+				Wrap:      80,
+				Format:    "text",
+				StdinName: "stdin.go",
+				Loader:    "parser",
+			},
+		},
+		{
+			name:         "invalid symbol selector",
+			args:         []string{"directory", "--", "MyStruct.MyMethod.MyField"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrSymbolName,
+		},
+		{
+			name:         "symbol selectors without directories",
+			args:         []string{"--", "--", "MyStruct"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrNoDirs,
+		},
+		{
+			name: "closure flag",
+			args: []string{"-closure", "MyStruct", "directory"},
+			wantCfg: &cli.Config{
+				Dirs:      []string{"directory"},
+				Theme:     "swapoff",
+				Color:     "auto",
+				Wrap:      80,
+				Format:    "text",
+				StdinName: "stdin.go",
+				Loader:    "parser",
+				Closure:   "MyStruct",
+			},
+		},
+		{
+			name:         "invalid closure symbol",
+			args:         []string{"-closure", "MyStruct.MyMethod", "directory"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrSymbolName,
+		},
+		{
+			name: "method diff flag",
+			args: []string{"-method-diff", "MyStruct", "MyOtherStruct", "directory"},
+			wantCfg: &cli.Config{
+				Dirs:           []string{"directory"},
+				Theme:          "swapoff",
+				Color:          "auto",
+				Wrap:           80,
+				Format:         "text",
+				StdinName:      "stdin.go",
+				Loader:         "parser",
+				MethodDiff:     "MyStruct",
+				MethodDiffWith: "MyOtherStruct",
+			},
+		},
+		{
+			name:         "method diff without second type",
+			args:         []string{"-method-diff", "MyStruct"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrSymbolName,
+		},
+		{
+			name:         "method diff without directories",
+			args:         []string{"-method-diff", "MyStruct", "MyOtherStruct"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrNoDirs,
+		},
+		{
+			name:         "method diff with JSON format",
+			args:         []string{"-format", "json", "-method-diff", "MyStruct", "MyOtherStruct", "directory"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrMethodDiffFormat,
+		},
+		{
+			name:         "method diff with JSON flag",
+			args:         []string{"-json", "-method-diff", "MyStruct", "MyOtherStruct", "directory"},
+			wantExitCode: cli.ExitUsage,
+			wantErr:      cli.ErrMethodDiffFormat,
+		},
 	}
 
 	for _, tc := range tt {
@@ -119,6 +337,412 @@ func TestParseFlags_PackageFiltering(t *testing.T) {
 	}
 }
 
+func TestConfig_IncludeSourcePackage(t *testing.T) {
+	//nolint:staticcheck // ast.Package is required by doc.New.
+	var (
+		httpPkg   = cli.SourcePackage{Package: &ast.Package{Name: "http"}, ImportPath: "net/http"}
+		myHTTPPkg = cli.SourcePackage{Package: &ast.Package{Name: "http"}, ImportPath: "example.com/mymodule/http"}
+		stdinPkg  = cli.SourcePackage{Package: &ast.Package{Name: "http"}}
+	)
+
+	tt := []struct {
+		args []string
+		pkg  cli.SourcePackage
+		want bool
+	}{
+		{[]string{"-exclude-packages", "http"}, httpPkg, false},
+		{[]string{"-exclude-packages", "http"}, myHTTPPkg, false},
+		{[]string{"-exclude-packages", "net/http"}, myHTTPPkg, true},
+		{[]string{"-by-import-path", "-exclude-packages", "net/http"}, httpPkg, false},
+		{[]string{"-by-import-path", "-exclude-packages", "net/http"}, myHTTPPkg, true},
+		{[]string{"-by-import-path", "-exclude-packages", "http"}, myHTTPPkg, true},
+		{[]string{"-by-import-path", "-only-packages", "example.com/mymodule/http"}, myHTTPPkg, true},
+		{[]string{"-by-import-path", "-only-packages", "example.com/mymodule/http"}, httpPkg, false},
+		{[]string{"-by-import-path", "-only-packages", "http"}, stdinPkg, true},
+	}
+
+	for _, tc := range tt {
+		name := fmt.Sprintf("returns %t for %s with args %s", tc.want, tc.pkg.ImportPath, strings.Join(tc.args, " "))
+
+		t.Run(name, func(t *testing.T) {
+			cfg, _, err := cli.ParseFlags(append(tc.args, "-no-env", "directory"), io.Discard)
+			if err != nil {
+				t.Fatalf("did not expect error, but got: %v", err)
+			}
+
+			if got := cfg.IncludeSourcePackage(tc.pkg); got != tc.want {
+				t.Errorf("expected cfg.IncludeSourcePackage to return %t, but got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseFlags_FileFiltering(t *testing.T) {
+	tt := []struct {
+		args []string
+		file string
+		want bool
+	}{
+		{nil, "client.go", true},
+		{[]string{"-only-files", "client*.go"}, "client.go", true},
+		{[]string{"-only-files", "client*.go"}, "client_http.go", true},
+		{[]string{"-only-files", "client*.go"}, "server.go", false},
+		{[]string{"-only-files", "server.go, client*.go"}, "server.go", true},
+		{[]string{"-exclude-files", "*_gen.go"}, "client.go", true},
+		{[]string{"-exclude-files", "*_gen.go"}, "client_gen.go", false},
+		{[]string{"-only-files", "client*.go", "-exclude-files", "*_gen.go"}, "client_gen.go", false},
+	}
+
+	for _, tc := range tt {
+		name := fmt.Sprintf("returns %t for %s with args %s", tc.want, tc.file, strings.Join(tc.args, " "))
+
+		t.Run(name, func(t *testing.T) {
+			args := append(tc.args, "directory")
+
+			cfg, exitCode, err := cli.ParseFlags(args, io.Discard)
+			if err != nil {
+				t.Fatalf("did not expect error, but got: %v", err)
+			}
+
+			if exitCode != 0 {
+				t.Fatalf("expected exit code 0, but got %d", exitCode)
+			}
+
+			if cfg.IncludeFile(tc.file) != tc.want {
+				t.Fatalf("expected cfg.IncludeFile(%q) to return %t", tc.file, tc.want)
+			}
+		})
+	}
+}
+
+func TestConfig_SourceFileFilter(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]struct {
+		src  string
+		want bool
+	}{
+		"lib.go":        {"package mypackage\n", true},
+		"lib_test.go":   {"package mypackage\n", false},
+		"gen.go":        {"//go:build ignore\n\npackage main\n", false},
+		"tools.go":      {"//go:build tools\n\npackage tools\n", false},
+		"legacy.go":     {"// +build ignore\n\npackage main\n", false},
+		"excluded.go":   {"package mypackage\n", false},
+		"constraint.go": {"//go:build !ignore\n\npackage mypackage\n", true},
+	}
+
+	for name, f := range files {
+		writeFile(t, filepath.Join(dir, name), f.src)
+	}
+
+	cfg, _, err := cli.ParseFlags([]string{"-exclude-files", "excluded.go", dir}, io.Discard)
+	if err != nil {
+		t.Fatalf("did not expect error, but got: %v", err)
+	}
+
+	filter := cfg.SourceFileFilter(dir)
+
+	for name, f := range files {
+		fi, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("error getting file info: %v", err)
+		}
+
+		if actual := filter(fi); actual != f.want {
+			t.Errorf("expected filter to return %t for %s, but got %t", f.want, name, actual)
+		}
+	}
+}
+
+func TestConfig_SourceFileFilter_ExcludeGenerated(t *testing.T) {
+	dir := filepath.Join("..", "..", "testdata", "source")
+
+	tt := []struct {
+		args []string
+		file string
+		want bool
+	}{
+		{nil, "generated.go", true},
+		{nil, "default.go", true},
+		{[]string{"-exclude-generated"}, "generated.go", false},
+		{[]string{"-exclude-generated"}, "default.go", true},
+	}
+
+	for _, tc := range tt {
+		name := fmt.Sprintf("returns %t for %s with args %s", tc.want, tc.file, strings.Join(tc.args, " "))
+
+		t.Run(name, func(t *testing.T) {
+			cfg, _, err := cli.ParseFlags(append(tc.args, dir), io.Discard)
+			if err != nil {
+				t.Fatalf("did not expect error, but got: %v", err)
+			}
+
+			fi, err := os.Stat(filepath.Join(dir, tc.file))
+			if err != nil {
+				t.Fatalf("error getting file info: %v", err)
+			}
+
+			if actual := cfg.SourceFileFilter(dir)(fi); actual != tc.want {
+				t.Errorf("expected filter to return %t for %s, but got %t", tc.want, tc.file, actual)
+			}
+		})
+	}
+}
+
+func TestConfig_OutputFormat(t *testing.T) {
+	tt := []struct {
+		args []string
+		want string
+	}{
+		{nil, cli.FormatText},
+		{[]string{"-format", "json"}, cli.FormatJSON},
+		{[]string{"-format", "flat-json"}, cli.FormatFlatJSON},
+		{[]string{"-json"}, cli.FormatJSON},
+		{[]string{"-json", "-format", "flat-json"}, cli.FormatJSON},
+	}
+
+	for _, tc := range tt {
+		name := fmt.Sprintf("returns %s with args %s", tc.want, strings.Join(tc.args, " "))
+
+		t.Run(name, func(t *testing.T) {
+			args := append(tc.args, "directory")
+
+			cfg, _, err := cli.ParseFlags(args, io.Discard)
+			if err != nil {
+				t.Fatalf("did not expect error, but got: %v", err)
+			}
+
+			if actual := cfg.OutputFormat(); actual != tc.want {
+				t.Errorf("expected output format %q, but got %q", tc.want, actual)
+			}
+		})
+	}
+}
+
+func TestConfig_Encoder(t *testing.T) {
+	pkgs := []*pkgdmp.Package{{Name: "mypackage", Funcs: []pkgdmp.Func{{Name: "MyFunc"}}}}
+
+	tt := []struct {
+		name     string
+		args     []string
+		terminal bool
+		want     func(t *testing.T, out string)
+	}{
+		{
+			"text", nil, false,
+			func(t *testing.T, out string) {
+				if want := "package mypackage\n\nfunc MyFunc()\n\n\n"; out != want {
+					t.Errorf("expected output %q, but got %q", want, out)
+				}
+			},
+		},
+		{
+			"highlighted text", []string{"-color", "always"}, false,
+			func(t *testing.T, out string) {
+				if !strings.Contains(out, "\x1b[") || !strings.Contains(out, "MyFunc") {
+					t.Errorf("expected highlighted source, but got %q", out)
+				}
+			},
+		},
+		{
+			"json envelope", []string{"-format", "json", "-json-envelope", "-group-by-kind"}, true,
+			func(t *testing.T, out string) {
+				var envelope struct {
+					SchemaVersion int `json:"schemaVersion"`
+					Packages      []struct {
+						Symbols map[string]json.RawMessage `json:"symbols"`
+					} `json:"packages"`
+				}
+
+				if err := json.Unmarshal([]byte(out), &envelope); err != nil {
+					t.Fatalf("expected JSON output, but got: %v", err)
+				}
+
+				if envelope.SchemaVersion != pkgdmp.SchemaVersion || len(envelope.Packages) != 1 ||
+					envelope.Packages[0].Symbols == nil {
+					t.Errorf("expected envelope with schema version and 1 grouped package, but got:\n\n%s", out)
+				}
+			},
+		},
+		{
+			"json build info", []string{"-json", "-build-info"}, false,
+			func(t *testing.T, out string) {
+				if !strings.Contains(out, `"buildInfo": {`) {
+					t.Errorf("expected build info in output, but got:\n\n%s", out)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, _, err := cli.ParseFlags(append(tc.args, "directory"), io.Discard)
+			if err != nil {
+				t.Fatalf("did not expect error, but got: %v", err)
+			}
+
+			enc, err := cfg.Encoder(tc.terminal)
+			if err != nil {
+				t.Fatalf("expected no error, but got: %v", err)
+			}
+
+			var b strings.Builder
+
+			if err := enc.Encode(&b, pkgs); err != nil {
+				t.Fatalf("expected no error when encoding, but got: %v", err)
+			}
+
+			tc.want(t, b.String())
+		})
+	}
+}
+
+func TestParseFlags_RegisteredFormat(t *testing.T) {
+	const format = "test-registered"
+
+	if _, ok := pkgdmp.LookupEncoder(format); !ok {
+		pkgdmp.RegisterEncoder(format, pkgdmp.EncoderFunc(func(w io.Writer, _ []*pkgdmp.Package) error {
+			_, err := io.WriteString(w, "registered\n")
+			return err //nolint:wrapcheck // test encoder.
+		}))
+	}
+
+	cfg, _, err := cli.ParseFlags([]string{"-format", format, "directory"}, io.Discard)
+	if err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	enc, err := cfg.Encoder(false)
+	if err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	var b strings.Builder
+
+	if err := enc.Encode(&b, nil); err != nil {
+		t.Fatalf("expected no error when encoding, but got: %v", err)
+	}
+
+	if b.String() != "registered\n" {
+		t.Errorf("expected output of registered encoder, but got %q", b.String())
+	}
+}
+
+func TestConfig_Highlight(t *testing.T) {
+	tt := []struct {
+		cfg      *cli.Config
+		terminal bool
+		want     bool
+	}{
+		{&cli.Config{Color: cli.ColorAuto}, true, true},
+		{&cli.Config{Color: cli.ColorAuto}, false, false},
+		{&cli.Config{Color: cli.ColorAuto, NoHighlight: true}, true, false},
+		{&cli.Config{Color: cli.ColorAlways}, false, true},
+		{&cli.Config{Color: cli.ColorAlways, NoHighlight: true}, false, true},
+		{&cli.Config{Color: cli.ColorNever}, true, false},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		name := fmt.Sprintf("returns %t for color %s with terminal %t and no highlight %t",
+			tc.want, tc.cfg.Color, tc.terminal, tc.cfg.NoHighlight,
+		)
+
+		t.Run(name, func(t *testing.T) {
+			if actual := tc.cfg.Highlight(tc.terminal); actual != tc.want {
+				t.Errorf("expected Highlight(%t) to return %t, but got %t", tc.terminal, tc.want, actual)
+			}
+		})
+	}
+}
+
+func TestConfig_Highlight_ForceColor(t *testing.T) {
+	tt := []struct {
+		env      map[string]string
+		args     []string
+		terminal bool
+		want     bool
+	}{
+		{nil, nil, false, false},
+		{nil, nil, true, true},
+		{map[string]string{"CLICOLOR_FORCE": "1"}, nil, false, true},
+		{map[string]string{"CLICOLOR_FORCE": "0"}, nil, false, false},
+		{map[string]string{"CLICOLOR_FORCE": "1", "NO_COLOR": "1"}, nil, true, false},
+		{map[string]string{"CLICOLOR_FORCE": "1"}, []string{"-color", "never"}, false, false},
+		{map[string]string{"CLICOLOR_FORCE": "1"}, []string{"-no-env"}, false, false},
+	}
+
+	for _, tc := range tt {
+		name := fmt.Sprintf("returns %t with env %v, args %s and terminal %t",
+			tc.want, tc.env, strings.Join(tc.args, " "), tc.terminal,
+		)
+
+		t.Run(name, func(t *testing.T) {
+			for _, k := range []string{"NO_COLOR", "PKGDMP_NO_COLOR", "PKGDMP_NO_HIGHLIGHT", "CLICOLOR_FORCE", "TERM"} {
+				t.Setenv(k, "")
+				os.Unsetenv(k)
+			}
+
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			args := append(tc.args, "directory")
+
+			cfg, _, err := cli.ParseFlags(args, io.Discard)
+			if err != nil {
+				t.Fatalf("did not expect error, but got: %v", err)
+			}
+
+			if actual := cfg.Highlight(tc.terminal); actual != tc.want {
+				t.Errorf("expected Highlight(%t) to return %t, but got %t", tc.terminal, tc.want, actual)
+			}
+		})
+	}
+}
+
+func TestParseFlags_Env(t *testing.T) {
+	tt := []struct {
+		name string
+		env  string
+		want func(*cli.Config) bool
+	}{
+		{
+			name: "single word",
+			env:  "PKGDMP_API",
+			want: func(cfg *cli.Config) bool { return cfg.API },
+		},
+		{
+			name: "camel case",
+			env:  "PKGDMP_BUILD_INFO",
+			want: func(cfg *cli.Config) bool { return cfg.BuildInfo },
+		},
+		{
+			name: "leading acronym",
+			env:  "PKGDMP_JSON_ENVELOPE",
+			want: func(cfg *cli.Config) bool { return cfg.JSONEnvelope },
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(tc.env, "true")
+
+			cfg, _, err := cli.ParseFlags([]string{"directory"}, io.Discard)
+			if err != nil {
+				t.Fatalf("did not expect error, but got: %v", err)
+			}
+
+			if !tc.want(cfg) {
+				t.Errorf("expected %s to enable its flag", tc.env)
+			}
+		})
+	}
+}
+
 func TestParserOptsFromCfg(t *testing.T) {
 	tt := []struct {
 		name          string
@@ -128,22 +752,45 @@ func TestParserOptsFromCfg(t *testing.T) {
 	}{
 		{
 			name: "default config",
-			cfg:  &cli.Config{},
+			cfg:  &cli.Config{Wrap: 80},
 			wantOpts: []string{
 				"symbolFilters(filters=filterUnexported(action=Exclude))",
 			},
 		},
+		{
+			name: "only vars",
+			cfg:  &cli.Config{Only: "var", Wrap: 80},
+			wantOpts: []string{
+				"symbolFilters(filters=filterUnexported(action=Exclude),filterSymbolTypes(action=Include,symbolTypes=SymbolVar))",
+			},
+		},
+		{
+			name: "exclude consts and vars",
+			cfg:  &cli.Config{Exclude: "const,var", Wrap: 80},
+			wantOpts: []string{
+				"symbolFilters(filters=filterUnexported(action=Exclude),filterSymbolTypes(action=Exclude,symbolTypes=SymbolConst,SymbolVar))",
+			},
+		},
 		{
 			name: "full docs and exclude interfaces",
-			cfg:  &cli.Config{FullDocs: true, Exclude: "interface"},
+			cfg:  &cli.Config{FullDocs: true, Exclude: "interface", Wrap: 80},
 			wantOpts: []string{
 				"fullDocs",
 				"symbolFilters(filters=filterUnexported(action=Exclude),filterSymbolTypes(action=Exclude,symbolTypes=SymbolInterfaceType))",
 			},
 		},
+		{
+			name: "no methods and no tags",
+			cfg:  &cli.Config{NoMethods: true, NoTags: true, Wrap: 80},
+			wantOpts: []string{
+				"noTags",
+				"noMethods",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
 		{
 			name: "match and exclude patterns",
-			cfg:  &cli.Config{Matching: `^FooBa(r|z)`, ExcludeMatching: `(Hello|Hi)World`},
+			cfg:  &cli.Config{Matching: `^FooBa(r|z)`, ExcludeMatching: `(Hello|Hi)World`, Wrap: 80},
 			wantOpts: []string{
 				"symbolFilters(filters=" +
 					"filterUnexported(action=Exclude)," +
@@ -151,6 +798,315 @@ func TestParserOptsFromCfg(t *testing.T) {
 					"filterMatchingIdents(action=Exclude,pattern=(Hello|Hi)World))",
 			},
 		},
+		{
+			name: "receiver and exclude receiver patterns",
+			cfg:  &cli.Config{Receiver: `^My`, ExcludeReceiver: `Struct$`, Wrap: 80},
+			wantOpts: []string{
+				"symbolFilters(filters=" +
+					"filterUnexported(action=Exclude)," +
+					"filterReceiver(action=Include,pattern=^My)," +
+					"filterReceiver(action=Exclude,pattern=Struct$))",
+			},
+		},
+		{
+			name: "matching type and exclude matching type patterns",
+			cfg:  &cli.Config{MatchingType: `error$`, ExcludeMatchingType: `\*sql\.DB`, Wrap: 80},
+			wantOpts: []string{
+				"symbolFilters(filters=" +
+					"filterUnexported(action=Exclude)," +
+					"filterMatchingTypes(action=Include,pattern=error$)," +
+					"filterMatchingTypes(action=Exclude,pattern=\\*sql\\.DB))",
+			},
+		},
+		{
+			name: "marker and exclude marker",
+			cfg:  &cli.Config{Marker: "Stable:", ExcludeMarker: "// Experimental:", Wrap: 80},
+			wantOpts: []string{
+				"symbolFilters(filters=" +
+					"filterUnexported(action=Exclude)," +
+					"filterDocMarker(action=Include,marker=Stable:)," +
+					"filterDocMarker(action=Exclude,marker=Experimental:))",
+			},
+		},
+		{
+			name: "minimum and maximum name length",
+			cfg:  &cli.Config{MinNameLen: 3, MaxNameLen: 20, Wrap: 80},
+			wantOpts: []string{
+				"symbolFilters(filters=" +
+					"filterUnexported(action=Exclude)," +
+					"filterNamePredicate(action=Exclude)," +
+					"filterNamePredicate(action=Exclude))",
+			},
+		},
+		{
+			name:          "negative minimum name length",
+			cfg:           &cli.Config{MinNameLen: -1},
+			wantErrRegexp: regexp.MustCompile(`minimum name length must be a positive integer`),
+		},
+		{
+			name: "keep and drop tag keys",
+			cfg:  &cli.Config{TagsKeep: "json, xml", TagsDrop: "db", Wrap: 80},
+			wantOpts: []string{
+				"tagKeys(action=Include,keys=json,xml)",
+				"tagKeys(action=Exclude,keys=db)",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "full and plain docs",
+			cfg:  &cli.Config{FullDocs: true, PlainDocs: true, Wrap: 80},
+			wantOpts: []string{
+				"fullDocs",
+				"plainDocs",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "keep directives",
+			cfg:  &cli.Config{KeepDirectives: true, Wrap: 80},
+			wantOpts: []string{
+				"keepDirectives",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "group by kind",
+			cfg:  &cli.Config{GroupByKind: true, Wrap: 80},
+			wantOpts: []string{
+				"groupByKind",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "signatures preset",
+			cfg:  &cli.Config{Signatures: true, Wrap: 80},
+			wantOpts: []string{
+				"noDocs",
+				"noTags",
+				"noMethods",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "signatures preset with overlapping flags",
+			cfg:  &cli.Config{Signatures: true, NoDocs: true, NoMethods: true, Wrap: 80},
+			wantOpts: []string{
+				"noDocs",
+				"noTags",
+				"noMethods",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "full package doc",
+			cfg:  &cli.Config{FullPackageDoc: true, Wrap: 80},
+			wantOpts: []string{
+				"fullPackageDoc",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "parse doc sections",
+			cfg:  &cli.Config{ParseDocSections: true, Wrap: 80},
+			wantOpts: []string{
+				"docSections",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "no comments",
+			cfg:  &cli.Config{FullDocs: true, NoComments: true, Wrap: 80},
+			wantOpts: []string{
+				"fullDocs",
+				"noComments",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "no receivers",
+			cfg:  &cli.Config{NoReceiver: true, Wrap: 80},
+			wantOpts: []string{
+				"noReceivers",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "no receiver names",
+			cfg:  &cli.Config{NoReceiverNames: true, Wrap: 80},
+			wantOpts: []string{
+				"noReceiverNames",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "imports",
+			cfg:  &cli.Config{Imports: true, Wrap: 80},
+			wantOpts: []string{
+				"imports",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "qualify imports",
+			cfg:  &cli.Config{QualifyImports: true, Wrap: 80},
+			wantOpts: []string{
+				"qualifiedImports",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "filter params",
+			cfg:  &cli.Config{FilterParams: true, Wrap: 80},
+			wantOpts: []string{
+				"includeUnfilterable",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "dry run",
+			cfg:  &cli.Config{DryRun: true, Wrap: 80},
+			wantOpts: []string{
+				"filterResults",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "flatten single consts",
+			cfg:  &cli.Config{FlattenConsts: true, Wrap: 80},
+			wantOpts: []string{
+				"flattenSingleConsts",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "promote embedded",
+			cfg:  &cli.Config{PromoteEmbedded: true, Wrap: 80},
+			wantOpts: []string{
+				"promoteEmbedded",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name:     "api overrides unexported",
+			cfg:      &cli.Config{API: true, Unexported: true, Wrap: 80},
+			wantOpts: []string{"symbolFilters(filters=filterUnexported(action=Exclude))"},
+		},
+		{
+			name: "expand embedded interfaces",
+			cfg:  &cli.Config{ExpandInterfaces: true, Wrap: 80},
+			wantOpts: []string{
+				"expandEmbeddedInterfaces",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "resolve underlying",
+			cfg:  &cli.Config{ResolveUnderlying: true, Wrap: 80},
+			wantOpts: []string{
+				"resolveUnderlying",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "show zero values",
+			cfg:  &cli.Config{ShowZeroValues: true, Wrap: 80},
+			wantOpts: []string{
+				"zeroValues",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "no empty groups",
+			cfg:  &cli.Config{NoEmptyGroups: true, Wrap: 80},
+			wantOpts: []string{
+				"noEmptyGroups",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "group constructors",
+			cfg:  &cli.Config{GroupConstructors: true, Wrap: 80},
+			wantOpts: []string{
+				"groupConstructors",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "unexported for",
+			cfg:  &cli.Config{UnexportedFor: "func,method", Wrap: 80},
+			wantOpts: []string{
+				"symbolFilters(filters=filterUnexportedForTypes(action=Include,symbolTypes=SymbolFunc,SymbolMethod))",
+			},
+		},
+		{
+			name: "strip internal types",
+			cfg:  &cli.Config{StripInternalTypes: true, Wrap: 80},
+			wantOpts: []string{
+				"stripInternalTypes",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "include examples",
+			cfg:  &cli.Config{IncludeExamples: true, Wrap: 80},
+			wantOpts: []string{
+				"examples",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "compact",
+			cfg:  &cli.Config{Compact: true, Wrap: 80},
+			wantOpts: []string{
+				"compact",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "sorted method comments",
+			cfg:  &cli.Config{MethodComments: true, Sort: true, Wrap: 80},
+			wantOpts: []string{
+				"methodComments",
+				"sortMethods",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "max methods",
+			cfg:  &cli.Config{MaxMethods: 5, Wrap: 80},
+			wantOpts: []string{
+				"maxMethods(n=5)",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "wrap signatures",
+			cfg:  &cli.Config{WrapSignatures: 100, Wrap: 80},
+			wantOpts: []string{
+				"wrapSignatures(width=100)",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "no wrapping",
+			cfg:  &cli.Config{Wrap: 0},
+			wantOpts: []string{
+				"wrap(width=0)",
+				"symbolFilters(filters=filterUnexported(action=Exclude))",
+			},
+		},
+		{
+			name: "arity limits",
+			cfg:  &cli.Config{MaxParams: "0", MinResults: "2", Wrap: 80},
+			wantOpts: []string{
+				"symbolFilters(filters=filterUnexported(action=Exclude),filterArity(action=Include,params=0..0,results=2..))",
+			},
+		},
+		{
+			name:          "invalid arity limit",
+			cfg:           &cli.Config{MinParams: "-1"},
+			wantErrRegexp: regexp.MustCompile(`min-params must be a positive integer, got "-1"`),
+		},
 		{
 			name:          "invalid match regexp",
 			cfg:           &cli.Config{Matching: `a\x{2`},
@@ -161,6 +1117,16 @@ func TestParserOptsFromCfg(t *testing.T) {
 			cfg:           &cli.Config{ExcludeMatching: `a\x{2`},
 			wantErrRegexp: regexp.MustCompile(`parsing exclude matching regular expression:.*invalid escape sequence`),
 		},
+		{
+			name:          "invalid matching type regexp",
+			cfg:           &cli.Config{MatchingType: `a\x{2`},
+			wantErrRegexp: regexp.MustCompile(`parsing matching type regular expression:.*invalid escape sequence`),
+		},
+		{
+			name:          "invalid receiver regexp",
+			cfg:           &cli.Config{Receiver: `a\x{2`},
+			wantErrRegexp: regexp.MustCompile(`parsing receiver regular expression:.*invalid escape sequence`),
+		},
 	}
 
 	for _, tc := range tt {
@@ -198,3 +1164,65 @@ func TestParserOptsFromCfg(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFlags_ExportedAndUnexported(t *testing.T) {
+	tt := []struct {
+		args        []string
+		wantErr     error
+		wantFilters string
+	}{
+		{nil, nil, "symbolFilters(filters=filterUnexported(action=Exclude))"},
+		{[]string{"-exported"}, nil, "symbolFilters(filters=filterUnexported(action=Exclude))"},
+		{[]string{"-exported", "-api"}, nil, "symbolFilters(filters=filterUnexported(action=Exclude))"},
+		{[]string{"-unexported"}, nil, ""},
+		{
+			[]string{"-unexported-for", "func"}, nil,
+			"symbolFilters(filters=filterUnexportedForTypes(action=Include,symbolTypes=SymbolFunc))",
+		},
+		{[]string{"-exported", "-unexported"}, cli.ErrExportedConflict, ""},
+		{[]string{"-unexported", "-exported"}, cli.ErrExportedConflict, ""},
+		{[]string{"-exported", "-unexported-for", "func"}, cli.ErrExportedConflict, ""},
+	}
+
+	for _, tc := range tt {
+		name := fmt.Sprintf("with args %q", tc.args)
+
+		t.Run(name, func(t *testing.T) {
+			args := append([]string{"-no-env"}, tc.args...)
+
+			cfg, exitCode, err := cli.ParseFlags(append(args, "directory"), io.Discard)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("expected error %v, but got: %v", tc.wantErr, err)
+				}
+
+				if exitCode != cli.ExitUsage {
+					t.Errorf("expected exit code %d, but got %d", cli.ExitUsage, exitCode)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, but got: %v", err)
+			}
+
+			opts, err := cli.ParserOptsFromCfg(cfg)
+			if err != nil {
+				t.Fatalf("expected no error, but got: %v", err)
+			}
+
+			var filters string
+
+			for _, opt := range opts {
+				if s := fmt.Sprint(opt); strings.HasPrefix(s, "symbolFilters(") {
+					filters = s
+				}
+			}
+
+			if filters != tc.wantFilters {
+				t.Errorf("expected symbol filters %q, but got %q", tc.wantFilters, filters)
+			}
+		})
+	}
+}
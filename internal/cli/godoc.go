@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"fmt"
+	"go/doc/comment"
+	"go/format"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+// godocTmpl is the template of the HTML fragment written by [GodocEncoder].
+var godocTmpl = template.Must(template.New("godoc").Parse(`<div class="pkgdmp-package">
+<h1>Package {{ .Name }}</h1>
+<h2 id="pkg-overview">Overview</h2>
+{{ .Doc }}
+<h2 id="pkg-index">Index</h2>
+<ul>
+{{- if .Consts }}
+<li><a href="#pkg-constants">Constants</a></li>
+{{- end }}
+{{- if .Vars }}
+<li><a href="#pkg-variables">Variables</a></li>
+{{- end }}
+{{- range .Funcs }}
+<li><a href="#{{ .ID }}">{{ .Synopsis }}</a></li>
+{{- end }}
+{{- range .Types }}
+<li><a href="#{{ .ID }}">{{ .Synopsis }}</a>
+{{- if .Methods }}
+<ul>
+{{- range .Methods }}
+<li><a href="#{{ .ID }}">{{ .Synopsis }}</a></li>
+{{- end }}
+</ul>
+{{- end }}
+</li>
+{{- end }}
+</ul>
+{{- if .Consts }}
+<h2 id="pkg-constants">Constants</h2>
+{{- range .Consts }}
+<pre>{{ .Decl }}</pre>
+{{ .Doc }}
+{{- end }}
+{{- end }}
+{{- if .Vars }}
+<h2 id="pkg-variables">Variables</h2>
+{{- range .Vars }}
+<pre>{{ .Decl }}</pre>
+{{ .Doc }}
+{{- end }}
+{{- end }}
+{{- if .Funcs }}
+<h2 id="pkg-functions">Functions</h2>
+{{- range .Funcs }}
+<h3 id="{{ .ID }}">{{ .Synopsis }}</h3>
+<pre>{{ .Decl }}</pre>
+{{ .Doc }}
+{{- end }}
+{{- end }}
+{{- if .Types }}
+<h2 id="pkg-types">Types</h2>
+{{- range .Types }}
+<h3 id="{{ .ID }}">{{ .Synopsis }}</h3>
+<pre>{{ .Decl }}</pre>
+{{ .Doc }}
+{{- range .Methods }}
+<h4 id="{{ .ID }}">{{ .Synopsis }}</h4>
+<pre>{{ .Decl }}</pre>
+{{ .Doc }}
+{{- end }}
+{{- end }}
+{{- end }}
+</div>
+`))
+
+// godocPackage is the data of [godocTmpl].
+type godocPackage struct {
+	Name   string
+	Doc    template.HTML
+	Consts []godocSymbol
+	Vars   []godocSymbol
+	Funcs  []godocSymbol
+	Types  []godocSymbol
+}
+
+// godocSymbol is a documented symbol in a [godocPackage].
+type godocSymbol struct {
+	ID       string
+	Synopsis string
+	Decl     string
+	Doc      template.HTML
+	Methods  []godocSymbol
+}
+
+// GodocEncoder writes packages as HTML fragments in the style of the classic
+// godoc layout, suitable for embedding in static documentation sites.
+//
+// Each package is written as a package overview, an index of its symbols,
+// and a section with the declaration and documentation of each symbol.
+// Functions and types have `<h3>` headings and methods `<h4>` headings, with
+// IDs following godoc conventions, e.g. `MyStruct` and `MyStruct.MyMethod`.
+//
+// Doc comments are rendered as HTML with links to symbols of the package, so
+// full doc comments, as parsed with the -full-docs flag, give the best
+// results.
+type GodocEncoder struct {
+	w io.Writer
+}
+
+// NewGodocEncoder returns a new encoder that writes to w.
+func NewGodocEncoder(w io.Writer) *GodocEncoder {
+	return &GodocEncoder{w: w}
+}
+
+// Encode writes an HTML fragment documenting pkg.
+func (e *GodocEncoder) Encode(pkg *pkgdmp.Package) error {
+	docHTML := newDocRenderer(pkg)
+	data := godocPackage{Name: pkg.Name, Doc: docHTML(pkg.Doc)}
+
+	for _, cg := range pkg.Consts {
+		if len(cg.Consts) == 0 {
+			continue
+		}
+
+		doc := cg.Doc
+		cg.Doc = ""
+		cg.Consts = append([]pkgdmp.Const(nil), cg.Consts...)
+
+		for i := range cg.Consts {
+			cg.Consts[i].Doc = ""
+		}
+
+		data.Consts = append(data.Consts, godocSymbol{Decl: formatDecl(cg.String()), Doc: docHTML(doc)})
+	}
+
+	for _, vg := range pkg.Vars {
+		if len(vg.Vars) == 0 {
+			continue
+		}
+
+		doc := vg.Doc
+		vg.Doc = ""
+		vg.Vars = append([]pkgdmp.Var(nil), vg.Vars...)
+
+		for i := range vg.Vars {
+			vg.Vars[i].Doc = ""
+		}
+
+		data.Vars = append(data.Vars, godocSymbol{Decl: formatDecl(vg.String()), Doc: docHTML(doc)})
+	}
+
+	for _, f := range pkg.Funcs {
+		data.Funcs = append(data.Funcs, godocFunc(f, docHTML))
+	}
+
+	for _, td := range pkg.Types {
+		sym := godocSymbol{ID: td.Name, Synopsis: "type " + td.Name, Doc: docHTML(td.Doc)}
+
+		if td.Type != "interface" {
+			for _, m := range td.Methods {
+				sym.Methods = append(sym.Methods, godocFunc(m, docHTML))
+			}
+
+			td.Methods = nil
+		}
+
+		td.Doc, td.Examples = "", nil
+		sym.Decl = formatDecl(td.String())
+
+		data.Types = append(data.Types, sym)
+	}
+
+	if err := godocTmpl.Execute(e.w, data); err != nil {
+		return fmt.Errorf("writing HTML for %s package: %w", pkg.Name, err)
+	}
+
+	return nil
+}
+
+// godocFunc returns the documented symbol of function or method f.
+func godocFunc(f pkgdmp.Func, docHTML func(string) template.HTML) godocSymbol {
+	sym := godocSymbol{ID: f.Name, Synopsis: "func " + f.Name, Doc: docHTML(f.Doc)}
+
+	if rt := f.ReceiverType(); rt != "" {
+		sym.ID = rt + "." + f.Name
+		sym.Synopsis = fmt.Sprintf("func (%s) %s", f.Receiver.Type, f.Name)
+	}
+
+	f.Doc, f.Examples = "", nil
+	sym.Decl = formatDecl(f.String())
+
+	return sym
+}
+
+// newDocRenderer returns a function rendering doc comment text as HTML, with
+// doc links such as `[MyStruct]` to symbols of pkg linked to their sections.
+func newDocRenderer(pkg *pkgdmp.Package) func(string) template.HTML {
+	parser := &comment.Parser{
+		LookupSym: func(recv, name string) bool {
+			return hasSymbol(pkg, recv, name)
+		},
+	}
+
+	printer := &comment.Printer{
+		HeadingLevel: 4,
+		DocLinkURL: func(link *comment.DocLink) string {
+			if link.ImportPath != "" {
+				return link.DefaultURL("https://pkg.go.dev")
+			}
+
+			if link.Recv != "" {
+				return "#" + link.Recv + "." + link.Name
+			}
+
+			return "#" + link.Name
+		},
+	}
+
+	return func(text string) template.HTML {
+		if text == "" {
+			return ""
+		}
+
+		//nolint:gosec // doc comments are rendered as HTML by the printer.
+		return template.HTML(printer.HTML(parser.Parse(text)))
+	}
+}
+
+// hasSymbol returns true if pkg declares a top-level symbol with name, or a
+// method with name on type recv if recv is not empty.
+func hasSymbol(pkg *pkgdmp.Package, recv, name string) bool {
+	for _, td := range pkg.Types {
+		if recv == "" && td.Name == name {
+			return true
+		}
+
+		if td.Name != recv {
+			continue
+		}
+
+		for _, m := range td.Methods {
+			if m.Name == name {
+				return true
+			}
+		}
+	}
+
+	for _, f := range pkg.Funcs {
+		if f.ReceiverType() == recv && f.Name == name {
+			return true
+		}
+	}
+
+	if recv != "" {
+		return false
+	}
+
+	for _, cg := range pkg.Consts {
+		for _, c := range cg.Consts {
+			for _, n := range c.Names {
+				if n == name {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, vg := range pkg.Vars {
+		for _, v := range vg.Vars {
+			for _, n := range v.Names {
+				if n == name {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// formatDecl returns the gofmt formatted declaration code, or code unchanged
+// if it cannot be formatted.
+func formatDecl(code string) string {
+	const prefix = "package p\n\n"
+
+	formatted, err := format.Source([]byte(prefix + code))
+	if err != nil {
+		return code
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(string(formatted), prefix))
+}
@@ -0,0 +1,82 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestGodocEncoder(t *testing.T) {
+	pkg := &pkgdmp.Package{
+		Name: "shapes",
+		Doc:  "Package shapes provides [Shape] implementations.",
+		Consts: []pkgdmp.ConstGroup{
+			{Doc: "Pi is pi.", Consts: []pkgdmp.Const{{Names: []string{"Pi"}, Spec: "Pi = 3.14"}}},
+		},
+		Vars: []pkgdmp.VarGroup{
+			{Doc: "Unit is the [Circle] with radius 1.", Vars: []pkgdmp.Var{{Names: []string{"Unit"}, Spec: "Unit = NewCircle(1)"}}},
+		},
+		Types: []pkgdmp.TypeDef{
+			{
+				Type:   "struct",
+				Name:   "Circle",
+				Doc:    "Circle is a circle. See [Circle.Area].",
+				Fields: []pkgdmp.Field{{Names: []string{"Radius"}, Type: "float64"}},
+				Methods: []pkgdmp.Func{
+					{
+						Name:     "Area",
+						Doc:      "Area returns the area.",
+						Receiver: &pkgdmp.Field{Names: []string{"c"}, Type: "*Circle"},
+						Results:  []pkgdmp.Field{{Type: "float64"}},
+					},
+				},
+			},
+			{
+				Type:    "interface",
+				Name:    "Shape",
+				Methods: []pkgdmp.Func{{Name: "Area", Results: []pkgdmp.Field{{Type: "float64"}}}},
+			},
+		},
+		Funcs: []pkgdmp.Func{
+			{
+				Name:    "NewCircle",
+				Doc:     "NewCircle creates a <circle>.",
+				Params:  []pkgdmp.Field{{Names: []string{"r"}, Type: "float64"}},
+				Results: []pkgdmp.Field{{Type: "*Circle"}},
+			},
+		},
+	}
+
+	var b strings.Builder
+
+	if err := cli.NewGodocEncoder(&b).Encode(pkg); err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+
+	got := b.String()
+
+	for _, want := range []string{
+		`<h2 id="pkg-overview">Overview</h2>` + "\n" +
+			`<p>Package shapes provides <a href="#Shape">Shape</a> implementations.`,
+		`<li><a href="#pkg-constants">Constants</a></li>`,
+		`<li><a href="#Circle.Area">func (*Circle) Area</a></li>`,
+		"<pre>const Pi = 3.14</pre>\n<p>Pi is pi.",
+		`<li><a href="#pkg-variables">Variables</a></li>`,
+		"<pre>var Unit = NewCircle(1)</pre>\n" + `<p>Unit is the <a href="#Circle">Circle</a> with radius 1.`,
+		`<h3 id="NewCircle">func NewCircle</h3>` + "\n" +
+			"<pre>func NewCircle(r float64) *Circle</pre>\n<p>NewCircle creates a &lt;circle&gt;.",
+		`<h3 id="Circle">type Circle</h3>` + "\n" +
+			"<pre>type Circle struct {\n\tRadius float64\n}</pre>\n" +
+			`<p>Circle is a circle. See <a href="#Circle.Area">Circle.Area</a>.`,
+		`<h4 id="Circle.Area">func (*Circle) Area</h4>` + "\n" +
+			"<pre>func (c *Circle) Area() float64</pre>",
+		`<h3 id="Shape">type Shape</h3>` + "\n" +
+			"<pre>type Shape interface {\n\tArea() float64\n}</pre>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain:\n\n%s\n\nbut got:\n\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,510 @@
+package cli
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/michenriksen/pkgdmp"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
+)
+
+// loadMode is the go/packages load mode of the packages loader.
+//
+// Dependencies are type-checked from source instead of export data, which is
+// slower but works regardless of the export data format of the Go toolchain.
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+	packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps
+
+// SourcePackage is a parsed Go package with type information if it was loaded
+// with the packages loader.
+type SourcePackage struct {
+	*ast.Package //nolint:staticcheck // ast.Package is required by doc.New.
+
+	// Types is the type-checked package, or nil if the package was loaded
+	// without type information.
+	Types *types.Package
+
+	// Header is the leading comment block of the package's first file, as
+	// returned by [PackageHeader].
+	Header string
+
+	// TestFiles are the parsed test files of the package, including files of
+	// its external test package, if test files are included.
+	TestFiles []*ast.File
+
+	// ImportPath is the import path of the package, or empty if it is not
+	// known, e.g. for packages read from stdin or outside of a Go module.
+	ImportPath string
+}
+
+// PackageHeader returns the comments preceding the package clause of the
+// first file in pkg, sorted by file name, such as a license or copyright
+// header.
+//
+// The package doc comment and build constraints are not part of the header.
+// Comments are returned as they appear in the source, including comment
+// markers, with blank lines between comment blocks.
+//
+//nolint:staticcheck // ast.Package is required by doc.New.
+func PackageHeader(pkg *ast.Package) string {
+	if len(pkg.Files) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(pkg.Files))
+
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	file := pkg.Files[names[0]]
+	blocks := make([]string, 0, len(file.Comments))
+
+	for _, cg := range file.Comments {
+		if cg.Pos() >= file.Package {
+			break
+		}
+
+		if cg == file.Doc || isDirectiveGroup(cg) {
+			continue
+		}
+
+		lines := make([]string, len(cg.List))
+
+		for i, c := range cg.List {
+			lines[i] = c.Text
+		}
+
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(blocks, "\n\n")
+}
+
+// isDirectiveGroup returns true if all comments in cg are build constraints
+// or directives such as `//go:build`.
+func isDirectiveGroup(cg *ast.CommentGroup) bool {
+	for _, c := range cg.List {
+		if !strings.HasPrefix(c.Text, "//go:") && !strings.HasPrefix(c.Text, "// +build") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsGoFile returns true if path is a regular file with a `.go` extension.
+func IsGoFile(path string) bool {
+	if filepath.Ext(path) != ".go" {
+		return false
+	}
+
+	fi, err := os.Stat(path)
+
+	return err == nil && fi.Mode().IsRegular()
+}
+
+// LoadPackages parses the Go packages at path, which is either a directory, a
+// single Go source file, or an archive as determined by [IsArchive].
+//
+// Files in a directory are filtered with [Config.SourceFileFilter], while a
+// single file is parsed as a single-file package regardless of filters.
+// Archives are read into memory with [OpenArchive] and their files filtered
+// with [Config.FSFileFilter], without extracting them.
+//
+// Packages in a directory are loaded with type information if the packages
+// loader is configured. Test files are only loaded with the parser loader, as
+// [SourcePackage.TestFiles], if the -include-examples flag is specified.
+//
+// Files are parsed with the file set returned by [Config.FileSet].
+func (c *Config) LoadPackages(path string) ([]SourcePackage, *token.FileSet, error) {
+	fset := c.FileSet()
+
+	if IsGoFile(path) {
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+
+		//nolint:staticcheck // ast.Package is required by doc.New.
+		pkg := &ast.Package{
+			Name:  file.Name.Name,
+			Files: map[string]*ast.File{path: file},
+		}
+
+		return []SourcePackage{{Package: pkg, Header: PackageHeader(pkg)}}, fset, nil
+	}
+
+	if IsArchive(path) {
+		fsys, dir, err := OpenArchive(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pkgs, err := c.parseFSDir(fset, fsys, dir, path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return splitTestFiles(pkgs), fset, nil
+	}
+
+	// Stat the directory first, as errors from reading it through
+	// [os.DirFS] or from the packages loader do not name it.
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil, fmt.Errorf("loading packages: %w", err)
+	}
+
+	if c.Loader == LoaderPackages {
+		pkgs, err := c.loadTypedPackages(fset, path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return pkgs, fset, nil
+	}
+
+	pkgs, err := c.parseFSDir(fset, os.DirFS(path), ".", path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := splitTestFiles(pkgs)
+	importPath := dirImportPath(path)
+
+	for i := range res {
+		res[i].ImportPath = importPath
+	}
+
+	return res, fset, nil
+}
+
+// LoadFSPackages parses the Go packages in directory dir of fsys, such as an
+// archive opened with [OpenArchive] or an [embed.FS].
+//
+// Files are filtered with [Config.FSFileFilter] and parsed with the file set
+// returned by [Config.FileSet], with their slash-separated paths in fsys as
+// file names. Packages are always loaded without type information.
+func (c *Config) LoadFSPackages(fsys fs.FS, dir string) ([]SourcePackage, *token.FileSet, error) {
+	fset := c.FileSet()
+
+	pkgs, err := c.parseFSDir(fset, fsys, dir, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return splitTestFiles(pkgs), fset, nil
+}
+
+// parseFSDir parses the Go source files in directory dir of fsys accepted by
+// [Config.FSFileFilter] with [pkgdmp.ParseFSFiles].
+//
+// File names are the paths of the files in fsys, joined to prefix if it is
+// not empty.
+//
+//nolint:staticcheck // ast.Package is required by doc.New.
+func (c *Config) parseFSDir(fset *token.FileSet, fsys fs.FS, dir, prefix string) (map[string]*ast.Package, error) {
+	files, err := pkgdmp.ParseFSFiles(fset, fsys, dir, prefix, c.FSFileFilter(fsys, dir))
+	if err != nil {
+		return nil, err //nolint:wrapcheck // error is already wrapped.
+	}
+
+	pkgs := make(map[string]*ast.Package, len(files))
+
+	for name, pkgFiles := range files {
+		pkg := &ast.Package{Name: name, Files: make(map[string]*ast.File, len(pkgFiles))}
+
+		for _, file := range pkgFiles {
+			pkg.Files[fset.File(file.Pos()).Name()] = file
+		}
+
+		pkgs[name] = pkg
+	}
+
+	return pkgs, nil
+}
+
+// splitTestFiles returns the packages in pkgs with their test files moved to
+// [SourcePackage.TestFiles].
+//
+// Files of an external test package, such as `foo_test`, are added to the
+// test files of the package it tests, and packages without any non-test
+// files are dropped.
+//
+//nolint:staticcheck // ast.Package is required by doc.New.
+func splitTestFiles(pkgs map[string]*ast.Package) []SourcePackage {
+	names := make([]string, 0, len(pkgs))
+	testFiles := make(map[string][]*ast.File)
+
+	for name, pkg := range pkgs {
+		names = append(names, name)
+		base := strings.TrimSuffix(name, "_test")
+
+		fileNames := make([]string, 0, len(pkg.Files))
+
+		for fileName := range pkg.Files {
+			fileNames = append(fileNames, fileName)
+		}
+
+		sort.Strings(fileNames)
+
+		for _, fileName := range fileNames {
+			if strings.HasSuffix(fileName, "_test.go") {
+				testFiles[base] = append(testFiles[base], pkg.Files[fileName])
+				delete(pkg.Files, fileName)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	all := make([]SourcePackage, 0, len(pkgs))
+
+	for _, name := range names {
+		pkg := pkgs[name]
+		if len(pkg.Files) == 0 {
+			continue
+		}
+
+		all = append(all, SourcePackage{Package: pkg, Header: PackageHeader(pkg), TestFiles: testFiles[name]})
+	}
+
+	return all
+}
+
+// loadTypedPackages loads the package in dir with go/packages.
+//
+// The package is type-checked with all of its files, while only files
+// accepted by [Config.SourceFileFilter] are included in its syntax.
+func (c *Config) loadTypedPackages(fset *token.FileSet, dir string) ([]SourcePackage, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode, Dir: dir, Fset: fset}, ".")
+	if err != nil {
+		return nil, fmt.Errorf("loading package in %s: %w", dir, err)
+	}
+
+	include := c.SourceFileFilter(dir)
+	res := make([]SourcePackage, 0, len(pkgs))
+
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) != 0 {
+			return nil, fmt.Errorf("loading package in %s: %w", dir, pkg.Errors[0])
+		}
+
+		//nolint:staticcheck // ast.Package is required by doc.New.
+		aPkg := &ast.Package{Name: pkg.Name, Files: make(map[string]*ast.File, len(pkg.Syntax))}
+
+		for _, file := range pkg.Syntax {
+			name := fset.File(file.Pos()).Name()
+
+			fi, err := os.Stat(name)
+			if err != nil {
+				return nil, fmt.Errorf("getting file info for %s: %w", name, err)
+			}
+
+			if include(fi) {
+				aPkg.Files[name] = file
+			}
+		}
+
+		res = append(res, SourcePackage{
+			Package:    aPkg,
+			Types:      pkg.Types,
+			Header:     PackageHeader(aPkg),
+			ImportPath: pkg.PkgPath,
+		})
+	}
+
+	return res, nil
+}
+
+// APIDirs returns the directories containing Go source files in and below
+// each directory in roots, for dumping the public API surface of a module.
+//
+// Directories named `internal`, `testdata`, or `vendor`, directories with
+// names starting with `.` or `_`, and directories of nested Go modules are
+// skipped together with their subdirectories. Roots that are Go files,
+// archives, or `-` are returned as they are. Directories are returned in lexical order
+// within each root, without duplicates.
+func APIDirs(roots []string) ([]string, error) {
+	seen := make(map[string]struct{})
+
+	var res []string
+
+	add := func(dir string) {
+		if _, ok := seen[dir]; !ok {
+			seen[dir] = struct{}{}
+			res = append(res, dir)
+		}
+	}
+
+	for _, root := range roots {
+		if root == "-" || IsGoFile(root) || IsArchive(root) {
+			add(root)
+			continue
+		}
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.IsDir() {
+				return nil
+			}
+
+			if path != root && skipAPIDir(path, d.Name()) {
+				return filepath.SkipDir
+			}
+
+			ok, err := hasGoFiles(path)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				add(path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("walking %s: %w", root, err)
+		}
+	}
+
+	return res, nil
+}
+
+// skipAPIDir returns true if the directory at path with name is not part of
+// the public API surface of the module being walked.
+func skipAPIDir(path, name string) bool {
+	switch {
+	case name == "internal", name == "testdata", name == "vendor":
+		return true
+	case strings.HasPrefix(name, "."), strings.HasPrefix(name, "_"):
+		return true
+	}
+
+	fi, err := os.Stat(filepath.Join(path, "go.mod"))
+
+	return err == nil && fi.Mode().IsRegular()
+}
+
+// hasGoFiles returns true if dir contains any Go source files that are not
+// test files.
+func hasGoFiles(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, fmt.Errorf("reading directory: %w", err)
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+
+		if !e.IsDir() && filepath.Ext(name) == ".go" && !strings.HasSuffix(name, "_test.go") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Position returns the position of pos in fset.
+//
+// If the -relative-paths flag is specified, the file name is made relative to
+// the root of the Go module containing the first directory argument, or to
+// the directory itself if it is not part of a module. File names of source
+// read from stdin are returned as they are.
+func (c *Config) Position(fset *token.FileSet, pos token.Pos) token.Position {
+	position := fset.Position(pos)
+
+	if !c.RelativePaths || position.Filename == "" || position.Filename == c.StdinName || len(c.Dirs) == 0 {
+		return position
+	}
+
+	base, err := filepath.Abs(c.Dirs[0])
+	if err != nil {
+		return position
+	}
+
+	if IsGoFile(base) {
+		base = filepath.Dir(base)
+	}
+
+	if root, ok := moduleRoot(base); ok {
+		base = root
+	}
+
+	name, err := filepath.Abs(position.Filename)
+	if err != nil {
+		return position
+	}
+
+	if rel, err := filepath.Rel(base, name); err == nil {
+		position.Filename = rel
+	}
+
+	return position
+}
+
+// dirImportPath returns the import path of the package in dir, derived from
+// the module path of the closest go.mod file, or an empty string if dir is
+// not part of a Go module.
+func dirImportPath(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	root, ok := moduleRoot(abs)
+	if !ok {
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+
+	modPath := modfile.ModulePath(data)
+	if modPath == "" {
+		return ""
+	}
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return ""
+	}
+
+	return path.Join(modPath, filepath.ToSlash(rel))
+}
+
+// moduleRoot returns the closest directory containing a go.mod file, starting
+// at dir and walking up the directory tree.
+func moduleRoot(dir string) (string, bool) {
+	for {
+		if fi, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && fi.Mode().IsRegular() {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+
+		dir = parent
+	}
+}
@@ -0,0 +1,378 @@
+package cli_test
+
+import (
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestConfig_LoadPackages(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.go")
+
+	writeFile(t, file, "package mypackage\n\nfunc MyFunc() {}\n")
+	writeFile(t, filepath.Join(dir, "other.go"), "package mypackage\n\nfunc MyOtherFunc() {}\n")
+	writeFile(t, filepath.Join(dir, "file_test.go"), "package mypackage_test\n")
+
+	cfg := &cli.Config{}
+
+	tt := []struct {
+		name      string
+		path      string
+		wantFiles []string
+	}{
+		{
+			name:      "directory",
+			path:      dir,
+			wantFiles: []string{file, filepath.Join(dir, "other.go")},
+		},
+		{
+			name:      "single file",
+			path:      file,
+			wantFiles: []string{file},
+		},
+		{
+			name:      "single test file",
+			path:      filepath.Join(dir, "file_test.go"),
+			wantFiles: []string{filepath.Join(dir, "file_test.go")},
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			pkgs, fset, err := cfg.LoadPackages(tc.path)
+			if err != nil {
+				t.Fatalf("expected no error, but got: %v", err)
+			}
+
+			if fset == nil {
+				t.Fatal("expected file set, but got nil")
+			}
+
+			if len(pkgs) != 1 {
+				t.Fatalf("expected 1 package, but got %d", len(pkgs))
+			}
+
+			var files []string
+
+			for name := range pkgs[0].Files {
+				files = append(files, name)
+			}
+
+			sort.Strings(files)
+
+			if len(files) != len(tc.wantFiles) {
+				t.Fatalf("expected files %v, but got %v", tc.wantFiles, files)
+			}
+
+			for i, f := range files {
+				if f != tc.wantFiles[i] {
+					t.Errorf("expected files %v, but got %v", tc.wantFiles, files)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_LoadPackages_ImportPath(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "sub")
+
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/mymodule\n")
+	writeFile(t, filepath.Join(root, "root.go"), "package mymodule\n")
+	writeFile(t, filepath.Join(dir, "sub.go"), "package sub\n")
+
+	tt := []struct {
+		path string
+		want string
+	}{
+		{root, "example.com/mymodule"},
+		{dir, "example.com/mymodule/sub"},
+		{filepath.Join(dir, "sub.go"), ""},
+	}
+
+	for _, tc := range tt {
+		pkgs, _, err := (&cli.Config{}).LoadPackages(tc.path)
+		if err != nil {
+			t.Fatalf("expected no error, but got: %v", err)
+		}
+
+		if len(pkgs) != 1 {
+			t.Fatalf("expected 1 package, but got %d", len(pkgs))
+		}
+
+		if pkgs[0].ImportPath != tc.want {
+			t.Errorf("expected import path %q for %s, but got %q", tc.want, tc.path, pkgs[0].ImportPath)
+		}
+	}
+}
+
+func TestConfig_LoadPackages_InvalidFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file.go")
+
+	writeFile(t, file, "package mypackage\n\nfunc {\n")
+
+	if _, _, err := (&cli.Config{}).LoadPackages(file); err == nil {
+		t.Error("expected error when parsing invalid file, but got no error")
+	}
+}
+
+func TestConfig_LoadPackages_NotExist(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nonexistent")
+
+	for _, loader := range []string{cli.LoaderParser, cli.LoaderPackages} {
+		t.Run(loader, func(t *testing.T) {
+			_, _, err := (&cli.Config{Loader: loader}).LoadPackages(dir)
+			if err == nil {
+				t.Fatal("expected error when loading nonexistent directory, but got no error")
+			}
+
+			if !strings.Contains(err.Error(), dir) {
+				t.Errorf("expected error to name %s, but got: %v", dir, err)
+			}
+
+			if code := cli.ExitCode(err); code != cli.ExitIO {
+				t.Errorf("expected exit code %d, but got %d", cli.ExitIO, code)
+			}
+		})
+	}
+}
+
+func TestIsGoFile(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "file.go"), "package mypackage\n")
+	writeFile(t, filepath.Join(dir, "file.txt"), "text\n")
+
+	tt := []struct {
+		path string
+		want bool
+	}{
+		{filepath.Join(dir, "file.go"), true},
+		{filepath.Join(dir, "file.txt"), false},
+		{filepath.Join(dir, "missing.go"), false},
+		{dir, false},
+	}
+
+	for _, tc := range tt {
+		if got := cli.IsGoFile(tc.path); got != tc.want {
+			t.Errorf("expected IsGoFile(%q) to return %t, but got %t", tc.path, tc.want, got)
+		}
+	}
+}
+
+func TestConfig_LoadPackages_PackagesLoader(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/mypackage\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(dir, "file.go"), "package mypackage\n\nimport \"time\"\n\ntype MyDuration time.Duration\n")
+	writeFile(t, filepath.Join(dir, "other.go"), "package mypackage\n\ntype MyTimeout MyDuration\n")
+
+	cfg, _, err := cli.ParseFlags([]string{"-no-env", "-loader", "packages", "-only-files", "file.go", dir}, io.Discard)
+	if err != nil {
+		t.Fatalf("expected no error when parsing flags, but got: %v", err)
+	}
+
+	pkgs, _, err := cfg.LoadPackages(dir)
+	if err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if len(pkgs) != 1 {
+		t.Fatalf("expected 1 package, but got %d", len(pkgs))
+	}
+
+	if pkgs[0].Types == nil {
+		t.Fatal("expected package to have type information")
+	}
+
+	if obj := pkgs[0].Types.Scope().Lookup("MyTimeout"); obj == nil {
+		t.Error("expected types of excluded files to be type-checked")
+	}
+
+	if len(pkgs[0].Files) != 1 {
+		t.Errorf("expected 1 file in package syntax, but got %d", len(pkgs[0].Files))
+	}
+
+	if pkgs[0].ImportPath != "example.com/mypackage" {
+		t.Errorf("expected import path example.com/mypackage, but got %q", pkgs[0].ImportPath)
+	}
+}
+
+func TestConfig_LoadPackages_Header(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "b.go"), "// Copyright of b.\n\npackage mypackage\n")
+	writeFile(t, filepath.Join(dir, "a.go"), `// Copyright 2024 Example Authors.
+// Licensed under the MIT license.
+
+/*
+Additional notice.
+*/
+
+//go:build linux
+
+// Package mypackage is an example package.
+package mypackage // line comment.
+
+// MyFunc is an example function.
+func MyFunc() {}
+`)
+
+	pkgs, _, err := (&cli.Config{}).LoadPackages(dir)
+	if err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if len(pkgs) != 1 {
+		t.Fatalf("expected 1 package, but got %d", len(pkgs))
+	}
+
+	want := "// Copyright 2024 Example Authors.\n// Licensed under the MIT license.\n\n/*\nAdditional notice.\n*/"
+
+	if pkgs[0].Header != want {
+		t.Errorf("expected header:\n\n%s\n\nbut got:\n\n%s", want, pkgs[0].Header)
+	}
+}
+
+func TestConfig_LoadPackages_TestFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "file.go"), "package mypackage\n\nfunc MyFunc() {}\n")
+	writeFile(t, filepath.Join(dir, "file_test.go"), "package mypackage\n\nfunc ExampleMyFunc() {}\n")
+	writeFile(t, filepath.Join(dir, "example_test.go"), "package mypackage_test\n\nfunc Example() {}\n")
+
+	pkgs, _, err := (&cli.Config{IncludeExamples: true}).LoadPackages(dir)
+	if err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if len(pkgs) != 1 {
+		t.Fatalf("expected 1 package, but got %d", len(pkgs))
+	}
+
+	if len(pkgs[0].Files) != 1 {
+		t.Errorf("expected 1 file, but got %d", len(pkgs[0].Files))
+	}
+
+	if len(pkgs[0].TestFiles) != 2 {
+		t.Errorf("expected 2 test files, but got %d", len(pkgs[0].TestFiles))
+	}
+}
+
+func TestConfig_Position(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "sub")
+	file := filepath.Join(dir, "file.go")
+
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/mymodule\n")
+	writeFile(t, file, "package mypackage\n")
+
+	fset := token.NewFileSet()
+	tFile := fset.AddFile(file, -1, 100)
+	stdin := fset.AddFile("stdin.go", -1, 100)
+
+	tt := []struct {
+		name string
+		cfg  *cli.Config
+		pos  token.Pos
+		want string
+	}{
+		{"absolute", &cli.Config{Dirs: []string{dir}}, tFile.Pos(0), file},
+		{"module root", &cli.Config{Dirs: []string{dir}, RelativePaths: true}, tFile.Pos(0), filepath.Join("sub", "file.go")},
+		{"file argument", &cli.Config{Dirs: []string{file}, RelativePaths: true}, tFile.Pos(0), filepath.Join("sub", "file.go")},
+		{"stdin", &cli.Config{Dirs: []string{"-"}, StdinName: "stdin.go", RelativePaths: true}, stdin.Pos(0), "stdin.go"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.cfg.Position(fset, tc.pos).Filename; got != tc.want {
+				t.Errorf("expected file name %q, but got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAPIDirs(t *testing.T) {
+	root := t.TempDir()
+
+	for _, dir := range []string{
+		"a", "a/internal/x", "b/sub", "empty", "internal", "nested", "testdata", "vendor/v", ".hidden", "_skip", "tests",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatalf("error creating directory: %v", err)
+		}
+	}
+
+	for _, file := range []string{
+		"root.go", "a/a.go", "a/internal/x/x.go", "b/sub/sub.go", "internal/i.go", "nested/go.mod",
+		"nested/n.go", "testdata/t.go", "vendor/v/v.go", ".hidden/h.go", "_skip/s.go", "tests/only_test.go",
+	} {
+		writeFile(t, filepath.Join(root, file), "package x\n")
+	}
+
+	file := filepath.Join(root, "root.go")
+
+	dirs, err := cli.APIDirs([]string{root, file, "-", root})
+	if err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+
+	want := []string{
+		root,
+		filepath.Join(root, "a"),
+		filepath.Join(root, "b", "sub"),
+		file,
+		"-",
+	}
+
+	if !reflect.DeepEqual(dirs, want) {
+		t.Errorf("expected directories:\n\n%q\n\nbut got:\n\n%q", want, dirs)
+	}
+}
+
+func TestConfig_LoadFSPackages(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pkg/file.go":      {Data: []byte("package mypackage\n\nfunc MyFunc() {}\n")},
+		"pkg/file_test.go": {Data: []byte("package mypackage\n")},
+		"pkg/ignored.go":   {Data: []byte("//go:build ignore\n\npackage main\n")},
+		"pkg/gen.go":       {Data: []byte("// Code generated by mygen. DO NOT EDIT.\n\npackage mypackage\n")},
+		"pkg/sub/sub.go":   {Data: []byte("package sub\n")},
+	}
+
+	pkgs, fset, err := (&cli.Config{ExcludeGenerated: true}).LoadFSPackages(fsys, "pkg")
+	if err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if fset == nil {
+		t.Fatal("expected file set, but got nil")
+	}
+
+	if len(pkgs) != 1 || pkgs[0].Name != "mypackage" {
+		t.Fatalf("expected mypackage package, but got %d packages", len(pkgs))
+	}
+
+	if _, ok := pkgs[0].Files["pkg/file.go"]; !ok || len(pkgs[0].Files) != 1 {
+		t.Errorf("expected only pkg/file.go to be parsed, but got %d files", len(pkgs[0].Files))
+	}
+}
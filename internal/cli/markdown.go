@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"fmt"
+	"go/doc/comment"
+	"io"
+	"strings"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+// markdownCellReplacer escapes text for use in a Markdown table cell.
+var markdownCellReplacer = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+
+// MarkdownEncoder writes packages as Markdown documents.
+//
+// Each package is written as a package overview and a section for each kind
+// of symbol, with the declaration of each symbol as a Go code block followed
+// by its documentation. Functions and types have `###` headings and methods
+// `####` headings.
+//
+// Const groups forming iota-style enums, as reported by
+// [pkgdmp.ConstGroup.Enum], are written as tables with the name, value, and
+// doc of each const instead of code blocks.
+type MarkdownEncoder struct {
+	w io.Writer
+}
+
+// NewMarkdownEncoder returns a new encoder that writes to w.
+func NewMarkdownEncoder(w io.Writer) *MarkdownEncoder {
+	return &MarkdownEncoder{w: w}
+}
+
+// Encode writes a Markdown document documenting pkg.
+func (e *MarkdownEncoder) Encode(pkg *pkgdmp.Package) error {
+	var b strings.Builder
+
+	docMD := newMarkdownDocRenderer(pkg)
+
+	fmt.Fprintf(&b, "# Package %s\n\n", pkg.Name)
+	b.WriteString(docMD(pkg.Doc))
+
+	if len(pkg.Consts) != 0 {
+		b.WriteString("## Constants\n\n")
+
+		for _, cg := range pkg.Consts {
+			writeMarkdownConstGroup(&b, cg, docMD)
+		}
+	}
+
+	if len(pkg.Vars) != 0 {
+		b.WriteString("## Variables\n\n")
+
+		for _, vg := range pkg.Vars {
+			if len(vg.Vars) == 0 {
+				continue
+			}
+
+			doc := vg.Doc
+			vg.Doc = ""
+			vg.Vars = append([]pkgdmp.Var(nil), vg.Vars...)
+
+			for i := range vg.Vars {
+				vg.Vars[i].Doc = ""
+			}
+
+			writeMarkdownCode(&b, vg.String())
+			b.WriteString(docMD(doc))
+		}
+	}
+
+	if len(pkg.Funcs) != 0 {
+		b.WriteString("## Functions\n\n")
+
+		for _, f := range pkg.Funcs {
+			writeMarkdownFunc(&b, "###", f, docMD)
+		}
+	}
+
+	if len(pkg.Types) != 0 {
+		b.WriteString("## Types\n\n")
+
+		for _, td := range pkg.Types {
+			fmt.Fprintf(&b, "### type %s\n\n", td.Name)
+
+			doc, methods := td.Doc, td.Methods
+
+			if td.Type != "interface" {
+				td.Methods = nil
+			} else {
+				methods = nil
+			}
+
+			td.Doc, td.Examples = "", nil
+
+			writeMarkdownCode(&b, td.String())
+			b.WriteString(docMD(doc))
+
+			for _, m := range methods {
+				writeMarkdownFunc(&b, "####", m, docMD)
+			}
+		}
+	}
+
+	if _, err := io.WriteString(e.w, b.String()); err != nil {
+		return fmt.Errorf("writing Markdown for %s package: %w", pkg.Name, err)
+	}
+
+	return nil
+}
+
+// writeMarkdownConstGroup writes const group cg as a table if it is an enum,
+// or as a code block otherwise, followed by its doc.
+func writeMarkdownConstGroup(b *strings.Builder, cg pkgdmp.ConstGroup, docMD func(string) string) {
+	if len(cg.Consts) == 0 {
+		return
+	}
+
+	if _, members, ok := cg.Enum(); ok {
+		b.WriteString(docMD(cg.Doc))
+		b.WriteString("| Name | Value | Doc |\n| --- | --- | --- |\n")
+
+		for _, m := range members {
+			value := m.Value
+			if value != "" {
+				value = "`" + value + "`"
+			}
+
+			fmt.Fprintf(b, "| `%s` | %s | %s |\n", m.Name, value, markdownCellReplacer.Replace(m.Doc))
+		}
+
+		b.WriteString("\n")
+
+		return
+	}
+
+	doc := cg.Doc
+	cg.Doc = ""
+	cg.Consts = append([]pkgdmp.Const(nil), cg.Consts...)
+
+	for i := range cg.Consts {
+		cg.Consts[i].Doc = ""
+	}
+
+	writeMarkdownCode(b, cg.String())
+	b.WriteString(docMD(doc))
+}
+
+// writeMarkdownFunc writes function or method f with a heading of level,
+// e.g. `###`, followed by its declaration and doc.
+func writeMarkdownFunc(b *strings.Builder, level string, f pkgdmp.Func, docMD func(string) string) {
+	if f.Receiver != nil {
+		fmt.Fprintf(b, "%s func (%s) %s\n\n", level, f.Receiver.Type, f.Name)
+	} else {
+		fmt.Fprintf(b, "%s func %s\n\n", level, f.Name)
+	}
+
+	doc := f.Doc
+	f.Doc, f.Examples = "", nil
+
+	writeMarkdownCode(b, f.String())
+	b.WriteString(docMD(doc))
+}
+
+// writeMarkdownCode writes code as a gofmt formatted Go code block.
+func writeMarkdownCode(b *strings.Builder, code string) {
+	fmt.Fprintf(b, "```go\n%s\n```\n\n", formatDecl(code))
+}
+
+// newMarkdownDocRenderer returns a function rendering doc comment text as
+// Markdown paragraphs, with doc links to other packages linked to their
+// documentation on pkg.go.dev.
+func newMarkdownDocRenderer(pkg *pkgdmp.Package) func(string) string {
+	parser := &comment.Parser{
+		LookupSym: func(recv, name string) bool {
+			return hasSymbol(pkg, recv, name)
+		},
+	}
+
+	printer := &comment.Printer{
+		HeadingLevel: 4,
+		DocLinkURL: func(link *comment.DocLink) string {
+			if link.ImportPath != "" {
+				return link.DefaultURL("https://pkg.go.dev")
+			}
+
+			return ""
+		},
+	}
+
+	return func(text string) string {
+		if text == "" {
+			return ""
+		}
+
+		return string(printer.Markdown(parser.Parse(text))) + "\n"
+	}
+}
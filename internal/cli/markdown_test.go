@@ -0,0 +1,130 @@
+package cli_test
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestMarkdownEncoder(t *testing.T) {
+	src := `// Package colors provides [Color] values.
+package colors
+
+// Color is a color.
+type Color int
+
+// Supported colors.
+const (
+	// Red is the color of blood.
+	Red Color = iota
+	Green // Green is the color of grass | leaves.
+	Blue
+)
+
+// MaxColors is the maximum number of colors.
+const MaxColors = 3
+
+// Name returns the name of c.
+func (c Color) Name() string { return "" }
+
+// Parse parses a color name.
+func Parse(name string) (Color, error) { return 0, nil }
+`
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "colors.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("error parsing source: %v", err)
+	}
+
+	dPkg, err := doc.NewFromFiles(fset, []*ast.File{file}, "example.com/colors", doc.AllDecls)
+	if err != nil {
+		t.Fatalf("error creating doc package: %v", err)
+	}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(dPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	// Packages restored from the cache must render enums as tables too, so
+	// the enum state of their consts must survive the round trip.
+	cached := cachePackage(t, pkgParser, pkg)
+
+	for name, pkg := range map[string]*pkgdmp.Package{"parsed": pkg, "cached": cached} {
+		pkg := pkg
+
+		t.Run(name, func(t *testing.T) {
+			var b strings.Builder
+
+			if err := cli.NewMarkdownEncoder(&b).Encode(pkg); err != nil {
+				t.Fatalf("expected no error; got %v", err)
+			}
+
+			got := b.String()
+
+			for _, want := range []string{
+				"# Package colors\n\nPackage colors provides Color values.\n",
+				"Supported colors.\n\n" +
+					"| Name | Value | Doc |\n" +
+					"| --- | --- | --- |\n" +
+					"| `Red` | `0` | Red is the color of blood. |\n" +
+					"| `Green` | `1` | Green is the color of grass \\| leaves. |\n" +
+					"| `Blue` | `2` |  |\n",
+				"```go\nconst MaxColors = 3\n```\n\nMaxColors is the maximum number of colors.\n",
+				"### func Parse\n\n```go\nfunc Parse(name string) (Color, error)\n```\n\nParse parses a color name.\n",
+				"### type Color\n\n```go\ntype Color int\n```\n\nColor is a color.\n",
+				"#### func (Color) Name\n\n```go\nfunc (c Color) Name() string\n```\n\nName returns the name of c.\n",
+			} {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected output to contain:\n\n%s\nbut got:\n\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+// cachePackage stores pkg in a new cache as the command does and returns the
+// package restored from it.
+func cachePackage(tb testing.TB, pkgParser *pkgdmp.Parser, pkg *pkgdmp.Package) *pkgdmp.Package {
+	tb.Helper()
+
+	cache, err := cli.NewCache(tb.TempDir())
+	if err != nil {
+		tb.Fatalf("error creating cache: %v", err)
+	}
+
+	data, err := pkgParser.MarshalPackages([]*pkgdmp.Package{pkg})
+	if err != nil {
+		tb.Fatalf("error encoding package: %v", err)
+	}
+
+	if err := cache.Put("key", data); err != nil {
+		tb.Fatalf("error storing cache entry: %v", err)
+	}
+
+	data, ok, err := cache.Get("key")
+	if !ok || err != nil {
+		tb.Fatalf("expected cache hit without error, but got ok=%t, err=%v", ok, err)
+	}
+
+	pkgs, err := pkgParser.UnmarshalPackages(data)
+	if err != nil {
+		tb.Fatalf("error decoding package: %v", err)
+	}
+
+	if len(pkgs) != 1 {
+		tb.Fatalf("expected 1 decoded package, but got %d", len(pkgs))
+	}
+
+	return pkgs[0]
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+// ErrTypeNotFound is returned by [WriteMethodDiff] if a package does not
+// declare both of the compared types.
+var ErrTypeNotFound = errors.New("type not found")
+
+// WriteMethodDiff writes a table with the methods only declared on type a,
+// only declared on type b, and declared on both types in pkg, as returned by
+// [pkgdmp.Package.MethodSetDiff].
+//
+// Nothing is written and an error wrapping [ErrTypeNotFound] is returned if
+// pkg does not declare both types.
+func WriteMethodDiff(w io.Writer, pkg *pkgdmp.Package, a, b string) error {
+	for _, name := range []string{a, b} {
+		if !hasType(pkg, name) {
+			return fmt.Errorf("%w: %s package does not declare %s", ErrTypeNotFound, pkg.Name, name)
+		}
+	}
+
+	onlyA, onlyB, common := pkg.MethodSetDiff(a, b)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "package %s\n", pkg.Name)
+	fmt.Fprintf(tw, "\tonly %s\t%s\n", a, methodList(onlyA))
+	fmt.Fprintf(tw, "\tonly %s\t%s\n", b, methodList(onlyB))
+	fmt.Fprintf(tw, "\tcommon\t%s\n", methodList(common))
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("writing method diff for %s package: %w", pkg.Name, err)
+	}
+
+	return nil
+}
+
+// hasType returns true if pkg declares a type with name.
+func hasType(pkg *pkgdmp.Package, name string) bool {
+	for _, td := range pkg.Types {
+		if td.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func methodList(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,78 @@
+package cli_test
+
+import (
+	"errors"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestWriteMethodDiff(t *testing.T) {
+	src := `package mypackage
+
+type MyStruct struct{}
+
+func (s *MyStruct) Get(key string) string { return "" }
+
+func (s *MyStruct) Close() error { return nil }
+
+type MyOtherStruct struct{}
+
+func (o MyOtherStruct) Get(k string) string { return "" }
+
+func (o MyOtherStruct) Close() {}
+
+func (o MyOtherStruct) Reset() {}
+`
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "mypackage.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("error parsing source: %v", err)
+	}
+
+	dPkg, err := doc.NewFromFiles(fset, []*ast.File{file}, "example.com/mypackage", doc.AllDecls)
+	if err != nil {
+		t.Fatalf("error creating doc package: %v", err)
+	}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(dPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	var b strings.Builder
+
+	if err := cli.WriteMethodDiff(&b, pkg, "MyStruct", "MyOtherStruct"); err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	want := `package mypackage
+  only MyStruct       Close
+  only MyOtherStruct  Close, Reset
+  common              Get
+`
+
+	if got := b.String(); got != want {
+		t.Errorf("expected output:\n\n%s\nbut got:\n\n%s", want, got)
+	}
+
+	b.Reset()
+
+	if err := cli.WriteMethodDiff(&b, pkg, "MyStruct", "MyNonExistentStruct"); !errors.Is(err, cli.ErrTypeNotFound) {
+		t.Errorf("expected error to be cli.ErrTypeNotFound, but got: %v", err)
+	}
+
+	if b.Len() != 0 {
+		t.Errorf("expected no output for package without both types, but got:\n%s", b.String())
+	}
+}
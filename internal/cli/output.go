@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+// JSONEncoder writes packages as an indented JSON array, as they are
+// produced if used as a [StreamEncoder].
+//
+// If an error occurs after the first package was written to an envelope, it
+// is written as the `error` field of the envelope, so the output is always a
+// single JSON value, and returned such that [IsReported] reports true for it.
+// Without an envelope there is no place for the error, so the array is
+// buffered and only written if all packages were encoded.
+type JSONEncoder struct {
+	// Envelope writes the array as the `packages` field of an object as
+	// written by an encoder returned by [NewJSONEnvelopeEncoder].
+	Envelope bool
+
+	// BuildInfo, if non-nil, writes the array as the `packages` field of an
+	// object as written by an encoder returned by [NewJSONBuildInfoEncoder].
+	BuildInfo *BuildInfo
+
+	// GroupByKind writes packages as returned by [pkgdmp.Package.GroupByKind].
+	GroupByKind bool
+}
+
+// Encode writes pkgs as a JSON array.
+func (e *JSONEncoder) Encode(w io.Writer, pkgs []*pkgdmp.Package) error {
+	return e.EncodeStream(w, func(fn func(*pkgdmp.Package) error) error {
+		for _, pkg := range pkgs {
+			if err := fn(pkg); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// EncodeStream writes packages as a JSON array as they are passed to the
+// function given to each.
+func (e *JSONEncoder) EncodeStream(w io.Writer, each func(fn func(*pkgdmp.Package) error) error) error {
+	var (
+		buf bytes.Buffer
+		enc *JSONArrayEncoder
+	)
+
+	switch {
+	case e.BuildInfo != nil:
+		var err error
+
+		if enc, err = NewJSONBuildInfoEncoder(w, *e.BuildInfo); err != nil {
+			return err
+		}
+	case e.Envelope:
+		enc = NewJSONEnvelopeEncoder(w)
+	default:
+		enc = NewJSONArrayEncoder(&buf)
+	}
+
+	err := each(func(pkg *pkgdmp.Package) error {
+		var v any = pkg
+		if e.GroupByKind {
+			v = pkg.GroupByKind()
+		}
+
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("encoding %s package as JSON: %w", pkg.Name, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if enc.Len() != 0 && enc.CloseWithError(err) == nil {
+			return reportedError{err}
+		}
+
+		return err
+	}
+
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	if _, err := buf.WriteTo(w); err != nil {
+		return fmt.Errorf("writing JSON output: %w", err)
+	}
+
+	return nil
+}
+
+// reportedError is an error already written to the output.
+type reportedError struct {
+	error
+}
+
+func (e reportedError) Unwrap() error {
+	return e.error
+}
+
+// IsReported returns true if err was already written to the output by an
+// encoder, such as the `error` field of a JSON envelope written by a
+// [JSONEncoder].
+func IsReported(err error) bool {
+	return errors.As(err, new(reportedError))
+}
+
+// JSONArrayEncoder writes values as elements of an indented JSON array to an
+// output stream one at a time, without holding all values in memory.
+//
+// The output is identical to encoding a slice of the values with an encoder
+// indented with two spaces.
+type JSONArrayEncoder struct {
+	w      io.Writer
+	header string
+	footer string
+	indent string
+	count  int
+}
+
+// NewJSONArrayEncoder returns a new encoder that writes to w.
+func NewJSONArrayEncoder(w io.Writer) *JSONArrayEncoder {
+	return &JSONArrayEncoder{w: w}
+}
+
+// NewJSONEnvelopeEncoder returns a new encoder that writes to w, with the
+// array as the `packages` field of an object with a `schemaVersion` field
+// set to [pkgdmp.SchemaVersion].
+//
+// The output is identical to encoding such an object with an encoder indented
+// with two spaces.
+func NewJSONEnvelopeEncoder(w io.Writer) *JSONArrayEncoder {
+	return &JSONArrayEncoder{
+		w:      w,
+		header: fmt.Sprintf("{\n  \"schemaVersion\": %d,\n  \"packages\": ", pkgdmp.SchemaVersion),
+		footer: "\n}",
+		indent: "  ",
+	}
+}
+
+// NewJSONBuildInfoEncoder returns a new encoder that writes to w like an
+// encoder returned by [NewJSONEnvelopeEncoder], with an additional
+// `buildInfo` field set to info.
+func NewJSONBuildInfoEncoder(w io.Writer, info BuildInfo) (*JSONArrayEncoder, error) {
+	data, err := json.MarshalIndent(info, "  ", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding build info: %w", err)
+	}
+
+	return &JSONArrayEncoder{
+		w: w,
+		header: fmt.Sprintf("{\n  \"schemaVersion\": %d,\n  \"buildInfo\": %s,\n  \"packages\": ",
+			pkgdmp.SchemaVersion, data,
+		),
+		footer: "\n}",
+		indent: "  ",
+	}, nil
+}
+
+// Encode writes the JSON encoding of v as the next element of the array.
+func (e *JSONArrayEncoder) Encode(v any) error {
+	data, err := json.MarshalIndent(v, e.indent+"  ", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding array element: %w", err)
+	}
+
+	sep := ",\n  " + e.indent
+	if e.count == 0 {
+		sep = e.header + "[\n  " + e.indent
+	}
+
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return fmt.Errorf("writing array element separator: %w", err)
+	}
+
+	if _, err := e.w.Write(data); err != nil {
+		return fmt.Errorf("writing array element: %w", err)
+	}
+
+	e.count++
+
+	return nil
+}
+
+// Len returns the number of values encoded as elements of the array.
+func (e *JSONArrayEncoder) Len() int {
+	return e.count
+}
+
+// Close writes the end of the array, or an empty array if no values were
+// encoded.
+func (e *JSONArrayEncoder) Close() error {
+	return e.close("")
+}
+
+// CloseWithError writes the end of the array like [JSONArrayEncoder.Close],
+// followed by an `error` field with the message of err in the object the
+// array is a field of.
+//
+// It returns an error without writing anything if the array is not a field
+// of an object, as created by [NewJSONArrayEncoder].
+func (e *JSONArrayEncoder) CloseWithError(err error) error {
+	if e.footer == "" {
+		return errors.New("closing JSON array with error: array is not a field of an object")
+	}
+
+	data, mErr := json.Marshal(err.Error())
+	if mErr != nil {
+		return fmt.Errorf("encoding error as JSON: %w", mErr)
+	}
+
+	return e.close(",\n" + e.indent + "\"error\": " + string(data))
+}
+
+// close writes the end of the array followed by fields, the remaining fields
+// of the object the array is a field of, if any.
+func (e *JSONArrayEncoder) close(fields string) error {
+	end := "\n" + e.indent + "]" + fields + e.footer + "\n"
+	if e.count == 0 {
+		end = e.header + "[]" + fields + e.footer + "\n"
+	}
+
+	if _, err := io.WriteString(e.w, end); err != nil {
+		return fmt.Errorf("writing array end: %w", err)
+	}
+
+	return nil
+}
+
+// WriteJSONError writes err to w as an indented JSON object with the error
+// message in its `error` field.
+func WriteJSONError(w io.Writer, err error) error {
+	data, mErr := json.MarshalIndent(struct {
+		Error string `json:"error"`
+	}{err.Error()}, "", "  ")
+	if mErr != nil {
+		return fmt.Errorf("encoding error as JSON: %w", mErr)
+	}
+
+	if _, wErr := fmt.Fprintf(w, "%s\n", data); wErr != nil {
+		return fmt.Errorf("writing JSON error: %w", wErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,382 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestJSONArrayEncoder(t *testing.T) {
+	tt := []struct {
+		name string
+		pkgs []*pkgdmp.Package
+	}{
+		{"no packages", []*pkgdmp.Package{}},
+		{"one package", testPackages(1)},
+		{"multiple packages", testPackages(3)},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			var want bytes.Buffer
+
+			enc := json.NewEncoder(&want)
+			enc.SetIndent("", "  ")
+
+			if err := enc.Encode(tc.pkgs); err != nil {
+				t.Fatalf("error encoding packages: %v", err)
+			}
+
+			var actual bytes.Buffer
+
+			arrEnc := cli.NewJSONArrayEncoder(&actual)
+
+			for _, pkg := range tc.pkgs {
+				if err := arrEnc.Encode(pkg); err != nil {
+					t.Fatalf("expected no error when encoding package, but got: %v", err)
+				}
+			}
+
+			if err := arrEnc.Close(); err != nil {
+				t.Fatalf("expected no error when closing encoder, but got: %v", err)
+			}
+
+			if actual.String() != want.String() {
+				t.Errorf("expected output:\n\n%s\n\nbut got:\n\n%s", want.String(), actual.String())
+			}
+		})
+	}
+}
+
+func TestJSONEnvelopeEncoder(t *testing.T) {
+	tt := []struct {
+		name string
+		pkgs []*pkgdmp.Package
+	}{
+		{"no packages", []*pkgdmp.Package{}},
+		{"one package", testPackages(1)},
+		{"multiple packages", testPackages(3)},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			var want bytes.Buffer
+
+			enc := json.NewEncoder(&want)
+			enc.SetIndent("", "  ")
+
+			envelope := struct {
+				SchemaVersion int               `json:"schemaVersion"`
+				Packages      []*pkgdmp.Package `json:"packages"`
+			}{pkgdmp.SchemaVersion, tc.pkgs}
+
+			if err := enc.Encode(envelope); err != nil {
+				t.Fatalf("error encoding envelope: %v", err)
+			}
+
+			var actual bytes.Buffer
+
+			envEnc := cli.NewJSONEnvelopeEncoder(&actual)
+
+			for _, pkg := range tc.pkgs {
+				if err := envEnc.Encode(pkg); err != nil {
+					t.Fatalf("expected no error when encoding package, but got: %v", err)
+				}
+			}
+
+			if err := envEnc.Close(); err != nil {
+				t.Fatalf("expected no error when closing encoder, but got: %v", err)
+			}
+
+			if actual.String() != want.String() {
+				t.Errorf("expected output:\n\n%s\n\nbut got:\n\n%s", want.String(), actual.String())
+			}
+		})
+	}
+}
+
+func TestJSONBuildInfoEncoder(t *testing.T) {
+	info := cli.BuildInfo{
+		Version:     "1.2.3",
+		Commit:      "abc123",
+		GoVersion:   "go1.22.0",
+		GeneratedAt: "2024-01-02T03:04:05Z",
+	}
+
+	for _, pkgs := range [][]*pkgdmp.Package{{}, testPackages(2)} {
+		var want bytes.Buffer
+
+		enc := json.NewEncoder(&want)
+		enc.SetIndent("", "  ")
+
+		envelope := struct {
+			SchemaVersion int               `json:"schemaVersion"`
+			BuildInfo     cli.BuildInfo     `json:"buildInfo"`
+			Packages      []*pkgdmp.Package `json:"packages"`
+		}{pkgdmp.SchemaVersion, info, pkgs}
+
+		if err := enc.Encode(envelope); err != nil {
+			t.Fatalf("error encoding envelope: %v", err)
+		}
+
+		var actual bytes.Buffer
+
+		biEnc, err := cli.NewJSONBuildInfoEncoder(&actual, info)
+		if err != nil {
+			t.Fatalf("expected no error when creating encoder, but got: %v", err)
+		}
+
+		for _, pkg := range pkgs {
+			if err := biEnc.Encode(pkg); err != nil {
+				t.Fatalf("expected no error when encoding package, but got: %v", err)
+			}
+		}
+
+		if err := biEnc.Close(); err != nil {
+			t.Fatalf("expected no error when closing encoder, but got: %v", err)
+		}
+
+		if actual.String() != want.String() {
+			t.Errorf("expected output:\n\n%s\n\nbut got:\n\n%s", want.String(), actual.String())
+		}
+	}
+}
+
+func TestJSONArrayEncoder_CloseWithError(t *testing.T) {
+	encErr := fmt.Errorf("parsing files in dir: %w", errors.New(`unexpected "}"`))
+
+	for _, tc := range []struct {
+		name string
+		pkgs []*pkgdmp.Package
+	}{
+		{"no packages", []*pkgdmp.Package{}},
+		{"multiple packages", testPackages(2)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var want bytes.Buffer
+
+			enc := json.NewEncoder(&want)
+			enc.SetIndent("", "  ")
+
+			envelope := struct {
+				SchemaVersion int               `json:"schemaVersion"`
+				Packages      []*pkgdmp.Package `json:"packages"`
+				Error         string            `json:"error"`
+			}{pkgdmp.SchemaVersion, tc.pkgs, encErr.Error()}
+
+			if err := enc.Encode(envelope); err != nil {
+				t.Fatalf("error encoding envelope: %v", err)
+			}
+
+			var actual bytes.Buffer
+
+			envEnc := cli.NewJSONEnvelopeEncoder(&actual)
+
+			for _, pkg := range tc.pkgs {
+				if err := envEnc.Encode(pkg); err != nil {
+					t.Fatalf("expected no error when encoding package, but got: %v", err)
+				}
+			}
+
+			if err := envEnc.CloseWithError(encErr); err != nil {
+				t.Fatalf("expected no error when closing encoder, but got: %v", err)
+			}
+
+			if actual.String() != want.String() {
+				t.Errorf("expected output:\n\n%s\n\nbut got:\n\n%s", want.String(), actual.String())
+			}
+		})
+	}
+
+	t.Run("array", func(t *testing.T) {
+		var actual bytes.Buffer
+
+		if err := cli.NewJSONArrayEncoder(&actual).CloseWithError(encErr); err == nil {
+			t.Error("expected error when closing array encoder with error, but got nil")
+		}
+
+		if actual.Len() != 0 {
+			t.Errorf("expected no output, but got:\n\n%s", actual.String())
+		}
+	})
+}
+
+func TestJSONEncoder(t *testing.T) {
+	pkgs := testPackages(2)
+
+	t.Run("array", func(t *testing.T) {
+		var want, actual bytes.Buffer
+
+		if err := encodeIndented(&want, pkgs); err != nil {
+			t.Fatalf("error encoding packages: %v", err)
+		}
+
+		if err := (&cli.JSONEncoder{}).Encode(&actual, pkgs); err != nil {
+			t.Fatalf("expected no error, but got: %v", err)
+		}
+
+		if actual.String() != want.String() {
+			t.Errorf("expected output:\n\n%s\n\nbut got:\n\n%s", want.String(), actual.String())
+		}
+	})
+
+	t.Run("envelope grouped by kind", func(t *testing.T) {
+		grouped := make([]*pkgdmp.GroupedPackage, len(pkgs))
+		for i, pkg := range pkgs {
+			grouped[i] = pkg.GroupByKind()
+		}
+
+		var want, actual bytes.Buffer
+
+		envelope := struct {
+			SchemaVersion int                      `json:"schemaVersion"`
+			Packages      []*pkgdmp.GroupedPackage `json:"packages"`
+		}{pkgdmp.SchemaVersion, grouped}
+
+		if err := encodeIndented(&want, envelope); err != nil {
+			t.Fatalf("error encoding envelope: %v", err)
+		}
+
+		if err := (&cli.JSONEncoder{Envelope: true, GroupByKind: true}).Encode(&actual, pkgs); err != nil {
+			t.Fatalf("expected no error, but got: %v", err)
+		}
+
+		if actual.String() != want.String() {
+			t.Errorf("expected output:\n\n%s\n\nbut got:\n\n%s", want.String(), actual.String())
+		}
+	})
+
+	encErr := errors.New("parsing files in dir")
+
+	failAfter := func(fn func(*pkgdmp.Package) error) error {
+		if err := fn(pkgs[0]); err != nil {
+			return err
+		}
+
+		return encErr
+	}
+
+	t.Run("stream error in envelope", func(t *testing.T) {
+		var actual bytes.Buffer
+
+		err := (&cli.JSONEncoder{Envelope: true}).EncodeStream(&actual, failAfter)
+		if !errors.Is(err, encErr) || !cli.IsReported(err) {
+			t.Fatalf("expected reported %v error, but got: %v", encErr, err)
+		}
+
+		var envelope struct {
+			Packages []*pkgdmp.Package `json:"packages"`
+			Error    string            `json:"error"`
+		}
+
+		if err := json.Unmarshal(actual.Bytes(), &envelope); err != nil {
+			t.Fatalf("expected output to be a single JSON value, but got: %v\n\n%s", err, actual.String())
+		}
+
+		if len(envelope.Packages) != 1 || envelope.Error != encErr.Error() {
+			t.Errorf("expected 1 package and error %q, but got %d packages and error %q",
+				encErr, len(envelope.Packages), envelope.Error,
+			)
+		}
+	})
+
+	t.Run("stream error in array", func(t *testing.T) {
+		var actual bytes.Buffer
+
+		err := (&cli.JSONEncoder{}).EncodeStream(&actual, failAfter)
+		if !errors.Is(err, encErr) || cli.IsReported(err) {
+			t.Fatalf("expected unreported %v error, but got: %v", encErr, err)
+		}
+
+		if actual.Len() != 0 {
+			t.Errorf("expected no output, but got:\n\n%s", actual.String())
+		}
+	})
+}
+
+func TestWriteJSONError(t *testing.T) {
+	var b bytes.Buffer
+
+	if err := cli.WriteJSONError(&b, fmt.Errorf("parsing files in dir: %w", errors.New(`unexpected "}"`))); err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	want := "{\n  \"error\": \"parsing files in dir: unexpected \\\"}\\\"\"\n}\n"
+
+	if b.String() != want {
+		t.Errorf("expected output:\n\n%s\nbut got:\n\n%s", want, b.String())
+	}
+}
+
+func BenchmarkJSONArrayEncoder(b *testing.B) {
+	pkgs := testPackages(100)
+
+	b.Run("streaming", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			enc := cli.NewJSONArrayEncoder(io.Discard)
+
+			for _, pkg := range pkgs {
+				if err := enc.Encode(pkg); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			if err := enc.Close(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("slice", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			enc := json.NewEncoder(io.Discard)
+			enc.SetIndent("", "  ")
+
+			if err := enc.Encode(pkgs); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func encodeIndented(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v) //nolint:wrapcheck // test helper.
+}
+
+func testPackages(n int) []*pkgdmp.Package {
+	pkgs := make([]*pkgdmp.Package, n)
+
+	for i := range pkgs {
+		pkgs[i] = &pkgdmp.Package{
+			Name: fmt.Sprintf("package%d", i),
+			Doc:  "Package is an example package.",
+			Funcs: []pkgdmp.Func{
+				{
+					Name:    "MyFunc",
+					Doc:     "MyFunc is an example function.",
+					Params:  []pkgdmp.Field{{Names: []string{"s"}, Type: "string"}},
+					Results: []pkgdmp.Field{{Type: "error"}},
+				},
+			},
+		}
+	}
+
+	return pkgs
+}
@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+// PlantUMLEncoder writes the struct and interface types of packages as
+// PlantUML class diagrams to an output stream.
+//
+// Each package is written as a separate diagram enclosed in `@startuml` and
+// `@enduml`. Structs are written as classes with their fields and methods,
+// and interfaces with their methods. Relationships between the types are
+// inferred from the parsed entities:
+//
+//   - Types with all methods of an interface, including methods promoted
+//     from embedded types of the package, realize the interface.
+//   - Types embedding another type are composed of it.
+//   - Types with fields referring to another type are associated with it.
+type PlantUMLEncoder struct {
+	w io.Writer
+}
+
+// NewPlantUMLEncoder returns a new encoder that writes to w.
+func NewPlantUMLEncoder(w io.Writer) *PlantUMLEncoder {
+	return &PlantUMLEncoder{w: w}
+}
+
+// Encode writes a class diagram of the types in pkg.
+func (e *PlantUMLEncoder) Encode(pkg *pkgdmp.Package) error {
+	var (
+		b       strings.Builder
+		classes []pkgdmp.TypeDef
+	)
+
+	for _, td := range pkg.Types {
+		if td.Type == "struct" || td.Type == "interface" {
+			classes = append(classes, withReceiverFuncs(td, pkg.Funcs))
+		}
+	}
+
+	fmt.Fprintf(&b, "@startuml\npackage %s {\n", pkg.Name)
+
+	for _, td := range classes {
+		writeClass(&b, td)
+	}
+
+	b.WriteString("}\n")
+
+	for _, rel := range relationships(classes) {
+		fmt.Fprintf(&b, "%s\n", rel)
+	}
+
+	b.WriteString("@enduml\n")
+
+	if _, err := io.WriteString(e.w, b.String()); err != nil {
+		return fmt.Errorf("writing class diagram for %s package: %w", pkg.Name, err)
+	}
+
+	return nil
+}
+
+// withReceiverFuncs returns td with the functions in fns declared with td as
+// receiver type added to its methods.
+func withReceiverFuncs(td pkgdmp.TypeDef, fns []pkgdmp.Func) pkgdmp.TypeDef {
+	methods := append([]pkgdmp.Func(nil), td.Methods...)
+
+	for _, f := range fns {
+		if f.ReceiverType() == td.Name {
+			methods = append(methods, f)
+		}
+	}
+
+	td.Methods = methods
+
+	return td
+}
+
+func writeClass(b *strings.Builder, td pkgdmp.TypeDef) {
+	kw := "class"
+	if td.Type == "interface" {
+		kw = "interface"
+	}
+
+	fmt.Fprintf(b, "  %s %s {\n", kw, td.Name)
+
+	for _, f := range td.Fields {
+		if len(f.Names) == 0 {
+			fmt.Fprintf(b, "    %s%s\n", visibility(embeddedName(f.Type)), f.Type)
+			continue
+		}
+
+		for _, name := range f.Names {
+			fmt.Fprintf(b, "    %s%s %s\n", visibility(name), name, f.Type)
+		}
+	}
+
+	for _, m := range td.Methods {
+		fmt.Fprintf(b, "    %s%s\n", visibility(m.Name), methodSignature(m, true))
+	}
+
+	b.WriteString("  }\n")
+}
+
+// relationships returns the relationships between classes in PlantUML
+// syntax, sorted and without duplicates.
+func relationships(classes []pkgdmp.TypeDef) []string {
+	names := make(map[string]struct{}, len(classes))
+	byName := make(map[string]pkgdmp.TypeDef, len(classes))
+
+	for _, td := range classes {
+		names[td.Name] = struct{}{}
+		byName[td.Name] = td
+	}
+
+	seen := make(map[string]struct{})
+
+	var res []string
+
+	add := func(rel string) {
+		if _, ok := seen[rel]; !ok {
+			seen[rel] = struct{}{}
+			res = append(res, rel)
+		}
+	}
+
+	for _, td := range classes {
+		sigs := methodSet(td, byName, make(map[string]struct{}))
+
+		for _, iface := range classes {
+			if iface.Type == "interface" && iface.Name != td.Name && implements(sigs, iface) {
+				add(fmt.Sprintf("%s ..|> %s", td.Name, iface.Name))
+			}
+		}
+
+		for _, f := range td.Fields {
+			for _, ref := range typeRefs(f.Type, names) {
+				if ref == td.Name {
+					continue
+				}
+
+				if len(f.Names) == 0 {
+					add(fmt.Sprintf("%s *-- %s", td.Name, ref))
+					continue
+				}
+
+				add(fmt.Sprintf("%s --> %s : %s", td.Name, ref, strings.Join(f.Names, ", ")))
+			}
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// methodSet returns the signatures of the methods of td and the methods
+// promoted from embedded types in byName, skipping types in visited.
+func methodSet(td pkgdmp.TypeDef, byName map[string]pkgdmp.TypeDef, visited map[string]struct{}) map[string]struct{} {
+	visited[td.Name] = struct{}{}
+	sigs := make(map[string]struct{}, len(td.Methods))
+
+	for _, m := range td.Methods {
+		sigs[methodSignature(m, false)] = struct{}{}
+	}
+
+	for _, f := range td.Fields {
+		if len(f.Names) != 0 {
+			continue
+		}
+
+		name := embeddedName(f.Type)
+		if _, ok := visited[name]; ok {
+			continue
+		}
+
+		if embedded, ok := byName[name]; ok {
+			for sig := range methodSet(embedded, byName, visited) {
+				sigs[sig] = struct{}{}
+			}
+		}
+	}
+
+	return sigs
+}
+
+// implements returns true if sigs contains the signatures of all methods of
+// the interface type iface, which must have at least one method.
+func implements(sigs map[string]struct{}, iface pkgdmp.TypeDef) bool {
+	if len(iface.Methods) == 0 {
+		return false
+	}
+
+	for _, m := range iface.Methods {
+		if _, ok := sigs[methodSignature(m, false)]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// methodSignature returns the method's name followed by its parameters and
+// results, with parameter and result names if withNames is true.
+func methodSignature(m pkgdmp.Func, withNames bool) string {
+	sig := fmt.Sprintf("%s(%s)", m.Name, fieldList(m.Params, withNames))
+
+	switch {
+	case len(m.Results) == 1 && len(m.Results[0].Names) == 0:
+		sig += " " + m.Results[0].Type
+	case len(m.Results) != 0:
+		sig += " (" + fieldList(m.Results, withNames) + ")"
+	}
+
+	return sig
+}
+
+func fieldList(fields []pkgdmp.Field, withNames bool) string {
+	var items []string
+
+	for _, f := range fields {
+		if len(f.Names) == 0 {
+			items = append(items, f.Type)
+			continue
+		}
+
+		if !withNames {
+			for range f.Names {
+				items = append(items, f.Type)
+			}
+
+			continue
+		}
+
+		items = append(items, strings.Join(f.Names, ", ")+" "+f.Type)
+	}
+
+	return strings.Join(items, ", ")
+}
+
+// typeRefs returns the names in names referred to by type expression typ, in
+// order of appearance.
+func typeRefs(typ string, names map[string]struct{}) []string {
+	var refs []string
+
+	for _, ref := range pkgdmp.TypeRefs(typ) {
+		if _, ok := names[ref]; ok {
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
+
+// visibility returns the PlantUML visibility modifier for name.
+func visibility(name string) string {
+	if token.IsExported(name) {
+		return "+"
+	}
+
+	return "-"
+}
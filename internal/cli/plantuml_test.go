@@ -0,0 +1,103 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestPlantUMLEncoder(t *testing.T) {
+	pkg := &pkgdmp.Package{
+		Name: "shapes",
+		Types: []pkgdmp.TypeDef{
+			{
+				Type: "interface",
+				Name: "Shape",
+				Methods: []pkgdmp.Func{
+					{Name: "Area", Results: []pkgdmp.Field{{Type: "float64"}}},
+					{Name: "Name", Results: []pkgdmp.Field{{Type: "string"}}},
+				},
+			},
+			{
+				Type:   "struct",
+				Name:   "Base",
+				Fields: []pkgdmp.Field{{Names: []string{"name"}, Type: "string"}},
+			},
+			{
+				Type: "struct",
+				Name: "Circle",
+				Fields: []pkgdmp.Field{
+					{Type: "*Base"},
+					{Names: []string{"Radius"}, Type: "float64"},
+					{Names: []string{"Center"}, Type: "*Point"},
+					{Names: []string{"Out"}, Type: "io.Writer"},
+				},
+				Methods: []pkgdmp.Func{
+					{
+						Name:     "Area",
+						Receiver: &pkgdmp.Field{Names: []string{"c"}, Type: "*Circle"},
+						Results:  []pkgdmp.Field{{Type: "float64"}},
+					},
+				},
+			},
+			{
+				Type:   "struct",
+				Name:   "Point",
+				Fields: []pkgdmp.Field{{Names: []string{"X", "Y"}, Type: "int"}},
+			},
+			{Type: "int", Name: "Unit"},
+		},
+		Funcs: []pkgdmp.Func{
+			{
+				Name:     "Name",
+				Receiver: &pkgdmp.Field{Names: []string{"b"}, Type: "Base"},
+				Results:  []pkgdmp.Field{{Type: "string"}},
+			},
+			{
+				Name:    "Scale",
+				Params:  []pkgdmp.Field{{Names: []string{"s"}, Type: "Shape"}, {Names: []string{"x", "y"}, Type: "float64"}},
+				Results: []pkgdmp.Field{{Names: []string{"area"}, Type: "float64"}, {Names: []string{"err"}, Type: "error"}},
+			},
+		},
+	}
+
+	want := `@startuml
+package shapes {
+  interface Shape {
+    +Area() float64
+    +Name() string
+  }
+  class Base {
+    -name string
+    +Name() string
+  }
+  class Circle {
+    +*Base
+    +Radius float64
+    +Center *Point
+    +Out io.Writer
+    +Area() float64
+  }
+  class Point {
+    +X int
+    +Y int
+  }
+}
+Circle *-- Base
+Circle --> Point : Center
+Circle ..|> Shape
+@enduml
+`
+
+	var b strings.Builder
+
+	if err := cli.NewPlantUMLEncoder(&b).Encode(pkg); err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+
+	if b.String() != want {
+		t.Errorf("expected output:\n\n%s\nbut got:\n\n%s", want, b.String())
+	}
+}
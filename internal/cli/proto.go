@@ -0,0 +1,302 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+// protoScalars maps Go types to protobuf scalar types.
+var protoScalars = map[string]string{
+	"bool":    "bool",
+	"string":  "string",
+	"error":   "string",
+	"int":     "int64",
+	"int8":    "int32",
+	"int16":   "int32",
+	"int32":   "int32",
+	"rune":    "int32",
+	"int64":   "int64",
+	"uint":    "uint64",
+	"uint8":   "uint32",
+	"byte":    "uint32",
+	"uint16":  "uint32",
+	"uint32":  "uint32",
+	"uint64":  "uint64",
+	"uintptr": "uint64",
+	"float32": "float",
+	"float64": "double",
+	"[]byte":  "bytes",
+}
+
+// ProtoEncoder writes a best-effort protobuf (proto3) representation of
+// packages to an output stream.
+//
+// Exported structs become messages and exported functions become RPCs in a
+// service named after the package, with a request message for the parameters
+// and a response message for the results. Other symbols are skipped.
+//
+// Message field numbers are assigned sequentially from 1 in declaration
+// order. Every field name consumes a number, including fields with types that
+// have no protobuf equivalent, such as channels, functions and type
+// parameters of generic types and functions. Numbers of such
+// fields are reserved instead of being reused, so numbers of the remaining
+// fields stay stable if support for more types is added.
+type ProtoEncoder struct {
+	w     io.Writer
+	count int
+}
+
+// NewProtoEncoder returns a new encoder that writes to w.
+func NewProtoEncoder(w io.Writer) *ProtoEncoder {
+	return &ProtoEncoder{w: w}
+}
+
+// Encode writes the protobuf representation of pkg.
+func (e *ProtoEncoder) Encode(pkg *pkgdmp.Package) error {
+	var b strings.Builder
+
+	if e.count != 0 {
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "syntax = \"proto3\";\n\npackage %s;\n", pkg.Name)
+
+	for _, td := range pkg.Types {
+		if td.Type != "struct" || !td.IsExported() {
+			continue
+		}
+
+		b.WriteString("\n")
+		writeProtoMessage(&b, td.Name, td.Doc, td.Fields, typeParamNames(td.TypeParams))
+	}
+
+	var funcs []pkgdmp.Func
+
+	for _, f := range pkg.Funcs {
+		if f.Receiver != nil || !f.IsExported() {
+			continue
+		}
+
+		funcs = append(funcs, f)
+	}
+
+	if len(funcs) != 0 {
+		writeProtoService(&b, pkg.Name, funcs)
+	}
+
+	if _, err := io.WriteString(e.w, b.String()); err != nil {
+		return fmt.Errorf("writing protobuf representation of %s package: %w", pkg.Name, err)
+	}
+
+	e.count++
+
+	return nil
+}
+
+func writeProtoService(b *strings.Builder, pkgName string, funcs []pkgdmp.Func) {
+	for _, f := range funcs {
+		typeParams := typeParamNames(f.TypeParams)
+
+		b.WriteString("\n")
+		writeProtoMessage(b, f.Name+"Request", "", namedFields(f.Params, "arg"), typeParams)
+		b.WriteString("\n")
+		writeProtoMessage(b, f.Name+"Response", "", namedFields(f.Results, "result"), typeParams)
+	}
+
+	fmt.Fprintf(b, "\nservice %s {\n", exportedName(pkgName))
+
+	for _, f := range funcs {
+		writeProtoComment(b, f.Doc, "  ")
+		fmt.Fprintf(b, "  rpc %[1]s(%[1]sRequest) returns (%[1]sResponse);\n", f.Name)
+	}
+
+	b.WriteString("}\n")
+}
+
+func writeProtoMessage(
+	b *strings.Builder,
+	name, doc string,
+	fields []pkgdmp.Field,
+	typeParams map[string]bool,
+) {
+	writeProtoComment(b, doc, "")
+	fmt.Fprintf(b, "message %s {\n", name)
+
+	num := 0
+
+	for _, f := range fields {
+		names := f.Names
+		if len(names) == 0 {
+			names = []string{embeddedName(f.Type)}
+		}
+
+		typ, ok := protoType(f.Type, typeParams)
+
+		for _, n := range names {
+			num++
+
+			if !ok {
+				fmt.Fprintf(b, "  reserved %d; // %s %s\n", num, n, strings.Join(strings.Fields(f.Type), " "))
+				continue
+			}
+
+			writeProtoComment(b, f.Doc, "  ")
+			fmt.Fprintf(b, "  %s %s = %d;", typ, n, num)
+
+			if f.Comment != "" {
+				fmt.Fprintf(b, " // %s", f.Comment)
+			}
+
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("}\n")
+}
+
+func writeProtoComment(b *strings.Builder, doc, indent string) {
+	if doc == "" {
+		return
+	}
+
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(b, "%s// %s\n", indent, line)
+	}
+}
+
+// protoType returns the protobuf type for a Go type, or false if the type
+// has no protobuf equivalent. Types named in typeParams are type parameters
+// and have no protobuf equivalent.
+func protoType(goType string, typeParams map[string]bool) (string, bool) {
+	goType = strings.TrimLeft(goType, "*")
+
+	if t, ok := protoScalars[goType]; ok {
+		return t, true
+	}
+
+	if strings.HasPrefix(goType, "map[") {
+		return protoMapType(goType, typeParams)
+	}
+
+	if strings.HasPrefix(goType, "[") {
+		i := strings.IndexByte(goType, ']')
+
+		elt, ok := protoType(goType[i+1:], typeParams)
+		if !ok || strings.HasPrefix(elt, "repeated ") || strings.HasPrefix(elt, "map<") {
+			return "", false
+		}
+
+		return "repeated " + elt, true
+	}
+
+	// Instantiated generic types map to the message of the generic type.
+	if i := strings.IndexByte(goType, '['); i > 0 && strings.HasSuffix(goType, "]") {
+		goType = goType[:i]
+	}
+
+	if !isQualifiedIdent(goType) || goType == "any" || typeParams[goType] {
+		return "", false
+	}
+
+	return goType, true
+}
+
+func protoMapType(goType string, typeParams map[string]bool) (string, bool) {
+	depth := 0
+
+	for i := len("map"); i < len(goType); i++ {
+		switch goType[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+
+		if depth != 0 {
+			continue
+		}
+
+		key, ok := protoScalars[goType[len("map["):i]]
+		if !ok || key == "double" || key == "float" || key == "bytes" {
+			return "", false
+		}
+
+		val, ok := protoType(goType[i+1:], typeParams)
+		if !ok || strings.HasPrefix(val, "repeated ") || strings.HasPrefix(val, "map<") {
+			return "", false
+		}
+
+		return fmt.Sprintf("map<%s, %s>", key, val), true
+	}
+
+	return "", false
+}
+
+// typeParamNames returns the set of names declared by typeParams.
+func typeParamNames(typeParams []pkgdmp.Field) map[string]bool {
+	names := make(map[string]bool)
+
+	for _, tp := range typeParams {
+		for _, n := range tp.Names {
+			names[n] = true
+		}
+	}
+
+	return names
+}
+
+// namedFields returns a copy of fields where unnamed fields are given names
+// with prefix followed by their position, e.g. `arg1`.
+func namedFields(fields []pkgdmp.Field, prefix string) []pkgdmp.Field {
+	res := make([]pkgdmp.Field, len(fields))
+
+	for i, f := range fields {
+		if len(f.Names) == 0 {
+			f.Names = []string{fmt.Sprintf("%s%d", prefix, i+1)}
+		}
+
+		res[i] = f
+	}
+
+	return res
+}
+
+// embeddedName returns the field name of an embedded field with type typ.
+func embeddedName(typ string) string {
+	typ = strings.TrimLeft(typ, "*")
+
+	if i := strings.LastIndexByte(typ, '.'); i != -1 {
+		typ = typ[i+1:]
+	}
+
+	return typ
+}
+
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+
+	return string(r)
+}
+
+func isQualifiedIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r != '_' && r != '.' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
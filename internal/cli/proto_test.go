@@ -0,0 +1,165 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestProtoEncoder(t *testing.T) {
+	pkg := &pkgdmp.Package{
+		Name: "mypackage",
+		Types: []pkgdmp.TypeDef{
+			{
+				Type: "struct",
+				Name: "User",
+				Doc:  "User is an example struct.",
+				Fields: []pkgdmp.Field{
+					{Names: []string{"ID"}, Type: "int", Comment: "unique ID."},
+					{Names: []string{"First", "Last"}, Type: "string"},
+					{Names: []string{"Done"}, Type: "chan struct{}"},
+					{Names: []string{"Tags"}, Type: "[]string"},
+					{Names: []string{"Attrs"}, Type: "map[string]*Attr"},
+					{Type: "*time.Time"},
+				},
+			},
+			{Type: "struct", Name: "unexported"},
+			{Type: "interface", Name: "Store"},
+		},
+		Funcs: []pkgdmp.Func{
+			{
+				Name:    "GetUser",
+				Doc:     "GetUser returns a user.",
+				Params:  []pkgdmp.Field{{Names: []string{"id"}, Type: "int"}},
+				Results: []pkgdmp.Field{{Type: "*User"}, {Type: "error"}},
+			},
+			{Name: "getUser"},
+			{Name: "Save", Receiver: &pkgdmp.Field{Names: []string{"u"}, Type: "*User"}},
+		},
+	}
+
+	want := `syntax = "proto3";
+
+package mypackage;
+
+// User is an example struct.
+message User {
+  int64 ID = 1; // unique ID.
+  string First = 2;
+  string Last = 3;
+  reserved 4; // Done chan struct{}
+  repeated string Tags = 5;
+  map<string, Attr> Attrs = 6;
+  time.Time Time = 7;
+}
+
+message GetUserRequest {
+  int64 id = 1;
+}
+
+message GetUserResponse {
+  User result1 = 1;
+  string result2 = 2;
+}
+
+service Mypackage {
+  // GetUser returns a user.
+  rpc GetUser(GetUserRequest) returns (GetUserResponse);
+}
+`
+
+	var b strings.Builder
+
+	if err := cli.NewProtoEncoder(&b).Encode(pkg); err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if actual := b.String(); actual != want {
+		t.Errorf("expected output:\n\n%s\nbut got:\n\n%s", want, actual)
+	}
+}
+
+func TestProtoEncoder_MultiplePackages(t *testing.T) {
+	var b strings.Builder
+
+	enc := cli.NewProtoEncoder(&b)
+
+	for _, pkg := range testPackages(2) {
+		if err := enc.Encode(pkg); err != nil {
+			t.Fatalf("expected no error, but got: %v", err)
+		}
+	}
+
+	if n := strings.Count(b.String(), "syntax = \"proto3\";"); n != 2 {
+		t.Errorf("expected 2 syntax declarations, but got %d", n)
+	}
+
+	if !strings.Contains(b.String(), "}\n\nsyntax") {
+		t.Errorf("expected packages to be separated by a blank line, but got:\n\n%s", b.String())
+	}
+}
+
+func TestProtoEncoder_TypeParams(t *testing.T) {
+	pkg := &pkgdmp.Package{
+		Name: "mypackage",
+		Types: []pkgdmp.TypeDef{
+			{
+				Type:       "struct",
+				Name:       "Box",
+				TypeParams: []pkgdmp.Field{{Names: []string{"T"}, Type: "any"}},
+				Fields: []pkgdmp.Field{
+					{Names: []string{"Value"}, Type: "T"},
+					{Names: []string{"Values"}, Type: "[]*T"},
+					{Names: []string{"Name"}, Type: "string"},
+				},
+			},
+		},
+		Funcs: []pkgdmp.Func{
+			{
+				Name:       "New",
+				TypeParams: []pkgdmp.Field{{Names: []string{"K", "V"}, Type: "comparable"}},
+				Params: []pkgdmp.Field{
+					{Names: []string{"k"}, Type: "K"},
+					{Names: []string{"m"}, Type: "map[string]V"},
+				},
+				Results: []pkgdmp.Field{{Type: "*Box[V]"}},
+			},
+		},
+	}
+
+	want := `syntax = "proto3";
+
+package mypackage;
+
+message Box {
+  reserved 1; // Value T
+  reserved 2; // Values []*T
+  string Name = 3;
+}
+
+message NewRequest {
+  reserved 1; // k K
+  reserved 2; // m map[string]V
+}
+
+message NewResponse {
+  Box result1 = 1;
+}
+
+service Mypackage {
+  rpc New(NewRequest) returns (NewResponse);
+}
+`
+
+	var b strings.Builder
+
+	if err := cli.NewProtoEncoder(&b).Encode(pkg); err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if actual := b.String(); actual != want {
+		t.Errorf("expected output:\n\n%s\nbut got:\n\n%s", want, actual)
+	}
+}
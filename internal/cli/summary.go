@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+// packageSummary is the JSON object written by [SummaryEncoder].
+type packageSummary struct {
+	Package string `json:"package"`
+	pkgdmp.PackageStats
+}
+
+// SummaryEncoder writes compact JSON summaries of packages to an output
+// stream.
+//
+// Each package is written on a separate line as an object with the package
+// name and the counts returned by [pkgdmp.Package.Stats], including the total
+// number of exported symbols. The summaries are suited for storing as CI
+// artifacts and comparing the size of the API surface across builds.
+type SummaryEncoder struct {
+	enc *json.Encoder
+}
+
+// NewSummaryEncoder returns a new encoder that writes to w.
+func NewSummaryEncoder(w io.Writer) *SummaryEncoder {
+	return &SummaryEncoder{enc: json.NewEncoder(w)}
+}
+
+// Encode writes a JSON summary of pkg.
+func (e *SummaryEncoder) Encode(pkg *pkgdmp.Package) error {
+	if err := e.enc.Encode(packageSummary{Package: pkg.Name, PackageStats: pkg.Stats()}); err != nil {
+		return fmt.Errorf("writing summary for %s package: %w", pkg.Name, err)
+	}
+
+	return nil
+}
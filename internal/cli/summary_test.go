@@ -0,0 +1,39 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+	"github.com/michenriksen/pkgdmp/internal/cli"
+)
+
+func TestSummaryEncoder(t *testing.T) {
+	pkgs := []*pkgdmp.Package{
+		{
+			Name:  "mypackage",
+			Funcs: []pkgdmp.Func{{Name: "New"}, {Name: "helper"}},
+		},
+		{
+			Name:   "otherpackage",
+			Consts: []pkgdmp.ConstGroup{{Consts: []pkgdmp.Const{{Names: []string{"A", "b"}}}}},
+		},
+	}
+
+	var b strings.Builder
+
+	enc := cli.NewSummaryEncoder(&b)
+
+	for _, pkg := range pkgs {
+		if err := enc.Encode(pkg); err != nil {
+			t.Fatalf("expected no error; got %v", err)
+		}
+	}
+
+	want := `{"package":"mypackage","consts":0,"vars":0,"types":0,"funcs":2,"methods":0,"exported":1}` + "\n" +
+		`{"package":"otherpackage","consts":2,"vars":0,"types":0,"funcs":0,"methods":0,"exported":1}` + "\n"
+
+	if b.String() != want {
+		t.Errorf("expected output:\n\n%s\nbut got:\n\n%s", want, b.String())
+	}
+}
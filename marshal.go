@@ -0,0 +1,310 @@
+package pkgdmp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// encodedPackages is the JSON representation of packages encoded with
+// [Parser.MarshalPackages].
+type encodedPackages struct {
+	Packages []*Package       `json:"packages"`
+	State    [][]encodedState `json:"state"`
+}
+
+// encodedState is the JSON representation of the unexported state of an
+// entity, in the order visited by [walkState].
+type encodedState struct {
+	RawDoc      string            `json:"rawDoc,omitempty"`
+	Spec        string            `json:"spec,omitempty"`
+	EnumType    string            `json:"enumType,omitempty"`
+	EnumValue   string            `json:"enumValue,omitempty"`
+	EnumDoc     string            `json:"enumDoc,omitempty"`
+	SrcType     string            `json:"srcType,omitempty"`
+	SourceTypes map[string]string `json:"sourceTypes,omitempty"`
+	Filterable  bool              `json:"filterable,omitempty"`
+}
+
+// stateRef references the unexported state of an entity. Fields the entity
+// does not have are nil.
+type stateRef struct {
+	rawDoc      *string
+	spec        *string
+	enumType    *string
+	enumValue   *string
+	enumDoc     *string
+	srcType     *string
+	sourceTypes *map[string]string
+	filterable  *bool
+}
+
+// MarshalPackages encodes packages as JSON including the state not part of
+// their JSON representation, such as full doc comments and enum values, so
+// they are restored exactly by [Parser.UnmarshalPackages].
+//
+// The encoding is meant for caching parsed packages and is not a stable
+// format; use [encoding/json] to encode packages for other programs.
+func (p *Parser) MarshalPackages(pkgs []*Package) ([]byte, error) {
+	enc := encodedPackages{
+		Packages: pkgs,
+		State:    make([][]encodedState, len(pkgs)),
+	}
+
+	for i, pkg := range pkgs {
+		walkState(pkg, func(ref stateRef) {
+			enc.State[i] = append(enc.State[i], ref.encode())
+		})
+	}
+
+	data, err := json.Marshal(enc)
+	if err != nil {
+		return nil, fmt.Errorf("encoding packages: %w", err)
+	}
+
+	return data, nil
+}
+
+// UnmarshalPackages parses JSON-encoded packages, either as encoded from
+// packages returned by [Parser.Package] or by [Parser.MarshalPackages], and
+// restores the state needed to render them as code according to the parser's
+// configuration.
+//
+// State not part of the JSON representation of packages is only restored
+// exactly for packages encoded by [Parser.MarshalPackages]. For others, full
+// doc comments are not available, specs of consts are rebuilt from their
+// names and values, specs of vars from their names and types, and types
+// qualified by [WithQualifiedImports] are rendered with their import paths.
+func (p *Parser) UnmarshalPackages(data []byte) ([]*Package, error) {
+	var enc encodedPackages
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &enc.Packages); err != nil {
+			return nil, fmt.Errorf("decoding packages: %w", err)
+		}
+	} else {
+		if err := json.Unmarshal(data, &enc); err != nil {
+			return nil, fmt.Errorf("decoding packages: %w", err)
+		}
+
+		if len(enc.State) != len(enc.Packages) {
+			return nil, fmt.Errorf("decoding packages: state for %d packages, but got %d packages",
+				len(enc.State), len(enc.Packages),
+			)
+		}
+	}
+
+	for i, pkg := range enc.Packages {
+		pkg.printCfg = p.printConfig()
+		restorePackage(pkg)
+
+		if enc.State == nil {
+			rebuildSpecs(pkg)
+			continue
+		}
+
+		if err := restoreState(pkg, enc.State[i]); err != nil {
+			return nil, fmt.Errorf("decoding %s package: %w", pkg.Name, err)
+		}
+	}
+
+	return enc.Packages, nil
+}
+
+// restoreState restores the unexported state of the entities of pkg from
+// states, in the order visited by [walkState].
+func restoreState(pkg *Package, states []encodedState) error {
+	var n int
+
+	walkState(pkg, func(ref stateRef) {
+		if n < len(states) {
+			ref.decode(states[n])
+		}
+
+		n++
+	})
+
+	if n != len(states) {
+		return fmt.Errorf("state for %d entities, but got %d entities", len(states), n)
+	}
+
+	return nil
+}
+
+// rebuildSpecs sets the specs of consts of pkg from their names and values,
+// and of vars from their names and types, as their values are not encoded.
+// Vars without a type are given type any, marked with a line comment.
+func rebuildSpecs(pkg *Package) {
+	for i := range pkg.Consts {
+		for j := range pkg.Consts[i].Consts {
+			c := &pkg.Consts[i].Consts[j]
+			if c.Spec != "" {
+				continue
+			}
+
+			c.Spec = strings.Join(c.Names, ", ")
+
+			if len(c.Values) == 0 {
+				continue
+			}
+
+			if c.Values[0].Specific {
+				c.Spec += " " + c.Values[0].Type
+			}
+
+			vals := make([]string, len(c.Values))
+			for k, v := range c.Values {
+				vals[k] = v.Value
+			}
+
+			c.Spec += " = " + strings.Join(vals, ", ")
+		}
+	}
+
+	for i := range pkg.Vars {
+		for j := range pkg.Vars[i].Vars {
+			v := &pkg.Vars[i].Vars[j]
+			if v.Spec != "" {
+				continue
+			}
+
+			// The types of vars declared without one are not encoded.
+			if v.Type == "" {
+				v.Spec = strings.Join(v.Names, ", ") + " any // type unknown."
+				continue
+			}
+
+			v.Spec = strings.Join(v.Names, ", ") + " " + v.Type
+		}
+	}
+}
+
+// walkState calls fn with a reference to the unexported state of each entity
+// in pkg that has any, in a stable order.
+func walkState(pkg *Package, fn func(stateRef)) {
+	for i := range pkg.Consts {
+		cg := &pkg.Consts[i]
+
+		fn(stateRef{enumType: &cg.enumType})
+
+		for j := range cg.Consts {
+			c := &cg.Consts[j]
+
+			fn(stateRef{rawDoc: &c.rawDoc, spec: &c.Spec, enumValue: &c.enumValue, enumDoc: &c.enumDoc})
+		}
+	}
+
+	for i := range pkg.Vars {
+		for j := range pkg.Vars[i].Vars {
+			v := &pkg.Vars[i].Vars[j]
+
+			fn(stateRef{rawDoc: &v.rawDoc, spec: &v.Spec})
+		}
+	}
+
+	for i := range pkg.Funcs {
+		walkFuncState(&pkg.Funcs[i], fn)
+	}
+
+	for i := range pkg.Types {
+		td := &pkg.Types[i]
+
+		fn(stateRef{rawDoc: &td.rawDoc, sourceTypes: &td.sourceTypes})
+
+		walkFieldsState(td.TypeParams, fn)
+		walkFieldsState(td.Params, fn)
+		walkFieldsState(td.Results, fn)
+		walkFieldsState(td.Fields, fn)
+
+		for j := range td.Methods {
+			walkFuncState(&td.Methods[j], fn)
+		}
+	}
+}
+
+func walkFuncState(f *Func, fn func(stateRef)) {
+	fn(stateRef{rawDoc: &f.rawDoc})
+
+	if f.Receiver != nil {
+		walkFieldState(f.Receiver, fn)
+	}
+
+	walkFieldsState(f.TypeParams, fn)
+	walkFieldsState(f.Params, fn)
+	walkFieldsState(f.Results, fn)
+}
+
+func walkFieldsState(fl []Field, fn func(stateRef)) {
+	for i := range fl {
+		walkFieldState(&fl[i], fn)
+	}
+}
+
+func walkFieldState(f *Field, fn func(stateRef)) {
+	fn(stateRef{srcType: &f.srcType, filterable: &f.filterable})
+
+	walkFieldsState(f.Fields, fn)
+
+	for i := range f.Methods {
+		walkFuncState(&f.Methods[i], fn)
+	}
+}
+
+// encode returns the state referenced by ref.
+func (ref stateRef) encode() encodedState {
+	var s encodedState
+
+	for _, f := range []struct {
+		dst *string
+		src *string
+	}{
+		{&s.RawDoc, ref.rawDoc},
+		{&s.Spec, ref.spec},
+		{&s.EnumType, ref.enumType},
+		{&s.EnumValue, ref.enumValue},
+		{&s.EnumDoc, ref.enumDoc},
+		{&s.SrcType, ref.srcType},
+	} {
+		if f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+
+	if ref.sourceTypes != nil {
+		s.SourceTypes = *ref.sourceTypes
+	}
+
+	if ref.filterable != nil {
+		s.Filterable = *ref.filterable
+	}
+
+	return s
+}
+
+// decode sets the state referenced by ref to s.
+func (ref stateRef) decode(s encodedState) {
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{ref.rawDoc, s.RawDoc},
+		{ref.spec, s.Spec},
+		{ref.enumType, s.EnumType},
+		{ref.enumValue, s.EnumValue},
+		{ref.enumDoc, s.EnumDoc},
+		{ref.srcType, s.SrcType},
+	} {
+		if f.dst != nil {
+			*f.dst = f.src
+		}
+	}
+
+	if ref.sourceTypes != nil {
+		*ref.sourceTypes = s.SourceTypes
+	}
+
+	if ref.filterable != nil {
+		*ref.filterable = s.Filterable
+	}
+}
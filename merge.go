@@ -0,0 +1,247 @@
+package pkgdmp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrConflict is returned by [MergePackages] if packages contain different
+// definitions of the same symbol.
+var ErrConflict = errors.New("conflicting symbol definitions")
+
+// MergePackages merges the consts, vars, types, and functions of packages with the
+// same name into a single package.
+//
+// Symbols defined identically in more than one package are only included
+// once, while different definitions of the same symbol result in an error
+// wrapping [ErrConflict]. Methods declared on a type defined in another
+// package are moved to the type definition.
+//
+// The merged package uses the first non-empty package doc comment and renders
+// according to the configuration of the first package.
+func MergePackages(pkgs ...*Package) (*Package, error) {
+	if len(pkgs) == 0 {
+		return nil, errors.New("no packages to merge")
+	}
+
+	merged := &Package{Name: pkgs[0].Name, printCfg: pkgs[0].printCfg}
+
+	var (
+		imports []string
+		consts  = make(map[string]Const)
+		vars    = make(map[string]Var)
+		types   = make(map[string]int)
+		funcs   = make(map[string]Func)
+		methods []Func
+	)
+
+	for _, pkg := range pkgs {
+		if pkg.Name != merged.Name {
+			return nil, fmt.Errorf("cannot merge package %s with package %s", pkg.Name, merged.Name)
+		}
+
+		if merged.Doc == "" {
+			merged.Doc = pkg.Doc
+		}
+
+		imports = append(imports, pkg.Imports...)
+
+		for _, cg := range pkg.Consts {
+			mcg, err := mergeConstGroup(consts, cg)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(mcg.Consts) != 0 {
+				merged.Consts = append(merged.Consts, mcg)
+			}
+		}
+
+		for _, vg := range pkg.Vars {
+			mvg, err := mergeVarGroup(vars, vg)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(mvg.Vars) != 0 {
+				merged.Vars = append(merged.Vars, mvg)
+			}
+		}
+
+		for _, td := range pkg.Types {
+			i, ok := types[td.Name]
+			if !ok {
+				types[td.Name] = len(merged.Types)
+				merged.Types = append(merged.Types, td)
+
+				continue
+			}
+
+			if err := mergeTypeDef(&merged.Types[i], td); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, f := range pkg.Funcs {
+			if f.Receiver != nil {
+				methods = append(methods, f)
+				continue
+			}
+
+			if err := mergeFunc(funcs, &merged.Funcs, f); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, m := range methods {
+		i, ok := types[m.ReceiverType()]
+		if !ok {
+			if err := mergeFunc(funcs, &merged.Funcs, m); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		td := &merged.Types[i]
+		seen := make(map[string]Func, len(td.Methods))
+
+		for _, tm := range td.Methods {
+			seen[funcKey(tm)] = tm
+		}
+
+		if err := mergeFunc(seen, &td.Methods, m); err != nil {
+			return nil, err
+		}
+	}
+
+	merged.Imports = uniqueSorted(imports)
+
+	return merged, nil
+}
+
+// mergeConstGroup returns cg without consts already in seen, or an error if
+// a const in cg conflicts with a const in seen.
+func mergeConstGroup(seen map[string]Const, cg ConstGroup) (ConstGroup, error) {
+	res := ConstGroup{Doc: cg.Doc, enumType: cg.enumType}
+
+	for _, c := range cg.Consts {
+		dup := false
+
+		for _, name := range c.Names {
+			prev, ok := seen[name]
+			if !ok {
+				continue
+			}
+
+			if !reflect.DeepEqual(prev, c) {
+				return ConstGroup{}, fmt.Errorf("const %s: %w", name, ErrConflict)
+			}
+
+			dup = true
+		}
+
+		if dup {
+			continue
+		}
+
+		for _, name := range c.Names {
+			seen[name] = c
+		}
+
+		res.Consts = append(res.Consts, c)
+	}
+
+	return res, nil
+}
+
+// mergeVarGroup returns vg without vars already in seen, or an error if a var
+// in vg conflicts with a var in seen.
+func mergeVarGroup(seen map[string]Var, vg VarGroup) (VarGroup, error) {
+	res := VarGroup{Doc: vg.Doc}
+
+	for _, v := range vg.Vars {
+		dup := false
+
+		for _, name := range v.Names {
+			prev, ok := seen[name]
+			if !ok {
+				continue
+			}
+
+			if !reflect.DeepEqual(prev, v) {
+				return VarGroup{}, fmt.Errorf("var %s: %w", name, ErrConflict)
+			}
+
+			dup = true
+		}
+
+		if dup {
+			continue
+		}
+
+		for _, name := range v.Names {
+			seen[name] = v
+		}
+
+		res.Vars = append(res.Vars, v)
+	}
+
+	return res, nil
+}
+
+// mergeTypeDef merges methods of td into dst, or returns an error if the
+// type definitions differ.
+func mergeTypeDef(dst *TypeDef, td TypeDef) error {
+	a, b := *dst, td
+	a.Methods, b.Methods = nil, nil
+
+	if !reflect.DeepEqual(a, b) {
+		return fmt.Errorf("type %s: %w", td.Name, ErrConflict)
+	}
+
+	seen := make(map[string]Func, len(dst.Methods))
+
+	for _, m := range dst.Methods {
+		seen[funcKey(m)] = m
+	}
+
+	for _, m := range td.Methods {
+		if err := mergeFunc(seen, &dst.Methods, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeFunc appends f to dst unless an identical function is in seen, or
+// returns an error if a different function with the same name is in seen.
+func mergeFunc(seen map[string]Func, dst *[]Func, f Func) error {
+	key := funcKey(f)
+
+	prev, ok := seen[key]
+	if !ok {
+		seen[key] = f
+		*dst = append(*dst, f)
+
+		return nil
+	}
+
+	if !reflect.DeepEqual(prev, f) {
+		return fmt.Errorf("func %s: %w", key, ErrConflict)
+	}
+
+	return nil
+}
+
+// funcKey returns the name of f qualified with its receiver type, if any.
+func funcKey(f Func) string {
+	if rt := f.ReceiverType(); rt != "" {
+		return rt + "." + f.Name
+	}
+
+	return f.Name
+}
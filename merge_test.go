@@ -0,0 +1,183 @@
+package pkgdmp_test
+
+import (
+	"errors"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+func TestMergePackages(t *testing.T) {
+	handwritten := parseSource(t, `// Package mypackage is an example package.
+package mypackage
+
+import "fmt"
+
+// MyConst is an example const.
+const MyConst = 1
+
+// MyStruct is an example struct.
+type MyStruct struct {
+	Name string
+}
+
+// String returns the name.
+func (s MyStruct) String() string { return fmt.Sprint(s.Name) }
+
+// MyFunc is an example function.
+func MyFunc() {}
+`)
+
+	generated := parseSource(t, `package mypackage
+
+import "errors"
+
+// MyConst is an example const.
+const MyConst = 1
+
+// MyGeneratedConst is a generated const.
+const MyGeneratedConst = "generated"
+
+// MyStruct is an example struct.
+type MyStruct struct {
+	Name string
+}
+
+// Validate validates the struct.
+func (s MyStruct) Validate() error { return errors.New("invalid") }
+
+// MyFunc is an example function.
+func MyFunc() {}
+
+// MyGeneratedFunc is a generated function.
+func MyGeneratedFunc() {}
+`)
+
+	merged, err := pkgdmp.MergePackages(handwritten, generated)
+	if err != nil {
+		t.Fatalf("expected no error when merging packages, but got: %v", err)
+	}
+
+	want := `// Package mypackage is an example package.
+package mypackage
+
+// MyConst is an example const.
+const MyConst = 1
+
+// MyGeneratedConst is a generated const.
+const MyGeneratedConst = "generated"
+
+// MyStruct is an example struct.
+type MyStruct struct {
+	Name string
+}
+
+// String returns the name.
+func (s MyStruct) String() string
+
+// Validate validates the struct.
+func (s MyStruct) Validate() error
+
+// MyFunc is an example function.
+func MyFunc()
+
+// MyGeneratedFunc is a generated function.
+func MyGeneratedFunc()
+`
+
+	actual, err := merged.Source()
+	if err != nil {
+		t.Fatalf("expected no error when getting merged source, but got: %v", err)
+	}
+
+	if actual != want {
+		t.Errorf("expected merged source:\n\n%s\nbut got:\n\n%s", want, actual)
+	}
+}
+
+func TestMergePackages_Conflicts(t *testing.T) {
+	base := parseSource(t, `package mypackage
+
+const MyConst = 1
+
+type MyStruct struct{}
+
+func (MyStruct) Method() {}
+
+func MyFunc() {}
+`)
+
+	tt := []struct {
+		name string
+		src  string
+	}{
+		{"const", "package mypackage\n\nconst MyConst = 2\n"},
+		{"type", "package mypackage\n\ntype MyStruct struct{ Name string }\n"},
+		{"method", "package mypackage\n\ntype MyStruct struct{}\n\nfunc (MyStruct) Method() error { return nil }\n"},
+		{"func", "package mypackage\n\nfunc MyFunc(n int) {}\n"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := pkgdmp.MergePackages(base, parseSource(t, tc.src))
+			if !errors.Is(err, pkgdmp.ErrConflict) {
+				t.Errorf("expected error wrapping %v, but got: %v", pkgdmp.ErrConflict, err)
+			}
+		})
+	}
+}
+
+func TestMergePackages_Imports(t *testing.T) {
+	a := &pkgdmp.Package{Name: "mypackage", Imports: []string{"fmt", "errors"}}
+	b := &pkgdmp.Package{Name: "mypackage", Imports: []string{"io", "fmt"}}
+
+	merged, err := pkgdmp.MergePackages(a, b)
+	if err != nil {
+		t.Fatalf("expected no error when merging packages, but got: %v", err)
+	}
+
+	if got := strings.Join(merged.Imports, ","); got != "errors,fmt,io" {
+		t.Errorf("expected merged imports errors,fmt,io, but got: %s", got)
+	}
+}
+
+func TestMergePackages_DifferentNames(t *testing.T) {
+	a := parseSource(t, "package a\n")
+	b := parseSource(t, "package b\n")
+
+	if _, err := pkgdmp.MergePackages(a, b); err == nil {
+		t.Error("expected error when merging packages with different names, but got nil")
+	}
+}
+
+// parseSource parses src as a single file package with default parser
+// options.
+func parseSource(tb testing.TB, src string) *pkgdmp.Package {
+	tb.Helper()
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "file.go", src, parser.ParseComments)
+	if err != nil {
+		tb.Fatalf("error parsing source: %v", err)
+	}
+
+	//nolint:staticcheck // ast.Package is required by doc.New.
+	aPkg := &ast.Package{Name: file.Name.Name, Files: map[string]*ast.File{"file.go": file}}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(doc.New(aPkg, "", doc.AllDecls))
+	if err != nil {
+		tb.Fatalf("error parsing package: %v", err)
+	}
+
+	return pkg
+}
@@ -0,0 +1,92 @@
+package pkgdmp
+
+import (
+	"sort"
+	"strings"
+)
+
+// MethodSetDiff compares the methods of the types named a and b and returns
+// the sorted names of methods only declared on a, only declared on b, and
+// declared on both.
+//
+// Methods are compared by name and normalized signature, ignoring receivers,
+// parameter and result names, and whitespace, so a method with the same name
+// but a different signature on each type is returned in both onlyA and onlyB.
+// Methods are those declared with the type as receiver, or in the type's
+// declaration for interface types. A type not declared in the package has no
+// methods.
+func (p *Package) MethodSetDiff(a, b string) (onlyA, onlyB, common []string) {
+	methodsA, methodsB := p.methodSet(a), p.methodSet(b)
+
+	for name, sig := range methodsA {
+		bSig, ok := methodsB[name]
+
+		switch {
+		case !ok:
+			onlyA = append(onlyA, name)
+		case sig == bSig:
+			common = append(common, name)
+		default:
+			onlyA = append(onlyA, name)
+			onlyB = append(onlyB, name)
+		}
+	}
+
+	for name := range methodsB {
+		if _, ok := methodsA[name]; !ok {
+			onlyB = append(onlyB, name)
+		}
+	}
+
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+	sort.Strings(common)
+
+	return onlyA, onlyB, common
+}
+
+// methodSet returns the normalized signatures of the methods of the type
+// named typ, keyed by method name.
+func (p *Package) methodSet(typ string) map[string]string {
+	res := make(map[string]string)
+
+	for _, td := range p.Types {
+		if td.Name != typ {
+			continue
+		}
+
+		for _, m := range td.Methods {
+			res[m.Name] = methodSignature(m)
+		}
+	}
+
+	for _, f := range p.Funcs {
+		if f.ReceiverType() == typ {
+			res[f.Name] = methodSignature(f)
+		}
+	}
+
+	return res
+}
+
+// methodSignature returns the signature of method f without its name,
+// receiver, and parameter and result names, with whitespace normalized.
+func methodSignature(f Func) string {
+	return "(" + signatureTypes(f.Params) + ") (" + signatureTypes(f.Results) + ")"
+}
+
+// signatureTypes returns the comma separated types of parameter or result
+// fields, with a type repeated for each name sharing it.
+func signatureTypes(fields []Field) string {
+	var typs []string
+
+	for _, f := range fields {
+		typ := strings.Join(strings.Fields(f.Type), " ")
+
+		for i := 0; i < max(len(f.Names), 1); i++ {
+			typs = append(typs, typ)
+		}
+	}
+
+	return strings.Join(typs, ", ")
+}
@@ -0,0 +1,67 @@
+package pkgdmp_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+func TestPackage_MethodSetDiff(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "method_sets.go"}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		a, b       string
+		wantOnlyA  []string
+		wantOnlyB  []string
+		wantCommon []string
+	}{
+		{
+			name:       "structs",
+			a:          "MyFileStore",
+			b:          "MyMemStore",
+			wantOnlyA:  []string{"Close", "Delete", "Sync"},
+			wantOnlyB:  []string{"Delete", "Snapshot"},
+			wantCommon: []string{"Get", "Put"},
+		},
+		{
+			name:       "struct and interface",
+			a:          "MyFileStore",
+			b:          "MyCloser",
+			wantOnlyA:  []string{"Delete", "Get", "Put", "Sync"},
+			wantCommon: []string{"Close"},
+		},
+		{
+			name:      "unknown type",
+			a:         "MyMemStore",
+			b:         "MyNonExistentType",
+			wantOnlyA: []string{"Delete", "Get", "Put", "Snapshot"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			onlyA, onlyB, common := pkg.MethodSetDiff(tt.a, tt.b)
+
+			if !reflect.DeepEqual(onlyA, tt.wantOnlyA) {
+				t.Errorf("expected methods only on %s %v, but got %v", tt.a, tt.wantOnlyA, onlyA)
+			}
+
+			if !reflect.DeepEqual(onlyB, tt.wantOnlyB) {
+				t.Errorf("expected methods only on %s %v, but got %v", tt.b, tt.wantOnlyB, onlyB)
+			}
+
+			if !reflect.DeepEqual(common, tt.wantCommon) {
+				t.Errorf("expected common methods %v, but got %v", tt.wantCommon, common)
+			}
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package pkgdmp
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/doc"
+	"go/printer"
 	"go/token"
+	"go/types"
+	"strconv"
 	"strings"
 )
 
@@ -16,6 +20,18 @@ var typeNames = map[token.Token]string{
 	token.STRING: "string",
 }
 
+// UnsupportedError reports a part of a declaration that the parser does not
+// support and skipped, e.g. an embedded interface element.
+type UnsupportedError struct {
+	Node   string    // Type of the unsupported AST node, e.g. `*ast.StarExpr`.
+	Symbol string    // Name of the symbol containing the node.
+	Pos    token.Pos // Position of the node.
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s: unsupported %s", e.Symbol, e.Node)
+}
+
 // ParserOption configures a [Parser].
 type ParserOption interface {
 	// String should return a string representation of the option.
@@ -28,15 +44,48 @@ type ParserOption interface {
 
 // Parser parses go packages to simple structs.
 type Parser struct {
-	filters  []SymbolFilter
-	fullDocs bool
-	noDocs   bool
-	noTags   bool
+	filters     []SymbolFilter
+	results     []FilterResult
+	warnings    []error
+	fullDocs    bool
+	fullPkgDoc  bool
+	noDocs      bool
+	noComments  bool
+	noTags      bool
+	noMethods   bool
+	plainDocs   bool
+	grouped     bool
+	imports     bool
+	promote     bool
+	expandIface bool
+	flatConsts  bool
+	noRecvNames bool
+	noRecvs     bool
+	record      bool
+	filterAll   bool
+	wrap        int
+	maxMethods  int
+	wrapSigs    int
+	zeroValues  bool
+	underlying  bool
+	noEmpty     bool
+	compact     bool
+	groupCtors  bool
+	stripTypes  bool
+	keepDirs    bool
+	docSections bool
+	methodDocs  bool
+	sortMethods bool
+	qualifyPkgs bool
+	qualifiers  []fileQualifiers
+	exampleSet  *token.FileSet
+	keepTags    map[string]struct{}
+	dropTags    map[string]struct{}
 }
 
 // NewParser returns a parser configured with options.
 func NewParser(opts ...ParserOption) (*Parser, error) {
-	p := &Parser{}
+	p := &Parser{wrap: defaultPrintConfig.wrap}
 
 	for _, opt := range opts {
 		if err := opt.apply(p); err != nil {
@@ -48,16 +97,45 @@ func NewParser(opts ...ParserOption) (*Parser, error) {
 }
 
 // Package parses dPkg to a simplified [Package].
-func (p *Parser) Package(dPkg *doc.Package) (*Package, error) {
+//
+// Files are the syntax trees of dPkg's source files, as parsed by
+// [go/parser]. They are only used to resolve package qualifiers with the
+// imports of each file if configured with [WithQualifiedImports], and the
+// imports of dPkg are used if none are given.
+func (p *Parser) Package(dPkg *doc.Package, files ...*ast.File) (*Package, error) {
+	return p.parsePackage(dPkg, nil, files)
+}
+
+// parsePackage parses dPkg to a simplified [Package], with package qualifiers
+// resolved with the declared names of the imports of tPkg if it is not nil.
+func (p *Parser) parsePackage(dPkg *doc.Package, tPkg *types.Package, files []*ast.File) (*Package, error) {
+	p.results = nil
+	p.warnings = nil
+	p.setQualifiers(dPkg, tPkg, files)
+
 	pkg := &Package{
-		Name: dPkg.Name,
-		Doc:  p.mkDoc(dPkg.Doc),
+		Name:     dPkg.Name,
+		Doc:      p.formatDoc(dPkg.Doc, p.fullDocs || p.fullPkgDoc),
+		Examples: p.parseExamples(dPkg.Examples),
+		printCfg: p.printConfig(),
+	}
+
+	if p.stripTypes {
+		pkg.printCfg.internalTypes = unexportedTypeNames(dPkg.Types)
+	}
+
+	if p.imports {
+		pkg.Imports = withoutCgoImport(uniqueSorted(dPkg.Imports))
 	}
 
 	if err := p.parseConsts(pkg, dPkg.Consts); err != nil {
 		return nil, fmt.Errorf("parsing constants: %w", err)
 	}
 
+	if err := p.parseVars(pkg, dPkg.Vars); err != nil {
+		return nil, fmt.Errorf("parsing variables: %w", err)
+	}
+
 	if err := p.parseTypes(pkg, dPkg.Types); err != nil {
 		return nil, fmt.Errorf("parsing types: %w", err)
 	}
@@ -66,12 +144,336 @@ func (p *Parser) Package(dPkg *doc.Package) (*Package, error) {
 		return nil, fmt.Errorf("parsing functions: %w", err)
 	}
 
+	qualifyPackage(pkg)
+
+	return pkg, nil
+}
+
+// TypedPackage parses dPkg to a simplified [Package] like [Parser.Package],
+// enriched with type information from tPkg, the type-checked package of
+// dPkg.
+//
+// Package qualifiers of imports without a name are resolved with the
+// declared names of the imported packages, and if configured with
+// [WithResolveUnderlying], the Underlying field of identifier type definitions
+// referring to other types is set to their resolved underlying type.
+func (p *Parser) TypedPackage(dPkg *doc.Package, tPkg *types.Package, files ...*ast.File) (*Package, error) {
+	pkg, err := p.parsePackage(dPkg, tPkg, files)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.underlying {
+		return pkg, nil
+	}
+
+	qual := types.RelativeTo(tPkg)
+
+	for i := range pkg.Types {
+		td := &pkg.Types[i]
+
+		if td.SymbolType() != SymbolIdentType {
+			continue
+		}
+
+		obj, ok := tPkg.Scope().Lookup(td.Name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+
+		if u := types.TypeString(obj.Type().Underlying(), qual); u != td.Type {
+			td.Underlying = u
+		}
+	}
+
 	return pkg, nil
 }
 
+// FilterResults returns the results of applying the parser's symbol filters
+// to symbols during the most recent call to [Parser.Package].
+//
+// Results are only recorded if the parser is configured with
+// [WithFilterResults]. Symbols nested in excluded symbols, such as methods of
+// an excluded type, are not filtered and have no results.
+func (p *Parser) FilterResults() []FilterResult {
+	return p.results
+}
+
+// Warnings returns errors for unsupported declarations skipped during the
+// most recent call to [Parser.Package], each an [*UnsupportedError].
+func (p *Parser) Warnings() []error {
+	return p.warnings
+}
+
+// warn records node of symbol as unsupported.
+func (p *Parser) warn(symbol string, node ast.Node) {
+	p.warnings = append(p.warnings, &UnsupportedError{
+		Node:   fmt.Sprintf("%T", node),
+		Symbol: symbol,
+		Pos:    node.Pos(),
+	})
+}
+
+func (p *Parser) printConfig() *printConfig {
+	return &printConfig{
+		wrap:        p.wrap,
+		groupByKind: p.grouped,
+		noRecvNames: p.noRecvNames,
+		noRecvs:     p.noRecvs,
+		flatConsts:  p.flatConsts,
+		maxMethods:  p.maxMethods,
+		wrapSigs:    p.wrapSigs,
+		zeroValues:  p.zeroValues,
+		underlying:  p.underlying,
+		noEmpty:     p.noEmpty,
+		compact:     p.compact,
+		groupCtors:  p.groupCtors,
+		methodDocs:  p.methodDocs,
+		sortMethods: p.sortMethods,
+	}
+}
+
+// qualifyPackage sets the qualified names of the package's type definitions,
+// functions, and methods, e.g. `mypkg.MyStruct.MyMethod`.
+func qualifyPackage(pkg *Package) {
+	for i := range pkg.Types {
+		td := &pkg.Types[i]
+		td.Qualified = pkg.Name + "." + td.Name
+
+		for j := range td.Methods {
+			td.Methods[j].Qualified = td.Qualified + "." + td.Methods[j].Name
+		}
+	}
+
+	for i := range pkg.Funcs {
+		f := &pkg.Funcs[i]
+		f.Qualified = pkg.Name + "." + f.Name
+
+		if rt := f.ReceiverType(); rt != "" {
+			f.Qualified = pkg.Name + "." + rt + "." + f.Name
+		}
+	}
+}
+
+// fileQualifiers maps the package qualifiers used in the source file
+// spanning positions start to end to the import paths of the packages they
+// refer to. Qualifiers with a zero start and end apply to all files.
+type fileQualifiers struct {
+	start, end token.Pos
+	paths      map[string]string
+}
+
+// setQualifiers sets the package qualifiers used to qualify the types of
+// dPkg's entities if configured with [WithQualifiedImports].
+//
+// Qualifiers are resolved from the imports of each of files, as the name of
+// an import if it has one, and otherwise as the declared name of the imported
+// package if tPkg is not nil, or its assumed name as given by [importName].
+// If files are not given, they are resolved from the imports of dPkg, and
+// assumed names shared by more than one import are left unresolved.
+func (p *Parser) setQualifiers(dPkg *doc.Package, tPkg *types.Package, files []*ast.File) {
+	p.qualifiers = nil
+
+	if !p.qualifyPkgs {
+		return
+	}
+
+	declared := make(map[string]string)
+
+	if tPkg != nil {
+		for _, imp := range tPkg.Imports() {
+			declared[imp.Path()] = imp.Name()
+		}
+	}
+
+	pkgName := func(path string) string {
+		if name, ok := declared[path]; ok {
+			return name
+		}
+
+		return importName(path)
+	}
+
+	if len(files) == 0 {
+		p.qualifiers = []fileQualifiers{{paths: importPaths(dPkg.Imports, pkgName)}}
+		return
+	}
+
+	for _, f := range files {
+		paths := make(map[string]string, len(f.Imports))
+
+		for _, spec := range f.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+
+			name := pkgName(path)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+
+			// Blank and dot imports have no qualifier.
+			if name != "_" && token.IsIdentifier(name) {
+				paths[name] = path
+			}
+		}
+
+		p.qualifiers = append(p.qualifiers, fileQualifiers{start: f.FileStart, end: f.FileEnd, paths: paths})
+	}
+}
+
+// importPaths returns the import paths of imports keyed by their package
+// names as given by pkgName, without names shared by more than one import.
+func importPaths(imports []string, pkgName func(string) string) map[string]string {
+	paths := make(map[string]string, len(imports))
+	ambiguous := make(map[string]struct{})
+
+	for _, path := range imports {
+		name := pkgName(path)
+		if !token.IsIdentifier(name) {
+			continue
+		}
+
+		if prev, ok := paths[name]; ok && prev != path {
+			ambiguous[name] = struct{}{}
+		}
+
+		paths[name] = path
+	}
+
+	for name := range ambiguous {
+		delete(paths, name)
+	}
+
+	return paths
+}
+
+// qualifyType returns typ, the type string of the node at pos, with package
+// qualifiers replaced by the import paths of the packages they refer to in
+// the node's source file, e.g. `b.Thing` with `github.com/x/b.Thing`, if
+// configured with [WithQualifiedImports].
+func (p *Parser) qualifyType(pos token.Pos, typ string) string {
+	for _, fq := range p.qualifiers {
+		if fq.start.IsValid() && (pos < fq.start || pos > fq.end) {
+			continue
+		}
+
+		return qualifyTypeString(typ, fq.paths)
+	}
+
+	return typ
+}
+
+// qualifyTypeDef qualifies the types of td declared by the type spec at pos,
+// and records the source forms of the ones qualified so td renders as written
+// in the source.
+func (p *Parser) qualifyTypeDef(pos token.Pos, td *TypeDef) {
+	for _, typ := range []*string{&td.Type, &td.Key, &td.Value, &td.Elt} {
+		src := *typ
+		if *typ = p.qualifyType(pos, src); *typ == src {
+			continue
+		}
+
+		if td.sourceTypes == nil {
+			td.sourceTypes = make(map[string]string)
+		}
+
+		td.sourceTypes[*typ] = src
+	}
+}
+
+// sourceSymbol returns s with the types of fields and function parameters and
+// results as written in the source, so symbol filters match the types of
+// symbols regardless of [WithQualifiedImports].
+func sourceSymbol(s Symbol) Symbol {
+	switch st := s.(type) {
+	case Field:
+		st.Type = st.sourceType()
+		return st
+	case Func:
+		st.TypeParams = sourceFields(st.TypeParams)
+		st.Params = sourceFields(st.Params)
+		st.Results = sourceFields(st.Results)
+
+		return st
+	default:
+		return s
+	}
+}
+
+func sourceFields(fl []Field) []Field {
+	if fl == nil {
+		return nil
+	}
+
+	res := make([]Field, len(fl))
+
+	for i, f := range fl {
+		f.Type = f.sourceType()
+		res[i] = f
+	}
+
+	return res
+}
+
+// restorePackage restores unexported symbol state of a decoded package that
+// can be derived from the position of symbols in the package.
+func restorePackage(pkg *Package) {
+	for i := range pkg.Funcs {
+		restoreFunc(&pkg.Funcs[i], SymbolFunc)
+	}
+
+	for i := range pkg.Types {
+		td := &pkg.Types[i]
+
+		restoreFields(td.TypeParams, SymbolTypeParamField)
+		restoreFields(td.Fields, SymbolStructField)
+		restoreFields(td.Params, SymbolParamField)
+		restoreFields(td.Results, SymbolResultField)
+
+		for j := range td.Methods {
+			restoreFunc(&td.Methods[j], SymbolMethod)
+		}
+	}
+}
+
+func restoreFunc(f *Func, st SymbolType) {
+	f.symbolType = st
+
+	if f.Receiver != nil {
+		f.symbolType = SymbolMethod
+		restoreField(f.Receiver, SymbolReceiverField)
+	}
+
+	restoreFields(f.TypeParams, SymbolTypeParamField)
+	restoreFields(f.Params, SymbolParamField)
+	restoreFields(f.Results, SymbolResultField)
+}
+
+func restoreFields(fl []Field, st SymbolType) {
+	for i := range fl {
+		restoreField(&fl[i], st)
+	}
+}
+
+func restoreField(f *Field, st SymbolType) {
+	f.symbolType = st
+
+	restoreFields(f.Fields, SymbolStructField)
+
+	for i := range f.Methods {
+		restoreFunc(&f.Methods[i], SymbolMethod)
+	}
+}
+
 func (p *Parser) parseConsts(pkg *Package, cnsts []*doc.Value) error {
 	for _, dVal := range cnsts {
-		cg := p.parseConst(dVal)
+		cg, err := p.parseConst(dVal)
+		if err != nil {
+			return err
+		}
+
 		if len(cg.Consts) == 0 {
 			continue
 		}
@@ -82,19 +484,62 @@ func (p *Parser) parseConsts(pkg *Package, cnsts []*doc.Value) error {
 	return nil
 }
 
-func (p *Parser) parseConst(dVal *doc.Value) ConstGroup {
-	cg := ConstGroup{Doc: p.mkDoc(dVal.Doc)}
+func (p *Parser) parseConst(dVal *doc.Value) (ConstGroup, error) {
+	cg := ConstGroup{Doc: p.mkDoc(dVal.Doc), enumType: iotaEnumType(dVal.Decl)}
 
-	for _, s := range dVal.Decl.Specs {
+	// Type and value expressions of the last spec with values, repeated by
+	// specs with implicit values.
+	var (
+		typ   ast.Expr
+		exprs []ast.Expr
+	)
+
+	for i, s := range dVal.Decl.Specs {
 		vs, ok := s.(*ast.ValueSpec)
 		if !ok {
-			panic(fmt.Errorf("unsupported const spec type %T", s))
+			return ConstGroup{}, fmt.Errorf("unsupported const spec type %T", s)
+		}
+
+		if len(vs.Values) != 0 {
+			typ, exprs = vs.Type, vs.Values
+		}
+
+		// Line comments are part of the printed spec, so they are removed
+		// from a copy if excluded. Doc comments are printed from Doc.
+		spec := *vs
+		spec.Doc = nil
+
+		if p.noComments {
+			spec.Comment = nil
+		}
+
+		// The signature spec has no comments and spells out the implicit
+		// type and values of the spec.
+		sigSpec := spec
+		sigSpec.Comment = nil
+
+		if len(vs.Values) == 0 {
+			sigSpec.Type, sigSpec.Values = typ, exprs
 		}
 
 		c := Const{
-			Names:   identNames(vs.Names),
-			Values:  make([]Value, 0, len(vs.Values)),
-			valSpec: vs,
+			Doc:    p.mkDoc(vs.Doc.Text()),
+			Names:  identNames(vs.Names),
+			Values: make([]Value, 0, len(vs.Values)),
+			Spec:   printNodes(&spec),
+			sig:    printNodes(&sigSpec),
+			rawDoc: strings.TrimSpace(dVal.Doc + "\n" + vs.Doc.Text()),
+		}
+
+		if cg.enumType != "" {
+			if v, ok := evalConstExpr(exprs[0], i); ok {
+				c.enumValue = v.String()
+			}
+
+			c.enumDoc = p.mkDoc(vs.Doc.Text())
+			if c.enumDoc == "" {
+				c.enumDoc = p.mkLineComment(vs.Comment.Text())
+			}
 		}
 
 		if !p.includeSymbol(c) {
@@ -109,16 +554,14 @@ func (p *Parser) parseConst(dVal *doc.Value) ConstGroup {
 				val.Value = vt.Value
 				val.Type = typeNames[vt.Kind]
 			case *ast.CallExpr:
-				if lit, ok := vt.Args[0].(*ast.BasicLit); ok {
-					val.Value = lit.Value
-				}
-
-				val.Type = printNodes(vt.Fun)
-				val.Specific = true
-			case *ast.Ident:
-				val.Type = vt.Name
+				val = callExprValue(vt)
+			case *ast.Ident, *ast.BinaryExpr, *ast.UnaryExpr, *ast.ParenExpr:
+				val.Value = printNodes(vt)
+				val.Type = constExprType(vt)
 			default:
-				panic(fmt.Errorf("unsupported const value type %T", vt))
+				// Best-effort fallback for less common value expressions such
+				// as selectors (`os.ModePerm`) and index expressions.
+				val.Value = printNodes(vt)
 			}
 
 			if vs.Type != nil {
@@ -126,13 +569,84 @@ func (p *Parser) parseConst(dVal *doc.Value) ConstGroup {
 				val.Specific = true
 			}
 
+			val.Type = p.qualifyType(vs.Pos(), val.Type)
+
 			c.Values = append(c.Values, val)
 		}
 
 		cg.Consts = append(cg.Consts, c)
 	}
 
-	return cg
+	return cg, nil
+}
+
+func (p *Parser) parseVars(pkg *Package, vars []*doc.Value) error {
+	for _, dVal := range vars {
+		vg, err := p.parseVar(dVal)
+		if err != nil {
+			return err
+		}
+
+		if len(vg.Vars) == 0 {
+			continue
+		}
+
+		pkg.Vars = append(pkg.Vars, vg)
+	}
+
+	return nil
+}
+
+func (p *Parser) parseVar(dVal *doc.Value) (VarGroup, error) {
+	vg := VarGroup{Doc: p.mkDoc(dVal.Doc)}
+
+	for _, s := range dVal.Decl.Specs {
+		vs, ok := s.(*ast.ValueSpec)
+		if !ok {
+			return VarGroup{}, fmt.Errorf("unsupported var spec type %T", s)
+		}
+
+		// Line comments are part of the printed spec, as for consts, so they
+		// are removed from a copy if excluded. Doc comments are printed from
+		// Doc.
+		spec := *vs
+		spec.Doc = nil
+
+		if p.noComments {
+			spec.Comment = nil
+		}
+
+		sigSpec := spec
+		sigSpec.Comment = nil
+
+		v := Var{
+			Doc:    p.mkDoc(vs.Doc.Text()),
+			Names:  identNames(vs.Names),
+			Embed:  embedPatterns(vs.Doc),
+			Spec:   printNodes(&spec),
+			sig:    printNodes(&sigSpec),
+			rawDoc: strings.TrimSpace(dVal.Doc + "\n" + vs.Doc.Text()),
+		}
+
+		// The directives of an ungrouped var are part of the declaration's
+		// doc comment, which is only kept if the package was created with
+		// [doc.PreserveAST].
+		if !dVal.Decl.Lparen.IsValid() {
+			v.Embed = append(embedPatterns(dVal.Decl.Doc), v.Embed...)
+		}
+
+		if vs.Type != nil {
+			v.Type = p.qualifyType(vs.Pos(), printNodes(vs.Type))
+		}
+
+		if !p.includeSymbol(v) {
+			continue
+		}
+
+		vg.Vars = append(vg.Vars, v)
+	}
+
+	return vg, nil
 }
 
 func (p *Parser) parseFuncs(pkg *Package, fns []*doc.Func) error {
@@ -149,6 +663,24 @@ func (p *Parser) parseFuncs(pkg *Package, fns []*doc.Func) error {
 }
 
 func (p *Parser) parseTypes(pkg *Package, types []*doc.Type) error {
+	var (
+		structs map[string]*ast.StructType
+		ifaces  map[string]*ast.InterfaceType
+		defs    map[string]ast.Expr
+	)
+
+	if p.promote {
+		structs = structTypes(types)
+	}
+
+	if p.expandIface {
+		ifaces = interfaceTypes(types)
+	}
+
+	if p.underlying {
+		defs = localTypeDefs(types)
+	}
+
 	for _, t := range types {
 		if t.Decl.Tok != token.TYPE {
 			continue
@@ -157,6 +689,7 @@ func (p *Parser) parseTypes(pkg *Package, types []*doc.Type) error {
 		for _, spec := range t.Decl.Specs {
 			typeSpec, ok := spec.(*ast.TypeSpec)
 			if !ok {
+				p.warn(t.Name, spec)
 				continue
 			}
 
@@ -164,50 +697,40 @@ func (p *Parser) parseTypes(pkg *Package, types []*doc.Type) error {
 				return fmt.Errorf("parsing consts for %s type: %w", t.Name, err)
 			}
 
+			if err := p.parseVars(pkg, t.Vars); err != nil {
+				return fmt.Errorf("parsing vars for %s type: %w", t.Name, err)
+			}
+
 			if err := p.parseFuncs(pkg, t.Funcs); err != nil {
 				return fmt.Errorf("parsing functions for %s type: %w", t.Name, err)
 			}
 
 			td := TypeDef{
-				Name: t.Name,
-				Doc:  p.mkDoc(t.Doc),
+				Name:       t.Name,
+				Doc:        p.mkDoc(t.Doc),
+				TypeParams: p.parseFieldList(typeSpec.TypeParams, SymbolTypeParamField),
+				Alias:      typeSpec.Assign != token.NoPos,
+				Examples:   p.parseExamples(t.Examples),
+				rawDoc:     t.Doc,
 			}
 
 			switch ts := typeSpec.Type.(type) {
 			case *ast.Ident:
 				td.Type = ts.Name
+
+				if p.underlying {
+					td.Underlying = underlyingType(ts.Name, defs)
+				}
+			case *ast.SelectorExpr:
+				// Qualified identifiers of types in other packages, e.g.
+				// aliases re-exporting them.
+				td.Type = printNodes(ts)
 			case *ast.StructType:
 				td.Type = "struct"
-				td.Fields = p.parseFieldList(ts.Fields, SymbolStructField)
+				td.Fields = p.parseStructFields(ts, structs)
 			case *ast.InterfaceType:
 				td.Type = "interface"
-
-				if ts.Methods != nil {
-					for _, m := range ts.Methods.List {
-						ft, ok := m.Type.(*ast.FuncType)
-						if !ok {
-							continue
-						}
-
-						f := Func{
-							Name:       m.Names[0].Name,
-							Params:     p.parseFieldList(ft.Params, SymbolParamField),
-							Results:    p.parseFieldList(ft.Results, SymbolResultField),
-							funcKw:     false,
-							symbolType: SymbolMethod,
-						}
-
-						if m.Doc != nil {
-							f.Doc = p.mkDoc(m.Doc.Text())
-						}
-
-						if m.Comment != nil {
-							f.Comment = p.mkDoc(m.Comment.Text())
-						}
-
-						td.Methods = append(td.Methods, f)
-					}
-				}
+				td.Methods, td.Fields = p.parseInterfaceType(t.Name, ts, ifaces)
 			case *ast.FuncType:
 				td.Type = "func"
 				td.Params = p.parseFieldList(ts.Params, SymbolParamField)
@@ -234,25 +757,23 @@ func (p *Parser) parseTypes(pkg *Package, types []*doc.Type) error {
 					td.Len = printNodes(ts.Len)
 				}
 			default:
+				p.warn(t.Name, ts)
 				continue
 			}
 
-			methods := make([]Func, 0, len(t.Methods))
-
-			for _, m := range t.Methods {
-				pm := p.parseFunc(m, SymbolMethod)
-				if !p.includeSymbol(pm) {
-					continue
-				}
+			p.qualifyTypeDef(typeSpec.Pos(), &td)
 
-				methods = append(methods, pm)
-			}
+			methods := p.parseMethods(t.Methods)
 
 			if !p.includeSymbol(td) {
 				pkg.Funcs = append(pkg.Funcs, methods...)
 				continue
 			}
 
+			if p.noEmpty && len(methods) == 0 && p.emptiedType(td, typeSpec.Type, structs, ifaces) {
+				continue
+			}
+
 			td.Methods = append(td.Methods, methods...)
 			pkg.Types = append(pkg.Types, td)
 		}
@@ -261,84 +782,510 @@ func (p *Parser) parseTypes(pkg *Package, types []*doc.Type) error {
 	return nil
 }
 
-func (p *Parser) parseFunc(df *doc.Func, st SymbolType) Func {
-	if st != SymbolFunc && st != SymbolMethod {
-		panic(fmt.Errorf("symbol type must be %v or %v for Func", SymbolFunc, SymbolMethod))
+// emptiedType returns true if td is a struct or interface type definition
+// without fields or methods although its declaration expr has some when
+// parsed without symbol filters, i.e. because all of them were filtered out.
+func (p *Parser) emptiedType(
+	td TypeDef,
+	expr ast.Expr,
+	structs map[string]*ast.StructType,
+	ifaces map[string]*ast.InterfaceType,
+) bool {
+	if len(td.Fields) != 0 || len(td.Methods) != 0 {
+		return false
 	}
 
-	decl := df.Decl
-
-	fn := Func{
-		Name:       df.Name,
-		Doc:        p.mkDoc(df.Doc),
-		funcKw:     decl.Type.Func != token.NoPos,
-		symbolType: st,
+	filters, record, warnings := p.filters, p.record, p.warnings
+	p.filters, p.record = nil, false
+
+	defer func() {
+		p.filters, p.record, p.warnings = filters, record, warnings
+	}()
+
+	switch ts := expr.(type) {
+	case *ast.StructType:
+		return len(p.parseStructFields(ts, structs)) != 0
+	case *ast.InterfaceType:
+		methods, fields := p.parseInterfaceType(td.Name, ts, ifaces)
+		return len(methods) != 0 || len(fields) != 0
+	default:
+		return false
 	}
+}
 
-	if decl.Recv != nil && decl.Recv.NumFields() != 0 {
-		fr := p.parseField(decl.Recv.List[0], SymbolReceiverField)
-		fn.Receiver = &fr
+// parseStructFields parses the fields of a struct type, with the fields of
+// embedded struct types in structs promoted into it if configured.
+func (p *Parser) parseStructFields(st *ast.StructType, structs map[string]*ast.StructType) []Field {
+	if !p.promote {
+		return p.parseFieldList(st.Fields, SymbolStructField)
 	}
 
-	if decl.Type.Params != nil && decl.Type.Params.NumFields() != 0 {
-		fn.Params = p.parseFieldList(decl.Type.Params, SymbolParamField)
-	}
+	// Symbol filters are applied after promotion, as fields of embedded
+	// types are promoted regardless of whether the embedded field itself
+	// is exported.
+	fields := p.promoteFields(st, "", structs, map[*ast.StructType]bool{st: true})
+	res := make([]Field, 0, len(fields))
 
-	if decl.Type.Results != nil && decl.Type.Results.NumFields() != 0 {
-		fn.Results = p.parseFieldList(decl.Type.Results, SymbolResultField)
+	for _, f := range fields {
+		if p.includeSymbol(f) {
+			res = append(res, f)
+		}
 	}
 
-	return fn
+	return res
 }
 
-func (p *Parser) parseFieldList(fl *ast.FieldList, st SymbolType) []Field {
-	if !isFieldSymbolType(st) {
-		panic(fmt.Errorf("symbol type must be %v, %v, %v, or %v for Field",
-			SymbolStructField, SymbolPackage, SymbolResultField, SymbolReceiverField),
-		)
+// promoteFields returns the unfiltered fields of st, with embedded fields of
+// struct types in structs replaced by their promoted fields.
+//
+// Fields declared in st take precedence over promoted fields with the same
+// name, and earlier embedded fields take precedence over later ones. If st is
+// an embedded type named origin, the fields declared in it are annotated with
+// a comment noting their origin, so promoted fields note the type declaring
+// them.
+func (p *Parser) promoteFields(
+	st *ast.StructType,
+	origin string,
+	structs map[string]*ast.StructType,
+	visiting map[*ast.StructType]bool,
+) []Field {
+	fields := p.parseInlineFields(st.Fields)
+	names := make(map[string]struct{})
+
+	for _, f := range fields {
+		for _, n := range f.Names {
+			names[n] = struct{}{}
+		}
 	}
 
-	if fl == nil {
-		return nil
-	}
+	res := make([]Field, 0, len(fields))
 
-	res := make([]Field, 0, len(fl.List))
+	for _, f := range fields {
+		embedded := receiverTypeName(f.Type)
+
+		est, ok := structs[embedded]
+		if len(f.Names) != 0 || !ok || visiting[est] {
+			if origin != "" {
+				f.Comment = promotedComment(f.Comment, origin)
+			}
+
+			res = append(res, f)
 
-	for _, f := range fl.List {
-		pf := p.parseField(f, st)
-		if !p.includeSymbol(pf) {
 			continue
 		}
 
-		res = append(res, pf)
-	}
+		visiting[est] = true
 
-	return res
-}
+		for _, pf := range p.promoteFields(est, embedded, structs, visiting) {
+			pNames := make([]string, 0, len(pf.Names))
 
-func (p *Parser) parseField(af *ast.Field, st SymbolType) Field {
-	f := Field{
-		Names:      identNames(af.Names),
-		Type:       printNodes(af.Type),
-		symbolType: st,
-	}
+			for _, n := range pf.Names {
+				if _, ok := names[n]; ok {
+					continue
+				}
+
+				names[n] = struct{}{}
+				pNames = append(pNames, n)
+			}
+
+			if len(pNames) == 0 && len(pf.Names) != 0 {
+				continue
+			}
+
+			pf.Names = pNames
+			res = append(res, pf)
+		}
+
+		delete(visiting, est)
+	}
+
+	return res
+}
+
+func (p *Parser) parseMethods(fns []*doc.Func) []Func {
+	if p.noMethods {
+		return nil
+	}
+
+	methods := make([]Func, 0, len(fns))
+
+	for _, m := range fns {
+		pm := p.parseFunc(m, SymbolMethod)
+		if !p.includeSymbol(pm) {
+			continue
+		}
+
+		methods = append(methods, pm)
+	}
+
+	return methods
+}
+
+// parseInterfaceType parses the methods and embedded interfaces of an
+// interface type of the named symbol, with the methods of embedded interface
+// types in ifaces expanded into it if configured.
+func (p *Parser) parseInterfaceType(
+	symbol string,
+	it *ast.InterfaceType,
+	ifaces map[string]*ast.InterfaceType,
+) ([]Func, []Field) {
+	if !p.expandIface {
+		return p.parseInterfaceMethods(symbol, it)
+	}
+
+	return p.expandInterfaceMethods(symbol, "", it, ifaces, map[*ast.InterfaceType]bool{it: true})
+}
+
+// expandInterfaceMethods returns the methods and embedded interfaces of an
+// interface type of the named symbol, with embedded interface types in ifaces
+// replaced by their methods.
+//
+// Methods declared in it take precedence over embedded methods with the same
+// name, and earlier embedded interfaces take precedence over later ones.
+// Embedded interfaces not in ifaces, such as `io.Closer`, are returned as
+// embedded fields, including the ones of expanded interfaces. Other embedded
+// elements are recorded as unsupported unless symbol is empty.
+//
+// If it is an embedded interface named origin, the methods declared in it are
+// annotated with a comment noting their origin, so expanded methods note the
+// interface declaring them.
+func (p *Parser) expandInterfaceMethods(
+	symbol, origin string,
+	it *ast.InterfaceType,
+	ifaces map[string]*ast.InterfaceType,
+	visiting map[*ast.InterfaceType]bool,
+) ([]Func, []Field) {
+	if it.Methods == nil {
+		return nil, nil
+	}
+
+	names := make(map[string]struct{})
+
+	for _, m := range it.Methods.List {
+		if _, ok := m.Type.(*ast.FuncType); ok {
+			names[m.Names[0].Name] = struct{}{}
+		}
+	}
+
+	var (
+		methods []Func
+		embeds  []Field
+	)
+
+	embedded := make(map[string]struct{})
+
+	addEmbed := func(f Field) {
+		if _, ok := embedded[f.Type]; ok {
+			return
+		}
+
+		embedded[f.Type] = struct{}{}
+		embeds = append(embeds, f)
+	}
+
+	for _, m := range it.Methods.List {
+		if ft, ok := m.Type.(*ast.FuncType); ok {
+			method := p.parseInterfaceMethod(m, ft)
+
+			if origin != "" {
+				method.Comment = promotedComment(method.Comment, origin)
+			}
+
+			methods = append(methods, method)
+
+			continue
+		}
+
+		var eit *ast.InterfaceType
+
+		ident, ok := m.Type.(*ast.Ident)
+		if ok {
+			eit, ok = ifaces[ident.Name]
+		}
+
+		if !ok && isEmbeddedInterface(m.Type) {
+			if f, ok := p.includeField(p.parseField(m, SymbolStructField)); ok {
+				addEmbed(f)
+			}
+
+			continue
+		}
+
+		if !ok || visiting[eit] {
+			if symbol != "" {
+				p.warn(symbol, m.Type)
+			}
+
+			continue
+		}
+
+		visiting[eit] = true
+
+		eMethods, eEmbeds := p.expandInterfaceMethods("", ident.Name, eit, ifaces, visiting)
+
+		for _, em := range eMethods {
+			if _, ok := names[em.Name]; ok {
+				continue
+			}
+
+			names[em.Name] = struct{}{}
+			methods = append(methods, em)
+		}
+
+		for _, ef := range eEmbeds {
+			addEmbed(ef)
+		}
+
+		delete(visiting, eit)
+	}
+
+	return methods, embeds
+}
+
+// parseInterfaceMethods parses the methods and embedded interfaces of an
+// interface type of the named symbol, recording other unsupported interface
+// elements unless symbol is empty.
+func (p *Parser) parseInterfaceMethods(symbol string, it *ast.InterfaceType) ([]Func, []Field) {
+	if it.Methods == nil {
+		return nil, nil
+	}
+
+	var (
+		methods []Func
+		embeds  []Field
+	)
+
+	for _, m := range it.Methods.List {
+		ft, ok := m.Type.(*ast.FuncType)
+		if ok {
+			methods = append(methods, p.parseInterfaceMethod(m, ft))
+			continue
+		}
+
+		if isEmbeddedInterface(m.Type) {
+			if f, ok := p.includeField(p.parseField(m, SymbolStructField)); ok {
+				embeds = append(embeds, f)
+			}
+
+			continue
+		}
+
+		if symbol != "" {
+			p.warn(symbol, m.Type)
+		}
+	}
+
+	return methods, embeds
+}
+
+// parseInterfaceMethod parses interface method m with function type ft.
+func (p *Parser) parseInterfaceMethod(m *ast.Field, ft *ast.FuncType) Func {
+	f := Func{
+		Name:       m.Names[0].Name,
+		Params:     p.parseFieldList(ft.Params, SymbolParamField),
+		Results:    p.parseFieldList(ft.Results, SymbolResultField),
+		symbolType: SymbolMethod,
+	}
+
+	if m.Doc != nil {
+		f.Doc = p.mkDoc(m.Doc.Text())
+		f.rawDoc = m.Doc.Text()
+
+		if p.docSections {
+			f.Sections = parseDocSections(f.rawDoc)
+		}
+	}
+
+	if m.Comment != nil {
+		f.Comment = p.mkLineComment(m.Comment.Text())
+	}
+
+	return f
+}
+
+func (p *Parser) parseFunc(df *doc.Func, st SymbolType) Func {
+	if st != SymbolFunc && st != SymbolMethod {
+		panic(fmt.Errorf("symbol type must be %v or %v for Func", SymbolFunc, SymbolMethod))
+	}
+
+	decl := df.Decl
+
+	fn := Func{
+		Name:       df.Name,
+		Doc:        p.mkDoc(df.Doc),
+		Examples:   p.parseExamples(df.Examples),
+		symbolType: st,
+		rawDoc:     df.Doc,
+	}
+
+	if p.docSections {
+		fn.Sections = parseDocSections(df.Doc)
+	}
+
+	if decl.Recv != nil && decl.Recv.NumFields() != 0 {
+		fr := p.parseField(decl.Recv.List[0], SymbolReceiverField)
+		fn.Receiver = &fr
+	}
+
+	if decl.Type.TypeParams != nil && decl.Type.TypeParams.NumFields() != 0 {
+		fn.TypeParams = p.parseFieldList(decl.Type.TypeParams, SymbolTypeParamField)
+	}
+
+	if decl.Type.Params != nil && decl.Type.Params.NumFields() != 0 {
+		fn.Params = p.parseFieldList(decl.Type.Params, SymbolParamField)
+	}
+
+	if decl.Type.Results != nil && decl.Type.Results.NumFields() != 0 {
+		fn.Results = p.parseFieldList(decl.Type.Results, SymbolResultField)
+	}
+
+	return fn
+}
+
+// parseExamples parses examples associated with a symbol if configured with
+// [WithExamples].
+func (p *Parser) parseExamples(examples []*doc.Example) []Example {
+	if p.exampleSet == nil || len(examples) == 0 {
+		return nil
+	}
+
+	res := make([]Example, 0, len(examples))
+
+	for _, ex := range examples {
+		var b strings.Builder
+
+		node := &printer.CommentedNode{Node: ex.Code, Comments: ex.Comments}
+		if err := printer.Fprint(&b, p.exampleSet, node); err != nil {
+			continue
+		}
+
+		code := b.String()
+
+		if _, ok := ex.Code.(*ast.BlockStmt); ok {
+			code = unindentBlock(code)
+		}
+
+		res = append(res, Example{
+			Suffix: ex.Suffix,
+			Doc:    p.mkDoc(ex.Doc),
+			Code:   code,
+			Output: ex.Output,
+		})
+	}
+
+	return res
+}
+
+func (p *Parser) parseFieldList(fl *ast.FieldList, st SymbolType) []Field {
+	if !isFieldSymbolType(st) {
+		panic(fmt.Errorf("symbol type must be %v, %v, %v, %v, or %v for Field",
+			SymbolStructField, SymbolParamField, SymbolResultField, SymbolReceiverField, SymbolTypeParamField),
+		)
+	}
+
+	if fl == nil {
+		return nil
+	}
+
+	res := make([]Field, 0, len(fl.List))
+
+	for _, f := range fl.List {
+		pf, ok := p.includeField(p.parseField(f, st))
+		if !ok {
+			continue
+		}
+
+		res = append(res, pf)
+	}
+
+	return res
+}
+
+// includeField returns f and whether to include it according to the symbol
+// filters.
+//
+// Names of param and result fields made subject to ident filters with
+// [WithIncludeUnfilterable] are filtered individually, so `a, b int` becomes
+// `a int` if `b` is excluded.
+func (p *Parser) includeField(f Field) (Field, bool) {
+	if p.filterAll && (f.symbolType == SymbolParamField || f.symbolType == SymbolResultField) {
+		f.filterable = true
+	}
+
+	if !f.filterable || len(f.Names) < 2 {
+		return f, p.includeSymbol(f)
+	}
+
+	names := make([]string, 0, len(f.Names))
+
+	for _, name := range f.Names {
+		nf := f
+		nf.Names = []string{name}
+
+		if p.includeSymbol(nf) {
+			names = append(names, name)
+		}
+	}
+
+	f.Names = names
+
+	return f, len(names) != 0
+}
+
+func (p *Parser) parseField(af *ast.Field, st SymbolType) Field {
+	f := Field{
+		Names:      identNames(af.Names),
+		Type:       printType(af.Type),
+		symbolType: st,
+	}
+
+	if typ := p.qualifyType(af.Pos(), f.Type); typ != f.Type {
+		f.Type, f.srcType = typ, f.Type
+	}
 
 	if af.Doc != nil {
 		f.Doc = p.mkDoc(af.Doc.Text())
 	}
 
 	if af.Comment != nil {
-		f.Comment = p.mkDoc(af.Comment.Text())
+		f.Comment = p.mkLineComment(af.Comment.Text())
 	}
 
 	if !p.noTags && af.Tag != nil {
 		f.Tags = p.parseFieldTags(af.Tag)
 	}
 
+	switch it := inlineType(af.Type).(type) {
+	case *ast.StructType:
+		f.Fields = p.parseInlineFields(it.Fields)
+	case *ast.InterfaceType:
+		// Inline types are rendered from their type string, which includes
+		// any elements not represented by the parsed methods.
+		f.Methods, f.Fields = p.parseInterfaceMethods("", it)
+	}
+
 	return f
 }
 
-func (*Parser) parseFieldTags(aft *ast.BasicLit) []FieldTag {
+// parseInlineFields parses the fields of an inline anonymous struct type.
+//
+// Symbol filters are not applied, as the fields are part of the field's type
+// and are always present in its rendered type string.
+func (p *Parser) parseInlineFields(fl *ast.FieldList) []Field {
+	if fl == nil || len(fl.List) == 0 {
+		return nil
+	}
+
+	res := make([]Field, 0, len(fl.List))
+
+	for _, f := range fl.List {
+		res = append(res, p.parseField(f, SymbolStructField))
+	}
+
+	return res
+}
+
+func (p *Parser) parseFieldTags(aft *ast.BasicLit) []FieldTag {
 	parsed := parseFieldTags(aft.Value)
 	if len(parsed) == 0 {
 		return nil
@@ -346,14 +1293,52 @@ func (*Parser) parseFieldTags(aft *ast.BasicLit) []FieldTag {
 
 	tags := make([]FieldTag, 0, len(parsed))
 
-	for _, p := range parsed {
-		tags = append(tags, FieldTag{Name: p[0], Values: p[1:]})
+	for _, pt := range parsed {
+		if !p.includeTag(pt[0]) {
+			continue
+		}
+
+		tags = append(tags, FieldTag{Name: pt[0], Values: pt[1:]})
+	}
+
+	if len(tags) == 0 {
+		return nil
 	}
 
 	return tags
 }
 
+func (p *Parser) includeTag(key string) bool {
+	if len(p.keepTags) != 0 {
+		if _, ok := p.keepTags[key]; !ok {
+			return false
+		}
+	}
+
+	_, drop := p.dropTags[key]
+
+	return !drop
+}
+
 func (p *Parser) includeSymbol(s Symbol) bool {
+	include := p.filterSymbol(s)
+
+	if p.record {
+		p.results = append(p.results, FilterResult{Symbol: s, Included: include})
+	}
+
+	return include
+}
+
+func (p *Parser) filterSymbol(s Symbol) bool {
+	if isCgoIdent(s.Ident()) {
+		return false
+	}
+
+	if p.qualifyPkgs {
+		s = sourceSymbol(s)
+	}
+
 	for _, f := range p.filters {
 		if !f.Include(s) {
 			return false
@@ -364,6 +1349,12 @@ func (p *Parser) includeSymbol(s Symbol) bool {
 }
 
 func (p *Parser) mkDoc(fullDoc string) string {
+	return p.formatDoc(fullDoc, p.fullDocs)
+}
+
+// formatDoc returns doc comment text fullDoc formatted according to the
+// parser's configuration, reduced to its synopsis unless full is true.
+func (p *Parser) formatDoc(fullDoc string, full bool) string {
 	fullDoc = strings.TrimSpace(fullDoc)
 
 	if p.noDocs {
@@ -372,13 +1363,72 @@ func (p *Parser) mkDoc(fullDoc string) string {
 
 	fullDoc = strings.TrimPrefix(strings.TrimSpace(fullDoc), "// ")
 
-	if p.fullDocs {
-		return fullDoc
+	if !p.keepDirs {
+		fullDoc = stripDirectives(fullDoc)
+	}
+
+	if !full {
+		pkg := doc.Package{}
+		fullDoc = pkg.Synopsis(fullDoc)
+	}
+
+	if p.plainDocs {
+		fullDoc = stripDocLinks(fullDoc)
+	}
+
+	return fullDoc
+}
+
+// mkLineComment returns the text of a trailing line comment, or an empty
+// string if the parser is configured to exclude line comments.
+func (p *Parser) mkLineComment(comment string) string {
+	if p.noComments {
+		return ""
 	}
 
-	pkg := doc.Package{}
+	return p.mkDoc(comment)
+}
+
+// WithDocSections configures a [Parser] to parse conventional sections of
+// function and method doc comments, such as `Parameters:`, `Returns:`, and
+// `Example usage:`, into [Func.Sections].
+//
+// Sections are parsed from full doc comments regardless of [WithFullDocs],
+// and are not rendered in package code.
+func WithDocSections() ParserOption {
+	return &docSections{}
+}
+
+type docSections struct{}
+
+func (*docSections) String() string {
+	return "docSections"
+}
+
+func (*docSections) apply(p *Parser) error {
+	p.docSections = true
+	return nil
+}
 
-	return pkg.Synopsis(fullDoc)
+// WithKeepDirectives configures a [Parser] to keep linter and compiler
+// directives such as `// nolint:errcheck` in doc and line comments.
+//
+// Directives are stripped by default, as they are noise in documentation.
+// Directives written without a space after the comment marker, such as
+// `//nolint`, are always stripped by [go/ast.CommentGroup.Text].
+func WithKeepDirectives() ParserOption {
+	return &keepDirectives{}
+}
+
+type keepDirectives struct{}
+
+func (*keepDirectives) String() string {
+	return "keepDirectives"
+}
+
+func (*keepDirectives) apply(p *Parser) error {
+	p.keepDirs = true
+	return nil
 }
 
 // WithFullDocs configures a [Parser] to include full doc comments instead of
@@ -398,6 +1448,24 @@ func (*fullDocs) apply(p *Parser) error {
 	return nil
 }
 
+// WithFullPackageDoc configures a [Parser] to include the full package doc
+// comment, while including short synopsis comments for symbols unless
+// [WithFullDocs] is also used.
+func WithFullPackageDoc() ParserOption {
+	return &fullPackageDoc{}
+}
+
+type fullPackageDoc struct{}
+
+func (*fullPackageDoc) String() string {
+	return "fullPackageDoc"
+}
+
+func (*fullPackageDoc) apply(p *Parser) error {
+	p.fullPkgDoc = true
+	return nil
+}
+
 // WithNoDocs configures a [Parser] to not include any doc comments for symbols.
 func WithNoDocs() ParserOption {
 	return &noDocs{}
@@ -414,6 +1482,42 @@ func (*noDocs) apply(p *Parser) error {
 	return nil
 }
 
+// WithNoComments configures a [Parser] to not include trailing line comments
+// of struct fields, function parameters and results, and interface methods.
+//
+// Doc comments are kept unless [WithNoDocs] is also used.
+func WithNoComments() ParserOption {
+	return &noComments{}
+}
+
+type noComments struct{}
+
+func (*noComments) String() string {
+	return "noComments"
+}
+
+func (*noComments) apply(p *Parser) error {
+	p.noComments = true
+	return nil
+}
+
+// WithPlainDocs configures a [Parser] to strip the square brackets of doc
+// links in doc comments, turning e.g. `[MyStruct]` into `MyStruct`.
+func WithPlainDocs() ParserOption {
+	return &plainDocs{}
+}
+
+type plainDocs struct{}
+
+func (*plainDocs) String() string {
+	return "plainDocs"
+}
+
+func (*plainDocs) apply(p *Parser) error {
+	p.plainDocs = true
+	return nil
+}
+
 // WithNoDocs configures a [Parser] to not include any struct field tags.
 func WithNoTags() ParserOption {
 	return &noTags{}
@@ -430,6 +1534,519 @@ func (*noTags) apply(p *Parser) error {
 	return nil
 }
 
+// WithNoMethods configures a [Parser] to not include methods declared on types.
+//
+// Method sets of interface types are kept, as they are part of the interface
+// type definition.
+func WithNoMethods() ParserOption {
+	return &noMethods{}
+}
+
+type noMethods struct{}
+
+func (*noMethods) String() string {
+	return "noMethods"
+}
+
+func (*noMethods) apply(p *Parser) error {
+	p.noMethods = true
+	return nil
+}
+
+// WithNoReceiverNames configures a [Parser] to render method receivers without
+// their variable names, e.g. `(*MyStruct)` instead of `(s *MyStruct)`.
+func WithNoReceiverNames() ParserOption {
+	return &noReceiverNames{}
+}
+
+type noReceiverNames struct{}
+
+func (*noReceiverNames) String() string {
+	return "noReceiverNames"
+}
+
+func (*noReceiverNames) apply(p *Parser) error {
+	p.noRecvNames = true
+	return nil
+}
+
+// WithNoReceivers configures a [Parser] to render methods without their
+// receivers, as if they were functions, e.g. `func MyMethod()` instead of
+// `func (s MyStruct) MyMethod()`.
+//
+// Methods are still rendered after the types they are declared on.
+func WithNoReceivers() ParserOption {
+	return &noReceivers{}
+}
+
+type noReceivers struct{}
+
+func (*noReceivers) String() string {
+	return "noReceivers"
+}
+
+func (*noReceivers) apply(p *Parser) error {
+	p.noRecvs = true
+	return nil
+}
+
+// WithWrap configures a [Parser] to wrap doc comments at column width when
+// rendering package code.
+//
+// Comments are not wrapped if width is 0. Default width is 80.
+func WithWrap(width int) ParserOption {
+	return &wrap{width: width}
+}
+
+type wrap struct {
+	width int
+}
+
+func (w *wrap) String() string {
+	return fmt.Sprintf("wrap(width=%d)", w.width)
+}
+
+func (w *wrap) apply(p *Parser) error {
+	if w.width < 0 {
+		return fmt.Errorf("wrap width must be a positive integer, got %d", w.width)
+	}
+
+	p.wrap = w.width
+
+	return nil
+}
+
+// WithResolveUnderlying configures a [Parser] to annotate identifier type
+// definitions referring to other package-local types with their underlying
+// type in a line comment, e.g. `// underlying: int`.
+//
+// Type definitions are followed transitively within the package. Packages
+// parsed with [Parser.TypedPackage] are annotated with the underlying type
+// resolved from type information, which includes types of other packages.
+func WithResolveUnderlying() ParserOption {
+	return &resolveUnderlying{}
+}
+
+type resolveUnderlying struct{}
+
+func (*resolveUnderlying) String() string {
+	return "resolveUnderlying"
+}
+
+func (*resolveUnderlying) apply(p *Parser) error {
+	p.underlying = true
+	return nil
+}
+
+// WithNoEmptyGroups configures a [Parser] to omit struct and interface types
+// that have no fields or methods left after filtering, and to render empty
+// struct and interface bodies on a single line, e.g. `type T struct{}`.
+//
+// Types declared without fields or methods are kept, as are types with
+// methods declared on them.
+func WithNoEmptyGroups() ParserOption {
+	return &noEmptyGroups{}
+}
+
+type noEmptyGroups struct{}
+
+func (*noEmptyGroups) String() string {
+	return "noEmptyGroups"
+}
+
+func (*noEmptyGroups) apply(p *Parser) error {
+	p.noEmpty = true
+	return nil
+}
+
+// WithCompact configures a [Parser] to render struct and interface types on a
+// single line, e.g. `type Config struct { A int; B string }`.
+//
+// Doc and line comments of fields and interface methods are omitted. Methods
+// declared on types are rendered below them as usual.
+func WithCompact() ParserOption {
+	return &compact{}
+}
+
+type compact struct{}
+
+func (*compact) String() string {
+	return "compact"
+}
+
+func (*compact) apply(p *Parser) error {
+	p.compact = true
+	return nil
+}
+
+// WithGroupConstructors configures a [Parser] to render functions returning
+// a type of the package, or a pointer to one, right after the declaration of
+// the type, e.g. `NewMyStruct` after `MyStruct`.
+//
+// Functions are matched by the type of their first result.
+func WithGroupConstructors() ParserOption {
+	return &groupConstructors{}
+}
+
+type groupConstructors struct{}
+
+func (*groupConstructors) String() string {
+	return "groupConstructors"
+}
+
+func (*groupConstructors) apply(p *Parser) error {
+	p.groupCtors = true
+	return nil
+}
+
+// WithMethodComments configures a [Parser] to render the methods of interface
+// types with the synopsis of their doc comment as a trailing line comment
+// instead of a doc comment, grouping the methods without blank lines.
+//
+// Methods with a line comment keep it instead of the synopsis.
+func WithMethodComments() ParserOption {
+	return &methodComments{}
+}
+
+type methodComments struct{}
+
+func (*methodComments) String() string {
+	return "methodComments"
+}
+
+func (*methodComments) apply(p *Parser) error {
+	p.methodDocs = true
+	return nil
+}
+
+// WithSortMethods configures a [Parser] to render the methods of interface
+// types in alphabetical order instead of source order.
+//
+// Only the rendered code is affected; [TypeDef.Methods] keeps the methods in
+// source order.
+func WithSortMethods() ParserOption {
+	return &sortMethods{}
+}
+
+type sortMethods struct{}
+
+func (*sortMethods) String() string {
+	return "sortMethods"
+}
+
+func (*sortMethods) apply(p *Parser) error {
+	p.sortMethods = true
+	return nil
+}
+
+// WithStripInternalTypes configures a [Parser] to render references to
+// unexported types of the package in signatures and type definitions as
+// `any`, to avoid leaking internal type names. Declarations with replaced
+// references are marked with a line comment.
+//
+// Only the rendered code is affected; the types of entities are unchanged.
+func WithStripInternalTypes() ParserOption {
+	return &stripInternalTypes{}
+}
+
+type stripInternalTypes struct{}
+
+func (*stripInternalTypes) String() string {
+	return "stripInternalTypes"
+}
+
+func (*stripInternalTypes) apply(p *Parser) error {
+	p.stripTypes = true
+	return nil
+}
+
+// WithExamples configures a [Parser] to include testable examples associated
+// with symbols, such as `ExampleMyFunc` for `MyFunc`, in doc comments.
+//
+// Examples are only associated with symbols of packages created with
+// [doc.NewFromFiles] including test files. The code of examples is printed
+// with fset, which must be the file set the files were parsed with.
+func WithExamples(fset *token.FileSet) ParserOption {
+	return &examples{fset: fset}
+}
+
+type examples struct {
+	fset *token.FileSet
+}
+
+func (*examples) String() string {
+	return "examples"
+}
+
+func (e *examples) apply(p *Parser) error {
+	if e.fset == nil {
+		return errors.New("examples file set must not be nil")
+	}
+
+	p.exampleSet = e.fset
+
+	return nil
+}
+
+// WithZeroValues configures a [Parser] to annotate struct fields with the zero
+// value of their type in a line comment, e.g. `// zero: 0`.
+//
+// Fields of named types are not annotated, as their zero value cannot be
+// inferred from the type name alone.
+func WithZeroValues() ParserOption {
+	return &zeroValues{}
+}
+
+type zeroValues struct{}
+
+func (*zeroValues) String() string {
+	return "zeroValues"
+}
+
+func (*zeroValues) apply(p *Parser) error {
+	p.zeroValues = true
+	return nil
+}
+
+// WithMaxMethods configures a [Parser] to render at most n methods per type,
+// followed by a comment with the number of omitted methods.
+//
+// A value of 0 renders all methods.
+func WithMaxMethods(n int) ParserOption {
+	return &maxMethods{n: n}
+}
+
+type maxMethods struct {
+	n int
+}
+
+func (mm *maxMethods) String() string {
+	return fmt.Sprintf("maxMethods(n=%d)", mm.n)
+}
+
+func (mm *maxMethods) apply(p *Parser) error {
+	if mm.n < 0 {
+		return fmt.Errorf("max methods must be a non-negative integer, got %d", mm.n)
+	}
+
+	p.maxMethods = mm.n
+
+	return nil
+}
+
+// WithWrapSignatures configures a [Parser] to wrap function, method, and
+// interface method signatures longer than width columns by rendering each
+// parameter on its own line.
+//
+// Signatures are not wrapped if width is 0, which is the default.
+func WithWrapSignatures(width int) ParserOption {
+	return &wrapSignatures{width: width}
+}
+
+type wrapSignatures struct {
+	width int
+}
+
+func (ws *wrapSignatures) String() string {
+	return fmt.Sprintf("wrapSignatures(width=%d)", ws.width)
+}
+
+func (ws *wrapSignatures) apply(p *Parser) error {
+	if ws.width < 0 {
+		return fmt.Errorf("signature wrap width must be a non-negative integer, got %d", ws.width)
+	}
+
+	p.wrapSigs = ws.width
+
+	return nil
+}
+
+// WithTagKeys configures a [Parser] to include or exclude struct field tags
+// with provided keys, e.g. `json`.
+func WithTagKeys(action FilterAction, keys ...string) ParserOption {
+	return &tagKeys{action: action, keys: keys}
+}
+
+type tagKeys struct {
+	keys   []string
+	action FilterAction
+}
+
+func (tk *tagKeys) String() string {
+	return fmt.Sprintf("tagKeys(action=%s,keys=%s)", tk.action, strings.Join(tk.keys, ","))
+}
+
+func (tk *tagKeys) apply(p *Parser) error {
+	keys := make(map[string]struct{}, len(tk.keys))
+
+	for _, k := range tk.keys {
+		keys[k] = struct{}{}
+	}
+
+	if tk.action == Include {
+		p.keepTags = keys
+	} else {
+		p.dropTags = keys
+	}
+
+	return nil
+}
+
+// WithGroupByKind configures a [Parser] to render package code with symbols
+// grouped in labeled sections by kind instead of in source order.
+func WithGroupByKind() ParserOption {
+	return &groupByKind{}
+}
+
+type groupByKind struct{}
+
+func (*groupByKind) String() string {
+	return "groupByKind"
+}
+
+func (*groupByKind) apply(p *Parser) error {
+	p.grouped = true
+	return nil
+}
+
+// WithQualifiedImports configures a [Parser] to replace package qualifiers in
+// the types of entities with the import paths of the packages they refer to,
+// e.g. `b.Thing` with `github.com/x/b.Thing`, so references to types of
+// other packages can be resolved unambiguously.
+//
+// Qualifiers are resolved with the imports of the source file declaring the
+// entity, including renamed imports, if the files are passed to
+// [Parser.Package]. Rendered code is not affected.
+func WithQualifiedImports() ParserOption {
+	return &qualifiedImports{}
+}
+
+type qualifiedImports struct{}
+
+func (*qualifiedImports) String() string {
+	return "qualifiedImports"
+}
+
+func (*qualifiedImports) apply(p *Parser) error {
+	p.qualifyPkgs = true
+	return nil
+}
+
+// WithImports configures a [Parser] to include the import paths of all
+// package files, rendered as an import declaration after the package clause.
+func WithImports() ParserOption {
+	return &imports{}
+}
+
+type imports struct{}
+
+func (*imports) String() string {
+	return "imports"
+}
+
+func (*imports) apply(p *Parser) error {
+	p.imports = true
+	return nil
+}
+
+// WithPromoteEmbedded configures a [Parser] to replace embedded fields of
+// struct types defined in the same package with the fields promoted from
+// them, annotated with a comment noting their origin.
+//
+// Fields declared in the embedding struct take precedence over promoted
+// fields with the same name.
+func WithPromoteEmbedded() ParserOption {
+	return &promoteEmbedded{}
+}
+
+type promoteEmbedded struct{}
+
+func (*promoteEmbedded) String() string {
+	return "promoteEmbedded"
+}
+
+func (*promoteEmbedded) apply(p *Parser) error {
+	p.promote = true
+	return nil
+}
+
+// WithExpandEmbeddedInterfaces configures a [Parser] to replace embedded
+// interface types defined in the same package with the methods of the
+// embedded interfaces, annotated with a comment noting their origin.
+//
+// Methods declared in the embedding interface take precedence over embedded
+// methods with the same name.
+func WithExpandEmbeddedInterfaces() ParserOption {
+	return &expandEmbeddedInterfaces{}
+}
+
+type expandEmbeddedInterfaces struct{}
+
+func (*expandEmbeddedInterfaces) String() string {
+	return "expandEmbeddedInterfaces"
+}
+
+func (*expandEmbeddedInterfaces) apply(p *Parser) error {
+	p.expandIface = true
+	return nil
+}
+
+// WithFlattenSingleConsts configures a [Parser] to render single const
+// declarations of the same type as a single const declaration group.
+func WithFlattenSingleConsts() ParserOption {
+	return &flattenSingleConsts{}
+}
+
+type flattenSingleConsts struct{}
+
+func (*flattenSingleConsts) String() string {
+	return "flattenSingleConsts"
+}
+
+func (*flattenSingleConsts) apply(p *Parser) error {
+	p.flatConsts = true
+	return nil
+}
+
+// WithFilterResults configures a [Parser] to record the results of applying
+// its symbol filters, available from [Parser.FilterResults].
+func WithFilterResults() ParserOption {
+	return &filterResults{}
+}
+
+type filterResults struct{}
+
+func (*filterResults) String() string {
+	return "filterResults"
+}
+
+func (*filterResults) apply(p *Parser) error {
+	p.record = true
+	return nil
+}
+
+// WithIncludeUnfilterable configures a [Parser] to apply ident filters, such
+// as [FilterMatchingIdents] and [FilterNamePredicate], to the names of function
+// parameters and results, which are otherwise always included.
+//
+// This is intended for auditing, e.g. finding functions with a parameter named
+// `password`. Excluding parameters or results produces signatures that do not
+// match the source and may not compile.
+func WithIncludeUnfilterable() ParserOption {
+	return &includeUnfilterable{}
+}
+
+type includeUnfilterable struct{}
+
+func (*includeUnfilterable) String() string {
+	return "includeUnfilterable"
+}
+
+func (*includeUnfilterable) apply(p *Parser) error {
+	p.filterAll = true
+	return nil
+}
+
 // WithSymbolFilters configures a [Parser] to filter package symbols with
 // provided filter functions.
 func WithSymbolFilters(filters ...SymbolFilter) ParserOption {
@@ -4,17 +4,22 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"go/ast"
 	"go/doc"
+	"go/format"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -128,6 +133,22 @@ func TestParser_Package(t *testing.T) {
 				),
 			},
 		},
+		{
+			name: "exclude matching types",
+			opts: []pkgdmp.ParserOption{
+				pkgdmp.WithSymbolFilters(
+					pkgdmp.FilterMatchingTypes(pkgdmp.Exclude, regexp.MustCompile(`error\)?$|^int$`)),
+				),
+			},
+		},
+		{
+			name: "only funcs",
+			opts: []pkgdmp.ParserOption{
+				pkgdmp.WithSymbolFilters(
+					pkgdmp.FilterSymbolTypes(pkgdmp.Include, pkgdmp.SymbolFunc),
+				),
+			},
+		},
 		{
 			name: "only structs and exclude tags",
 			opts: []pkgdmp.ParserOption{
@@ -178,6 +199,354 @@ func TestParser_Package(t *testing.T) {
 				),
 			},
 		},
+		{
+			name: "exclude methods",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithNoMethods()},
+		},
+		{
+			name: "full doc comments with paragraphs",
+			opts: []pkgdmp.ParserOption{
+				pkgdmp.WithFullDocs(),
+				pkgdmp.WithSymbolFilters(
+					pkgdmp.FilterMatchingIdents(pkgdmp.Include, regexp.MustCompile(`^MyFunction$`)),
+				),
+			},
+		},
+		{
+			name: "keep json tags",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithTagKeys(pkgdmp.Include, "json")},
+		},
+		{
+			name: "drop json tags",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithTagKeys(pkgdmp.Exclude, "json")},
+		},
+		{
+			name: "drop all tag keys",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithTagKeys(pkgdmp.Exclude, "json", "xml")},
+		},
+		{
+			name: "group by kind",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithGroupByKind()},
+		},
+		{
+			name: "no receiver names",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithNoReceiverNames()},
+		},
+		{
+			name: "include imports",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithImports()},
+		},
+		{
+			name: "plain doc comments",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithPlainDocs()},
+		},
+		{
+			name: "wrap comments at 60",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithWrap(60)},
+		},
+		{
+			name: "wrap comments at 100",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithWrap(100)},
+		},
+		{
+			name: "no comment wrapping",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithWrap(0), pkgdmp.WithFullDocs()},
+		},
+		{
+			name:       "const expressions",
+			sourceFile: "const_exprs.go",
+			opts:       nil,
+		},
+		{
+			name:       "anonymous types",
+			sourceFile: "anon_types.go",
+			opts:       nil,
+		},
+		{
+			name:       "generic instantiations",
+			sourceFile: "generics.go",
+			opts:       nil,
+		},
+		{
+			name:       "interface method docs",
+			sourceFile: "iface_docs.go",
+			opts:       nil,
+		},
+		{
+			name:       "embedded fields",
+			sourceFile: "embedded.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude))},
+		},
+		{
+			name:       "promote embedded fields",
+			sourceFile: "embedded.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithPromoteEmbedded()},
+		},
+		{
+			name:       "promote embedded fields and exclude unexported",
+			sourceFile: "embedded.go",
+			opts: []pkgdmp.ParserOption{
+				pkgdmp.WithPromoteEmbedded(),
+				pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude)),
+			},
+		},
+		{
+			name:       "expand embedded interfaces",
+			sourceFile: "embedded_ifaces.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithExpandEmbeddedInterfaces()},
+		},
+		{
+			name:       "char consts",
+			sourceFile: "char_consts.go",
+			opts:       nil,
+		},
+		{
+			name:       "flatten single char consts",
+			sourceFile: "char_consts.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithFlattenSingleConsts()},
+		},
+		{
+			name:       "strip directives",
+			sourceFile: "directives.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithFullDocs()},
+		},
+		{
+			name:       "keep directives",
+			sourceFile: "directives.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithFullDocs(), pkgdmp.WithKeepDirectives()},
+		},
+		{
+			name:       "interface method docs in full",
+			sourceFile: "documented_iface.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithFullDocs()},
+		},
+		{
+			name:       "interface method comments",
+			sourceFile: "documented_iface.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithFullDocs(), pkgdmp.WithMethodComments()},
+		},
+		{
+			name:       "sorted interface method comments",
+			sourceFile: "documented_iface.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithMethodComments(), pkgdmp.WithSortMethods()},
+		},
+		{
+			name:       "func results",
+			sourceFile: "func_results.go",
+			opts:       nil,
+		},
+		{
+			name:       "multi-name fields",
+			sourceFile: "multi_name_fields.go",
+			opts:       nil,
+		},
+		{
+			name: "no comments",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithFullDocs(), pkgdmp.WithNoComments()},
+		},
+		{
+			name:       "no const comments",
+			sourceFile: "enums.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithNoComments()},
+		},
+		{
+			name:       "no var comments",
+			sourceFile: "vars.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithNoComments()},
+		},
+		{
+			name:       "no const docs",
+			sourceFile: "enums.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithNoDocs()},
+		},
+		{
+			name:       "wrap signatures",
+			sourceFile: "long_signatures.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithWrapSignatures(80)},
+		},
+		{
+			name:       "wrap signatures with line comments",
+			sourceFile: "internal_types.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithStripInternalTypes(), pkgdmp.WithWrapSignatures(40)},
+		},
+		{
+			name:       "full package doc",
+			sourceFile: "package_doc.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithFullPackageDoc()},
+		},
+		{
+			name:       "package doc synopsis",
+			sourceFile: "package_doc.go",
+			opts:       nil,
+		},
+		{
+			name: "no receivers",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithNoReceivers()},
+		},
+		{
+			name:       "generic methods",
+			sourceFile: "generic_methods.go",
+			opts:       nil,
+		},
+		{
+			name:       "generic methods without receiver names",
+			sourceFile: "generic_methods.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithNoReceiverNames()},
+		},
+		{
+			name:       "qualified types",
+			sourceFile: "qualified_types.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithImports()},
+		},
+		{
+			name:       "vars",
+			sourceFile: "vars.go",
+			opts:       nil,
+		},
+		{
+			name:       "exported vars",
+			sourceFile: "vars.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude))},
+		},
+		{
+			name:       "excluded vars",
+			sourceFile: "vars.go",
+			opts: []pkgdmp.ParserOption{
+				pkgdmp.WithSymbolFilters(pkgdmp.FilterSymbolTypes(pkgdmp.Exclude, pkgdmp.SymbolVar)),
+			},
+		},
+		{
+			name:       "embed vars",
+			sourceFile: "embed_vars.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithImports()},
+		},
+		{
+			name:       "qualified imports",
+			sourceFile: "qualified_imports.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithQualifiedImports()},
+		},
+		{
+			name:       "mixed typed and untyped consts",
+			sourceFile: "mixed_consts.go",
+			opts:       nil,
+		},
+		{
+			name: "filter param names",
+			opts: []pkgdmp.ParserOption{
+				pkgdmp.WithIncludeUnfilterable(),
+				pkgdmp.WithSymbolFilters(
+					pkgdmp.FilterMatchingIdents(pkgdmp.Exclude, regexp.MustCompile(`^b$`)),
+				),
+			},
+		},
+		{
+			name:       "resolve underlying",
+			sourceFile: "underlying.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithResolveUnderlying()},
+		},
+		{
+			name:       "resolve underlying cycle",
+			sourceFile: "underlying_cycle.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithResolveUnderlying()},
+		},
+		{
+			name:       "show zero values",
+			sourceFile: "zero_values.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithZeroValues()},
+		},
+		{
+			name:       "max methods",
+			sourceFile: "many_methods.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithMaxMethods(1)},
+		},
+		{
+			name:       "type aliases",
+			sourceFile: "aliases.go",
+		},
+		{
+			name:       "flatten single consts",
+			sourceFile: "single_consts.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithFlattenSingleConsts()},
+		},
+		{
+			name:       "cgo symbols",
+			sourceFile: "cgo.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithImports(), pkgdmp.WithSymbolFilters()},
+		},
+		{
+			name:       "unsupported const values",
+			sourceFile: "unsupported_consts.go",
+			opts:       nil,
+		},
+		{
+			name:       "const call values",
+			sourceFile: "const_calls.go",
+			opts:       nil,
+		},
+		{
+			name:       "empty types",
+			sourceFile: "empty_types.go",
+			opts: []pkgdmp.ParserOption{
+				pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude)),
+			},
+		},
+		{
+			name:       "no empty groups",
+			sourceFile: "empty_types.go",
+			opts: []pkgdmp.ParserOption{
+				pkgdmp.WithNoEmptyGroups(),
+				pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude)),
+			},
+		},
+		{
+			name:       "no empty groups grouped by kind",
+			sourceFile: "empty_types.go",
+			opts: []pkgdmp.ParserOption{
+				pkgdmp.WithNoEmptyGroups(),
+				pkgdmp.WithGroupByKind(),
+				pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude), pkgdmp.FilterSymbolTypes(pkgdmp.Exclude, pkgdmp.SymbolMethod)),
+			},
+		},
+		{
+			name: "group constructors",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithGroupConstructors()},
+		},
+		{
+			name: "group constructors grouped by kind",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithGroupConstructors(), pkgdmp.WithGroupByKind()},
+		},
+		{
+			name:       "strip internal types",
+			sourceFile: "internal_types.go",
+			opts: []pkgdmp.ParserOption{
+				pkgdmp.WithStripInternalTypes(),
+				pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude)),
+			},
+		},
+		{
+			name: "compact",
+			opts: []pkgdmp.ParserOption{pkgdmp.WithCompact()},
+		},
+		{
+			name:       "compact max methods",
+			sourceFile: "many_methods.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithCompact(), pkgdmp.WithMaxMethods(2)},
+		},
+		{
+			name:       "compact func fields",
+			sourceFile: "compact.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithCompact()},
+		},
+		{
+			name:       "compact inline types",
+			sourceFile: "anon_types.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithCompact()},
+		},
+		{
+			name:       "multi-line field comments",
+			sourceFile: "field_comments.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithFullDocs()},
+		},
 	}
 
 	for _, tc := range tt {
@@ -189,57 +558,1542 @@ func TestParser_Package(t *testing.T) {
 	}
 }
 
-func (tc *parserTestCase) run(tb *testing.T) {
-	tb.Helper()
+func TestNewParser_InvalidWrap(t *testing.T) {
+	_, err := pkgdmp.NewParser(pkgdmp.WithWrap(-1))
+	if err == nil {
+		t.Fatal("expected error when wrap width is negative, but got no error")
+	}
 
-	pkgParser, _ := pkgdmp.NewParser(tc.opts...)
+	if !strings.Contains(err.Error(), "wrap width must be a positive integer") {
+		t.Errorf("expected error about wrap width, but got: %v", err)
+	}
+}
 
-	pkg, err := pkgParser.Package(tc.pkgDoc(tb))
-	if err != nil {
-		tb.Errorf("expected no error when parsing package, but got: %v", err)
+func TestNewParser_InvalidWrapSignatures(t *testing.T) {
+	_, err := pkgdmp.NewParser(pkgdmp.WithWrapSignatures(-1))
+	if err == nil {
+		t.Fatal("expected error when signature wrap width is negative, but got no error")
 	}
 
-	tc.compareGolden(tb, pkg)
+	if !strings.Contains(err.Error(), "signature wrap width must be a non-negative integer") {
+		t.Errorf("expected error about signature wrap width, but got: %v", err)
+	}
 }
 
-func (tc *parserTestCase) pkgDoc(tb testing.TB) *doc.Package {
-	tb.Helper()
+func TestNewParser_InvalidMaxMethods(t *testing.T) {
+	_, err := pkgdmp.NewParser(pkgdmp.WithMaxMethods(-1))
+	if err == nil {
+		t.Fatal("expected error when max methods is negative, but got no error")
+	}
 
-	if tc.sourceFile == "" {
-		return defaultDocPkg
+	if !strings.Contains(err.Error(), "max methods must be a non-negative integer") {
+		t.Errorf("expected error about max methods, but got: %v", err)
 	}
+}
 
-	tDir := tb.TempDir()
+func TestParser_Package_FullDocsParagraphs(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser(
+		pkgdmp.WithFullDocs(),
+		pkgdmp.WithSymbolFilters(
+			pkgdmp.FilterMatchingIdents(pkgdmp.Include, regexp.MustCompile(`^MyFunction$`)),
+		),
+	)
 
-	src, err := os.Open(filepath.Join("testdata", tc.sourceFile))
+	pkg, err := pkgParser.Package(defaultDocPkg)
 	if err != nil {
-		tb.Fatalf("error opening source file: %v", err)
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
 	}
-	defer src.Close()
 
-	dst, err := os.Create(filepath.Join(tDir, "file.go"))
-	if err != nil {
-		tb.Fatalf("error creating temporary source file: %v", err)
+	if len(pkg.Funcs) != 1 {
+		t.Fatalf("expected 1 function, but got %d", len(pkg.Funcs))
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, src); err != nil {
-		tb.Fatalf("error copying source file content to temporary file")
+	actual := pkg.Funcs[0].String()
+
+	for _, want := range []string{
+		"// Otherwise, it returns false to indicate that the integers are not equal.\n//\n// This function",
+		"// Example usage:\n//\n//\tresult := MyFunction(5, 5) // result will be true\n",
+		"// Parameters:\n//\n//\ta: The first integer to compare.\n//\tb: The second integer to compare.\n//\n// Returns:",
+	} {
+		if !strings.Contains(actual, want) {
+			t.Errorf("expected function code to contain:\n\n%s\n\nbut got:\n\n%s", want, actual)
+		}
 	}
+}
+
+// numericLiteralsSrc is kept inline rather than in testdata, as its literals
+// are intentionally not gofmt formatted.
+const numericLiteralsSrc = `package mypackage
+
+// Literal bases checks that parser preserves the base of integer literals.
+const (
+	MyHex         = 0x1F
+	MyHexUpper    = 0XFF
+	MyOctal       = 0o755
+	MyOctalUpper  = 0O644
+	MyLegacyOctal = 0600
+	MyBinary      = 0b1010
+	MyBinaryUpper = 0B0101
+	MyDecimal     = 42
+)
+
+// Underscores checks that parser preserves digit separators.
+const (
+	MyMillion   = 1_000_000
+	MyHexBytes  = 0xFF_FF
+	MyBinNibble = 0b1111_0000
+	MyFloat     = 1_000.000_5
+)
+
+// Floats checks that parser preserves float and imaginary literals.
+const (
+	MyExponent          = 1e9
+	MyExponentUp        = 6.022E23
+	MyHexFloat          = 0x1p-2
+	MyImaginary         = 1.5i
+	MyTypedHex   uint16 = 0xBEEF
+	MyShiftedHex        = 0x10 << 2
+)
+`
+
+func TestParser_Package_NumericLiterals(t *testing.T) {
+	tc := &parserTestCase{name: "numeric literals"}
 
 	fset := token.NewFileSet()
 
-	pkgMap, err := parser.ParseDir(fset, tDir, nil, parser.ParseComments)
+	file, err := parser.ParseFile(fset, "numeric_literals.go", numericLiteralsSrc, parser.ParseComments)
 	if err != nil {
-		tb.Fatalf("error parsing source: %v", err)
+		t.Fatalf("error parsing source: %v", err)
 	}
 
-	pkg, ok := pkgMap[defaultPkgName]
-	if !ok {
-		tb.Fatalf("expected source to specify package %q", defaultPkgName)
+	dPkg, err := doc.NewFromFiles(fset, []*ast.File{file}, "example.com/mypackage", doc.AllDecls|doc.PreserveAST)
+	if err != nil {
+		t.Fatalf("error creating doc package: %v", err)
+	}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(dPkg, file)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	tc.compareGolden(t, pkg)
+
+	// Specs and values keep literals as written, while the formatted package
+	// source normalizes the case of prefixes and exponents like gofmt.
+	want := map[string]string{
+		"MyHexUpper":    "MyHexUpper = 0XFF",
+		"MyOctalUpper":  "MyOctalUpper = 0O644",
+		"MyLegacyOctal": "MyLegacyOctal = 0600",
+		"MyBinaryUpper": "MyBinaryUpper = 0B0101",
+		"MyMillion":     "MyMillion = 1_000_000",
+		"MyHexBytes":    "MyHexBytes = 0xFF_FF",
+		"MyFloat":       "MyFloat = 1_000.000_5",
+		"MyExponentUp":  "MyExponentUp = 6.022E23",
+		"MyHexFloat":    "MyHexFloat = 0x1p-2",
+		"MyTypedHex":    "MyTypedHex uint16 = 0xBEEF",
+	}
+
+	for _, cg := range pkg.Consts {
+		for _, c := range cg.Consts {
+			wantSpec, ok := want[c.Ident()]
+			if !ok {
+				continue
+			}
+
+			delete(want, c.Ident())
+
+			if c.Spec != wantSpec {
+				t.Errorf("expected %s spec to be %q, but got %q", c.Ident(), wantSpec, c.Spec)
+			}
+
+			if wantVal := strings.SplitN(wantSpec, " = ", 2)[1]; c.Values[0].Value != wantVal {
+				t.Errorf("expected %s value to be %q, but got %q", c.Ident(), wantVal, c.Values[0].Value)
+			}
+		}
+	}
+
+	for name := range want {
+		t.Errorf("expected const %s to be parsed", name)
+	}
+}
+
+func TestParser_Package_FuncResults(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "func_results.go"}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	src, err := pkg.Source()
+	if err != nil {
+		t.Fatalf("expected no error when getting source, but got: %v", err)
+	}
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		t.Fatalf("expected source to be valid Go code, but got: %v", err)
+	}
+
+	if string(formatted) != src {
+		t.Errorf("expected source to be gofmt formatted, but got:\n\n%s\n\nformatted:\n\n%s", src, formatted)
+	}
+
+	want := map[string]string{
+		"MyNamedFuncResult":       "func MyNamedFuncResult() (f func(int) int, err error)",
+		"MySingleNamedFuncResult": "func MySingleNamedFuncResult() (f func() error)",
+		"MySingleNamedResult":     "func MySingleNamedResult() (err error)",
+		"MyCurry":                 "func MyCurry(x int) func(int) func(string) (int, error)",
+	}
+
+	for _, f := range pkg.Funcs {
+		wantSig, ok := want[f.Name]
+		if !ok {
+			continue
+		}
+
+		f.Doc = ""
+
+		if sig := strings.TrimSpace(f.String()); sig != wantSig {
+			t.Errorf("expected %s signature %q, but got %q", f.Name, wantSig, sig)
+		}
+	}
+}
+
+func TestParser_Package_VarsJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourceFile string
+		opts       []pkgdmp.ParserOption
+		want       string
+	}{
+		{
+			name:       "all vars",
+			sourceFile: "vars.go",
+			want: `[{"doc":"Default settings.","vars":[` +
+				`{"doc":"DefaultName is the default name.","names":["DefaultName"]},` +
+				`{"doc":"DefaultSize is the default size.","names":["DefaultSize"],"type":"int"},` +
+				`{"names":["defaultTimeout"]}]},` +
+				`{"doc":"DefaultStruct is the default struct.","vars":[` +
+				`{"names":["DefaultStruct"]}]},` +
+				`{"doc":"ErrNotFound is returned when something is not found.","vars":[` +
+				`{"names":["ErrNotFound"]}]},` +
+				`{"doc":"MaxRetries is the maximum number of retries.","vars":[` +
+				`{"names":["MaxRetries","MinRetries"],"type":"int"}]},` +
+				`{"vars":[{"names":["internalCounter"],"type":"int"}]}]`,
+		},
+		{
+			name:       "exported vars",
+			sourceFile: "vars.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude))},
+			want: `[{"doc":"Default settings.","vars":[` +
+				`{"doc":"DefaultName is the default name.","names":["DefaultName"]},` +
+				`{"doc":"DefaultSize is the default size.","names":["DefaultSize"],"type":"int"}]},` +
+				`{"doc":"DefaultStruct is the default struct.","vars":[` +
+				`{"names":["DefaultStruct"]}]},` +
+				`{"doc":"ErrNotFound is returned when something is not found.","vars":[` +
+				`{"names":["ErrNotFound"]}]},` +
+				`{"doc":"MaxRetries is the maximum number of retries.","vars":[` +
+				`{"names":["MaxRetries","MinRetries"],"type":"int"}]}]`,
+		},
+		{
+			name:       "embed vars",
+			sourceFile: "embed_vars.go",
+			opts:       []pkgdmp.ParserOption{pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude))},
+			want: `[{"doc":"Embedded files.","vars":[` +
+				`{"doc":"Templates contains the HTML templates.","names":["Templates"],"type":"embed.FS",` +
+				`"embed":["templates/*.html","templates/partials/*.html"]},` +
+				`{"names":["Static"],"type":"embed.FS","embed":["\"static files\""]}]},` +
+				`{"doc":"Version is the version of the package.","vars":[` +
+				`{"names":["Version"],"type":"string","embed":["version.txt"]}]}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			tc := &parserTestCase{sourceFile: tt.sourceFile}
+
+			pkgParser, err := pkgdmp.NewParser(tt.opts...)
+			if err != nil {
+				t.Fatalf("expected no error when creating parser, but got: %v", err)
+			}
+
+			pkg, err := pkgParser.Package(tc.pkgDoc(t))
+			if err != nil {
+				t.Fatalf("expected no error when parsing package, but got: %v", err)
+			}
+
+			data, err := json.Marshal(pkg.Vars)
+			if err != nil {
+				t.Fatalf("expected no error when encoding vars, but got: %v", err)
+			}
+
+			if string(data) != tt.want {
+				t.Errorf("expected vars JSON:\n\n%s\n\nbut got:\n\n%s", tt.want, data)
+			}
+		})
+	}
+}
+
+func TestParser_Package_MultiNameFields(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "multi_name_fields.go"}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	if len(pkg.Types) != 1 {
+		t.Fatalf("expected 1 type, but got %d", len(pkg.Types))
+	}
+
+	fields := pkg.Types[0].Fields
+
+	if len(fields) != 3 {
+		t.Fatalf("expected 3 fields, but got %d", len(fields))
+	}
+
+	tests := []struct {
+		wantJSON string
+		wantSrc  string
+	}{
+		{
+			wantJSON: `{"type":"float64","comment":"coordinates.","names":["X","Y","Z"],` +
+				`"tags":[{"Name":"json","Values":["coord","omitempty"]},{"Name":"xml","Values":["coord"]}]}`,
+			wantSrc: "X, Y, Z float64 `json:\"coord,omitempty\" xml:\"coord\"` // coordinates.",
+		},
+		{
+			wantJSON: `{"type":"string","names":["Name","Label"],"tags":[{"Name":"json","Values":["name"]}]}`,
+			wantSrc:  "Name, Label string `json:\"name\"`",
+		},
+		{
+			wantJSON: `{"type":"bool","comment":"unexported shorthand fields.","names":["a","b"]}`,
+			wantSrc:  "a, b bool // unexported shorthand fields.",
+		},
+	}
+
+	for i, tt := range tests {
+		data, err := json.Marshal(fields[i])
+		if err != nil {
+			t.Fatalf("expected no error when encoding field, but got: %v", err)
+		}
+
+		if string(data) != tt.wantJSON {
+			t.Errorf("expected field %d JSON:\n\n%s\n\nbut got:\n\n%s", i, tt.wantJSON, data)
+		}
+
+		if src := strings.TrimSpace(fields[i].String()); src != tt.wantSrc {
+			t.Errorf("expected field %d source %q, but got %q", i, tt.wantSrc, src)
+		}
+	}
+}
+
+func TestParser_Package_QualifiedImports(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "qualified_imports.go"}
+
+	pkgParser, _ := pkgdmp.NewParser(pkgdmp.WithQualifiedImports())
+
+	dPkg, files := tc.pkgDocFiles(t)
+
+	pkg, err := pkgParser.Package(dPkg, files...)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	got := make(map[string]string)
+
+	for _, td := range pkg.Types {
+		got[td.Name] = td.Elt
+
+		for _, f := range append(td.Fields, td.Params...) {
+			got[td.Name+"."+f.Names[0]] = f.Type
+		}
+
+		for _, m := range td.Methods {
+			for _, p := range m.Params {
+				got[m.Name+"."+p.Names[0]] = p.Type
+			}
+		}
+	}
+
+	for _, f := range pkg.Funcs {
+		for _, p := range f.Params {
+			got[f.Name+"."+p.Names[0]] = p.Type
+		}
+	}
+
+	got["DefaultStore"] = pkg.Vars[0].Vars[0].Type
+
+	want := map[string]string{
+		"MyClient.HTTP":    "*net/http.Client",
+		"MyClient.Store":   "github.com/example/project/v2/store.Store",
+		"MyClient.Logger":  "github.com/example/logging.Logger",
+		"MyClient.Nodes":   "map[string][]*gopkg.in/yaml.v3.Node",
+		"MyClient.Log":     "*log.Logger",
+		"MyClient.Conn":    "*github.com/mattn/go-sqlite3.SQLiteConn",
+		"MyHandler.ctx":    "context.Context",
+		"MyHandler.ev":     "github.com/example/project/v2/store.Event",
+		"MyItems":          "github.com/example/project/v2/store.Item",
+		"NewMyClient.opts": "...github.com/example/project/v2/store.Option",
+		"Get.key":          "github.com/example/project/v2/store.Key",
+		"Decode.node":      "*gopkg.in/yaml.v3.Node",
+		"DefaultStore":     "github.com/example/project/v2/store.Store",
+	}
+
+	for name, wantType := range want {
+		if got[name] != wantType {
+			t.Errorf("expected type of %s to be %q, but got %q", name, wantType, got[name])
+		}
+	}
+}
+
+func TestParser_Package_QualifiedImportsFilters(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "qualified_imports.go"}
+
+	pkgParser, _ := pkgdmp.NewParser(
+		pkgdmp.WithQualifiedImports(),
+		pkgdmp.WithSymbolFilters(
+			pkgdmp.FilterMatchingTypes(pkgdmp.Exclude, regexp.MustCompile(`^\*stdlog\.Logger$|yaml\.Node`)),
+		),
+	)
+
+	dPkg, files := tc.pkgDocFiles(t)
+
+	pkg, err := pkgParser.Package(dPkg, files...)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	var fields []string
+
+	for _, td := range pkg.Types {
+		if td.Name != "MyClient" {
+			continue
+		}
+
+		for _, f := range td.Fields {
+			fields = append(fields, f.Names[0])
+		}
+
+		for _, m := range td.Methods {
+			if m.Name == "Decode" {
+				t.Errorf("expected Decode method to be excluded by its source signature")
+			}
+		}
+	}
+
+	want := []string{"HTTP", "Store", "Logger", "Conn"}
+
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("expected MyClient fields %v, but got %v", want, fields)
+	}
+}
+
+func TestParser_Package_GenericMethods(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "generic_methods.go"}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	want := map[string]string{
+		"MyPair.Set":   "*MyPair[K, V]",
+		"MyPair.Value": "*MyPair[_, V]",
+		"MyStack.Peek": "MyStack[T]",
+		"MyStack.Push": "*MyStack[T]",
+	}
+
+	n := 0
+
+	for _, td := range pkg.Types {
+		for _, m := range td.Methods {
+			n++
+
+			if rt := m.ReceiverType(); rt != td.Name {
+				t.Errorf("expected method %s to have receiver type %s, but got %s", m.Name, td.Name, rt)
+			}
+
+			if wantRecv := want[td.Name+"."+m.Name]; m.Receiver.Type != wantRecv {
+				t.Errorf("expected method %s.%s to have receiver %q, but got %q", td.Name, m.Name, wantRecv, m.Receiver.Type)
+			}
+		}
+	}
+
+	if n != len(want) {
+		t.Errorf("expected %d methods, but got %d", len(want), n)
+	}
+}
+
+func TestParser_Package_DocSections(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser(pkgdmp.WithDocSections())
+
+	pkg, err := pkgParser.Package(defaultDocPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	want := &pkgdmp.DocSections{
+		Params: []pkgdmp.DocParam{
+			{Name: "a", Doc: "The first integer to compare."},
+			{Name: "b", Doc: "The second integer to compare."},
+		},
+		Returns: "true if the integers are equal, false otherwise.",
+		Example: "result := MyFunction(5, 5) // result will be true\nresult := MyFunction(10, 20) // result will be false",
+	}
+
+	for _, f := range pkg.Funcs {
+		switch f.Name {
+		case "MyFunction":
+			if !reflect.DeepEqual(f.Sections, want) {
+				t.Errorf("expected MyFunction doc sections:\n\n%#v\n\nbut got:\n\n%#v", want, f.Sections)
+			}
+		default:
+			if f.Sections != nil {
+				t.Errorf("expected %s to have no doc sections, but got: %#v", f.Name, f.Sections)
+			}
+		}
+	}
+
+	tc := &parserTestCase{sourceFile: "doc_sections.go"}
+
+	pkg, err = pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	want = &pkgdmp.DocSections{
+		Params: []pkgdmp.DocParam{
+			{Name: "dst", Doc: "The destination writer."},
+			{Name: "src", Doc: "The source reader, which is read until EOF."},
+		},
+		Returns: "The number of bytes copied and any error.",
+	}
+
+	for _, f := range pkg.Funcs {
+		switch f.Name {
+		case "MyCopy":
+			if !reflect.DeepEqual(f.Sections, want) {
+				t.Errorf("expected MyCopy doc sections:\n\n%#v\n\nbut got:\n\n%#v", want, f.Sections)
+			}
+		case "MyPlainFunction":
+			if f.Sections != nil {
+				t.Errorf("expected MyPlainFunction to have no doc sections, but got: %#v", f.Sections)
+			}
+		}
+	}
+}
+
+func TestParser_Package_CharConsts(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "char_consts.go"}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	want := map[string]pkgdmp.Value{
+		"Letter":    {Value: `'a'`, Type: "rune"},
+		"Tab":       {Value: `'\t'`, Type: "rune"},
+		"Quote":     {Value: `'\''`, Type: "rune"},
+		"Accent":    {Value: `'é'`, Type: "rune"},
+		"Unicode":   {Value: `'\u00e9'`, Type: "rune"},
+		"Byte":      {Value: `'A'`, Type: "byte", Specific: true},
+		"EscByte":   {Value: `'\n'`, Type: "byte", Specific: true},
+		"TypedRune": {Value: `'z'`, Type: "rune", Specific: true},
+		"Newline":   {Value: `'\n'`, Type: "byte", Specific: true},
+	}
+
+	for _, cg := range pkg.Consts {
+		for _, c := range cg.Consts {
+			wantVal, ok := want[c.Ident()]
+			if !ok {
+				continue
+			}
+
+			delete(want, c.Ident())
+
+			if len(c.Values) != 1 || c.Values[0] != wantVal {
+				t.Errorf("expected %s values to be [%+v], but got %+v", c.Ident(), wantVal, c.Values)
+			}
+		}
+	}
+
+	for name := range want {
+		t.Errorf("expected const %s to be parsed", name)
+	}
+}
+
+func TestParser_Package_ConstExprValues(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "const_exprs.go"}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	want := map[string]pkgdmp.Value{
+		"KB":          {Value: "1 << 10", Type: "int"},
+		"GB":          {Value: "1 << (10 * 3)", Type: "int"},
+		"MyNegative":  {Value: "-1", Type: "int"},
+		"MySum":       {Value: "1 + 2.5", Type: "float64"},
+		"MyProduct":   {Value: "(2 * 3) + 'a'", Type: "rune"},
+		"MyComplex":   {Value: "2i * 2", Type: "complex128"},
+		"MyCompare":   {Value: "1 < 2", Type: "bool"},
+		"MyNot":       {Value: "!false", Type: "bool"},
+		"MyConcat":    {Value: `"hello" + " " + "world"`, Type: "string"},
+		"MyReference": {Value: "KB * 4", Type: ""},
+		"MyFlagA":     {Value: "1 << iota", Type: "MyFlag", Specific: true},
+	}
+
+	for _, cg := range pkg.Consts {
+		for _, c := range cg.Consts {
+			wantVal, ok := want[c.Ident()]
+			if !ok {
+				continue
+			}
+
+			delete(want, c.Ident())
+
+			if len(c.Values) != 1 {
+				t.Errorf("expected %s to have 1 value, but has %d", c.Ident(), len(c.Values))
+				continue
+			}
+
+			if c.Values[0] != wantVal {
+				t.Errorf("expected %s value to be %#v, but got %#v", c.Ident(), wantVal, c.Values[0])
+			}
+		}
+	}
+
+	for name := range want {
+		t.Errorf("expected const %s to be parsed", name)
+	}
+}
+
+func TestParser_Package_ConstCallValues(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "const_calls.go"}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	want := map[string]pkgdmp.Value{
+		"MyDuration":      {Value: "0", Type: "time.Duration", Specific: true},
+		"MyTimeout":       {Value: "time.Duration(30) * time.Second", Type: ""},
+		"MyNestedConv":    {Value: "int8(-1) + 1", Type: "uint8", Specific: true},
+		"MyImaginary":     {Value: "complex(0, 1)", Type: "complex128"},
+		"MyTypedComplex":  {Value: "complex(1, 2)", Type: "complex64", Specific: true},
+		"MyRealPart":      {Value: "real(3 + 4i)", Type: "float64"},
+		"MyImaginaryPart": {Value: "imag(MyImaginary)", Type: ""},
+		"MyLength":        {Value: `len("hello")`, Type: "int"},
+		"MySize":          {Value: "unsafe.Sizeof(MyLength)", Type: "uintptr"},
+	}
+
+	for _, cg := range pkg.Consts {
+		for _, c := range cg.Consts {
+			wantVal, ok := want[c.Ident()]
+			if !ok {
+				continue
+			}
+
+			delete(want, c.Ident())
+
+			if len(c.Values) != 1 {
+				t.Errorf("expected %s to have 1 value, but has %d", c.Ident(), len(c.Values))
+				continue
+			}
+
+			if c.Values[0] != wantVal {
+				t.Errorf("expected %s value to be %#v, but got %#v", c.Ident(), wantVal, c.Values[0])
+			}
+		}
+	}
+
+	for name := range want {
+		t.Errorf("expected const %s to be parsed", name)
+	}
+}
+
+func TestParser_Package_MixedConstValues(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "mixed_consts.go"}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	want := map[string][]pkgdmp.Value{
+		"MyUntyped":   {{Value: `"x"`, Type: "string"}},
+		"MyTyped":     {{Value: "2", Type: "int", Specific: true}},
+		"MyConverted": {{Value: "1 << 2", Type: "uint8", Specific: true}},
+		"MyIota":      {{Value: "iota", Type: "int"}},
+		"MyBool":      {{Value: "true", Type: "bool"}},
+		"MyAlias":     {{Value: "MyTyped", Type: ""}},
+		"MyTypedIota": {{Value: "iota + 1", Type: "MyMixedKind", Specific: true}},
+		"MyImplicit":  {},
+		"MyPair":      {{Value: "3", Type: "int", Specific: true}, {Value: "4", Type: "int", Specific: true}},
+	}
+
+	for _, cg := range pkg.Consts {
+		for _, c := range cg.Consts {
+			wantVals, ok := want[c.Ident()]
+			if !ok {
+				continue
+			}
+
+			delete(want, c.Ident())
+
+			if len(c.Values) != len(wantVals) {
+				t.Errorf("expected %s to have %d values, but has %d", c.Ident(), len(wantVals), len(c.Values))
+				continue
+			}
+
+			for i, v := range c.Values {
+				if v != wantVals[i] {
+					t.Errorf("expected %s value %d to be %#v, but got %#v", c.Ident(), i, wantVals[i], v)
+				}
+
+				// Structured values must agree with the rendered declaration.
+				if !strings.Contains(c.String(), v.Value) {
+					t.Errorf("expected %s declaration %q to contain value %q", c.Ident(), c.String(), v.Value)
+				}
+
+				if v.Specific && !strings.Contains(c.String(), v.Type) {
+					t.Errorf("expected %s declaration %q to contain type %q", c.Ident(), c.String(), v.Type)
+				}
+			}
+		}
+	}
+
+	for name := range want {
+		t.Errorf("expected const %s to be parsed", name)
+	}
+}
+
+func TestConstGroup_Enum(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "enums.go"}
+
+	// Unexported consts are excluded to verify that values of the remaining
+	// consts are evaluated with their iota in the declaration.
+	pkgParser, _ := pkgdmp.NewParser(pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude)))
+
+	pkg, err := pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	want := map[string]struct {
+		typ     string
+		members []pkgdmp.EnumMember
+	}{
+		"Red": {
+			typ: "Color",
+			members: []pkgdmp.EnumMember{
+				{Name: "Red", Value: "1", Doc: "Red is the color of blood."},
+				{Name: "Green", Value: "2", Doc: "Green is the color of grass."},
+				{Name: "Blue", Value: "3"},
+			},
+		},
+		"MyFlagA": {
+			typ: "MyFlag",
+			members: []pkgdmp.EnumMember{
+				{Name: "MyFlagA", Value: "1", Doc: "First flag."},
+				{Name: "MyFlagB", Value: "2", Doc: "Second flag."},
+				{Name: "MyFlagC", Value: "4", Doc: "Third flag."},
+			},
+		},
+	}
+
+	for _, cg := range pkg.Consts {
+		name := cg.Consts[0].Ident()
+
+		typ, members, ok := cg.Enum()
+
+		wantEnum, isEnum := want[name]
+		if !isEnum {
+			if ok {
+				t.Errorf("expected const group of %s not to be an enum, but got %s enum", name, typ)
+			}
+
+			continue
+		}
+
+		delete(want, name)
+
+		if !ok {
+			t.Errorf("expected const group of %s to be an enum", name)
+			continue
+		}
+
+		if typ != wantEnum.typ {
+			t.Errorf("expected enum type of %s to be %q, but got %q", name, wantEnum.typ, typ)
+		}
+
+		if !reflect.DeepEqual(members, wantEnum.members) {
+			t.Errorf("expected enum members of %s to be %#v, but got %#v", name, wantEnum.members, members)
+		}
+	}
+
+	for name := range want {
+		t.Errorf("expected const group of %s to be found", name)
+	}
+}
+
+func TestParser_Package_AnonymousTypes(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "anon_types.go"}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	if len(pkg.Types) != 1 || len(pkg.Types[0].Fields) != 2 {
+		t.Fatalf("expected 1 type with 2 fields, but got: %#v", pkg.Types)
+	}
+
+	server := pkg.Types[0].Fields[0]
+
+	if len(server.Fields) != 2 {
+		t.Fatalf("expected Server field to have 2 inline fields, but got: %#v", server.Fields)
+	}
+
+	if got := server.Fields[1]; got.Ident() != "Port" || got.Type != "int" || got.Comment != "server port." {
+		t.Errorf("expected inline field Port int with comment, but got: %#v", got)
+	}
+
+	if got := server.Fields[0].Tags; len(got) != 1 || got[0].String() != `json:"host"` {
+		t.Errorf("expected inline field Host to have json tag, but got: %#v", got)
+	}
+
+	if hooks := pkg.Types[0].Fields[1]; len(hooks.Fields) != 1 || hooks.Fields[0].Ident() != "Name" {
+		t.Errorf("expected Hooks field to have inline field Name, but got: %#v", hooks.Fields)
+	}
+
+	if len(pkg.Funcs) != 1 || len(pkg.Funcs[0].Params) != 2 {
+		t.Fatalf("expected 1 function with 2 params, but got: %#v", pkg.Funcs)
+	}
+
+	logger := pkg.Funcs[0].Params[1]
+
+	if len(logger.Methods) != 1 || logger.Methods[0].Name != "Log" || logger.Methods[0].Doc != "Log logs a message." {
+		t.Errorf("expected logger param to have inline method Log with doc, but got: %#v", logger.Methods)
+	}
+
+	if res := pkg.Funcs[0].Results[0]; len(res.Fields) != 1 || res.Fields[0].Ident() != "OK" {
+		t.Errorf("expected result to have inline field OK, but got: %#v", res.Fields)
+	}
+}
+
+func TestParser_Package_AnonymousTypesJSON(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "anon_types.go"}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(tc.pkgDoc(t))
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	if len(pkg.Funcs) != 1 || len(pkg.Funcs[0].Params) != 2 {
+		t.Fatalf("expected 1 function with 2 params, but got: %#v", pkg.Funcs)
+	}
+
+	data, err := json.Marshal(pkg.Funcs[0].Params[1])
+	if err != nil {
+		t.Fatalf("expected no error when encoding param, but got: %v", err)
+	}
+
+	want := `{"type":"interface{ Log(msg string) error }","names":["logger"],` +
+		`"methods":[{"name":"Log","doc":"Log logs a message.",` +
+		`"params":[{"type":"string","names":["msg"]}],"results":[{"type":"error"}]}]}`
+
+	if string(data) != want {
+		t.Errorf("expected param JSON:\n\n%s\n\nbut got:\n\n%s", want, data)
+	}
+
+	var decoded pkgdmp.Field
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("expected no error when decoding param, but got: %v", err)
+	}
+
+	if len(decoded.Methods) != 1 || decoded.Methods[0].Name != "Log" || len(decoded.Methods[0].Params) != 1 {
+		t.Errorf("expected decoded param to have inline method Log, but got: %#v", decoded.Methods)
+	}
+
+	wantSig := "func MyConfigure(opts struct{ A int }, logger interface{ Log(msg string) error }) struct{ OK bool }"
+	f := pkg.Funcs[0]
+	f.Doc = ""
+
+	if sig := strings.TrimSpace(f.String()); sig != wantSig {
+		t.Errorf("expected inline signature %q, but got %q", wantSig, sig)
+	}
+}
+
+func TestParser_Package_MethodSymbolTypes(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(defaultDocPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	for _, td := range pkg.Types {
+		for _, m := range td.Methods {
+			if m.SymbolType() != pkgdmp.SymbolMethod {
+				t.Errorf("expected method %s.%s to have symbol type %v, but got %v",
+					td.Name, m.Name, pkgdmp.SymbolMethod, m.SymbolType(),
+				)
+			}
+		}
+	}
+
+	for _, f := range pkg.Funcs {
+		if f.SymbolType() != pkgdmp.SymbolFunc {
+			t.Errorf("expected function %s to have symbol type %v, but got %v",
+				f.Name, pkgdmp.SymbolFunc, f.SymbolType(),
+			)
+		}
+	}
+}
+
+func TestParser_Package_ExcludeMethodSymbols(t *testing.T) {
+	sources := make([]string, 2)
+
+	for i, opt := range []pkgdmp.ParserOption{
+		pkgdmp.WithSymbolFilters(pkgdmp.FilterSymbolTypes(pkgdmp.Exclude, pkgdmp.SymbolMethod)),
+		pkgdmp.WithNoMethods(),
+	} {
+		pkgParser, _ := pkgdmp.NewParser(opt)
+
+		pkg, err := pkgParser.Package(defaultDocPkg)
+		if err != nil {
+			t.Fatalf("expected no error when parsing package, but got: %v", err)
+		}
+
+		if sources[i], err = pkg.Source(); err != nil {
+			t.Fatalf("expected no error when getting source, but got: %v", err)
+		}
+	}
+
+	if sources[0] != sources[1] {
+		t.Errorf("expected excluding method symbols to render like %s:\n\n%s\nbut got:\n\n%s",
+			pkgdmp.WithNoMethods(), sources[1], sources[0],
+		)
+	}
+}
+
+func TestParser_Package_BlankLines(t *testing.T) {
+	typeFilter := pkgdmp.FilterSymbolTypes(pkgdmp.Exclude,
+		pkgdmp.SymbolIdentType, pkgdmp.SymbolFuncType, pkgdmp.SymbolStructType, pkgdmp.SymbolInterfaceType,
+	)
+
+	for _, opts := range [][]pkgdmp.ParserOption{
+		{pkgdmp.WithSymbolFilters(typeFilter)},
+		{pkgdmp.WithSymbolFilters(typeFilter), pkgdmp.WithGroupByKind()},
+	} {
+		pkgParser, _ := pkgdmp.NewParser(opts...)
+
+		pkg, err := pkgParser.Package(defaultDocPkg)
+		if err != nil {
+			t.Fatalf("expected no error when parsing package, but got: %v", err)
+		}
+
+		if len(pkg.Types) != 0 || len(pkg.Consts) == 0 || len(pkg.Funcs) == 0 {
+			t.Fatalf("expected package with only consts and funcs, but got %d types, %d consts, and %d funcs",
+				len(pkg.Types), len(pkg.Consts), len(pkg.Funcs),
+			)
+		}
+
+		// Const groups without consts write no code.
+		pkg.Consts = append([]pkgdmp.ConstGroup{{Doc: "Empty group."}}, pkg.Consts...)
+		pkg.Consts = append(pkg.Consts, pkgdmp.ConstGroup{})
+
+		if got := pkg.String(); strings.Contains(got, "\n\n\n") {
+			t.Errorf("expected at most one consecutive blank line with %v, but got:\n\n%s", opts, got)
+		}
+	}
+}
+
+func TestParser_Package_Examples(t *testing.T) {
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, 2)
+
+	for name, src := range map[string]string{
+		"mypackage.go": `// Package mypackage is an example package.
+package mypackage
+
+// MyType is an example type.
+type MyType struct{}
+
+// MyFunc is an example function.
+func MyFunc() int { return 1 }
+`,
+		"example_test.go": `package mypackage_test
+
+import (
+	"fmt"
+
+	"example.com/mypackage"
+)
+
+func Example() {
+	fmt.Println("package")
+}
+
+// Calling MyFunc.
+func ExampleMyFunc() {
+	// Print the result.
+	fmt.Println(mypackage.MyFunc())
+	// Output: 1
+}
+
+func ExampleMyType_second() {
+	_ = mypackage.MyType{}
+}
+`,
+	} {
+		file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("expected no error when parsing %s, but got: %v", name, err)
+		}
+
+		files = append(files, file)
+	}
+
+	dPkg, err := doc.NewFromFiles(fset, files, "example.com/mypackage", doc.AllDecls|doc.PreserveAST)
+	if err != nil {
+		t.Fatalf("expected no error when creating doc package, but got: %v", err)
+	}
+
+	pkgParser, err := pkgdmp.NewParser(pkgdmp.WithExamples(fset))
+	if err != nil {
+		t.Fatalf("expected no error when creating parser, but got: %v", err)
+	}
+
+	pkg, err := pkgParser.Package(dPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	if len(pkg.Funcs) != 1 || len(pkg.Funcs[0].Examples) != 1 {
+		t.Fatalf("expected one function with one example, but got: %+v", pkg.Funcs)
+	}
+
+	want := pkgdmp.Example{
+		Doc:    "Calling MyFunc.",
+		Code:   "// Print the result.\nfmt.Println(mypackage.MyFunc())\n// Output: 1",
+		Output: "1\n",
+	}
+
+	if got := pkg.Funcs[0].Examples[0]; got != want {
+		t.Errorf("expected example:\n\n%+v\n\nbut got:\n\n%+v", want, got)
+	}
+
+	src, err := pkg.Source()
+	if err != nil {
+		t.Fatalf("expected no error when getting package source, but got: %v", err)
+	}
+
+	for _, want := range []string{
+		"// Example:\n//\n//\tfmt.Println(\"package\")\npackage mypackage",
+		"// Example:\n//\n// Calling MyFunc.\n//\n//\t// Print the result.\n//\tfmt.Println(mypackage.MyFunc())\n//\t// Output: 1\nfunc MyFunc() int",
+		"// Example (second):\n//\n//\t_ = mypackage.MyType{}\ntype MyType struct{}",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected source to contain:\n\n%s\n\nbut got:\n\n%s", want, src)
+		}
+	}
+
+	pkgParser, _ = pkgdmp.NewParser()
+
+	if pkg, _ = pkgParser.Package(dPkg); len(pkg.Examples) != 0 {
+		t.Errorf("expected no examples without WithExamples, but got %d", len(pkg.Examples))
+	}
+}
+
+func TestParser_Package_FuncKeyword(t *testing.T) {
+	pkg := parseSource(t, `package mypackage
+
+type MyInterface interface {
+	MyMethod(n int) error
+}
+
+type MyType struct{}
+
+func (t MyType) MyMethod(n int) error { return nil }
+
+func MyFunc(n int) error { return nil }
+`)
+
+	iface, typ := pkg.Types[0], pkg.Types[1]
+
+	tt := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "function", got: pkg.Funcs[0].String(), want: "func MyFunc(n int) error"},
+		{name: "method", got: typ.Methods[0].String(), want: "func (t MyType) MyMethod(n int) error"},
+		{name: "interface method", got: iface.Methods[0].String(), want: "MyMethod(n int) error"},
+		{name: "interface type", got: iface.String(), want: "type MyInterface interface {\n\tMyMethod(n int) error\n}"},
+		{name: "function without parser", got: pkgdmp.Func{Name: "MyFunc"}.String(), want: "func MyFunc()"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			// Signatures are unformatted code with a space before results.
+			if got := strings.ReplaceAll(strings.TrimSpace(tc.got), "  ", " "); got != tc.want {
+				t.Errorf("expected:\n\n%s\n\nbut got:\n\n%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPackage_Print_Flags(t *testing.T) {
+	pkg := &pkgdmp.Package{
+		Name: "main",
+		Doc:  "Command mycommand does things.",
+		Flags: []pkgdmp.CommandFlag{
+			{Name: "name", Type: "string", Default: `"world"`, Usage: "name to greet"},
+			{Name: "n", Type: "int", Default: "0"},
+			{Name: "v", Type: "bool", Default: "false", Usage: "enable verbose output"},
+		},
+	}
+
+	want := `// Command mycommand does things.
+//
+// Flags:
+//
+//	-name string
+//	    name to greet (default "world")
+//	-n int
+//	-v
+//	    enable verbose output
+package main
+`
+
+	if got := pkg.String(); got != want {
+		t.Errorf("expected:\n\n%s\n\nbut got:\n\n%s", want, got)
+	}
+}
+
+func TestParser_Package_QualifiedNames(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser(
+		pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude)),
+	)
+
+	pkg, err := pkgParser.Package(defaultDocPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	var qualified []string
+
+	for _, td := range pkg.Types {
+		qualified = append(qualified, td.Qualified)
+
+		for _, m := range td.Methods {
+			qualified = append(qualified, m.Qualified)
+		}
+	}
+
+	for _, f := range pkg.Funcs {
+		qualified = append(qualified, f.Qualified)
+	}
+
+	want := []string{
+		"mypackage.MyExportedType",
+		"mypackage.MyFunctionType",
+		"mypackage.MyInterface",
+		"mypackage.MyInterface.MyMethod",
+		"mypackage.MyLogLevel",
+		"mypackage.MyStruct",
+		"mypackage.MyStruct.MyMethod",
+		"mypackage.MyThirdFunction",
+		"mypackage.NewMyStruct",
+		"mypackage.MyFunction",
+		"mypackage.MyOtherFunction",
+	}
+
+	if got, exp := strings.Join(qualified, "\n"), strings.Join(want, "\n"); got != exp {
+		t.Errorf("expected qualified names:\n\n%s\n\nbut got:\n\n%s", exp, got)
+	}
+
+	data, err := json.Marshal(pkg.Types[0])
+	if err != nil {
+		t.Fatalf("expected no error when encoding type, but got: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"qualified":"mypackage.MyExportedType"`) {
+		t.Errorf("expected JSON to contain qualified name, but got: %s", data)
+	}
+}
+
+func TestParser_FilterResults(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser(
+		pkgdmp.WithFilterResults(),
+		pkgdmp.WithSymbolFilters(
+			pkgdmp.FilterUnexported(pkgdmp.Exclude),
+			pkgdmp.FilterSymbolTypes(pkgdmp.Include, pkgdmp.SymbolFunc, pkgdmp.SymbolParamField, pkgdmp.SymbolResultField),
+		),
+	)
+
+	for i := 0; i < 2; i++ {
+		pkg, err := pkgParser.Package(defaultDocPkg)
+		if err != nil {
+			t.Fatalf("expected no error when parsing package, but got: %v", err)
+		}
+
+		var included, excluded int
+
+		for _, r := range pkgParser.FilterResults() {
+			if r.Symbol.SymbolType() != pkgdmp.SymbolFunc {
+				continue
+			}
+
+			if r.Included {
+				included++
+			} else {
+				excluded++
+			}
+		}
+
+		if included != len(pkg.Funcs) {
+			t.Errorf("expected %d included funcs, but got %d", len(pkg.Funcs), included)
+		}
+
+		if excluded == 0 {
+			t.Error("expected unexported funcs to be recorded as excluded")
+		}
+	}
+}
+
+func TestParser_FilterResults_NotRecorded(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser()
+
+	if _, err := pkgParser.Package(defaultDocPkg); err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	if n := len(pkgParser.FilterResults()); n != 0 {
+		t.Errorf("expected no filter results without option, but got %d", n)
+	}
+}
+
+func TestParser_Warnings(t *testing.T) {
+	tc := &parserTestCase{sourceFile: "unsupported.go"}
+	pkgParser, _ := pkgdmp.NewParser()
+
+	if _, err := pkgParser.Package(tc.pkgDoc(t)); err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	warnings := pkgParser.Warnings()
+
+	want := []string{
+		"MyNumber: unsupported *ast.BinaryExpr",
+		"MyPointer: unsupported *ast.StarExpr",
+	}
+
+	if len(warnings) != len(want) {
+		t.Fatalf("expected %d warnings, but got %d: %v", len(want), len(warnings), warnings)
+	}
+
+	for i, w := range warnings {
+		var uErr *pkgdmp.UnsupportedError
+
+		if !errors.As(w, &uErr) {
+			t.Errorf("expected warning %d to be *pkgdmp.UnsupportedError, but got %T", i, w)
+			continue
+		}
+
+		if !uErr.Pos.IsValid() {
+			t.Errorf("expected warning %d to have a valid position", i)
+		}
+
+		if w.Error() != want[i] {
+			t.Errorf("expected warning %d to be %q, but got %q", i, want[i], w.Error())
+		}
+	}
+
+	if _, err := pkgParser.Package(defaultDocPkg); err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	if n := len(pkgParser.Warnings()); n != 0 {
+		t.Errorf("expected warnings to be reset for next package, but got %d", n)
+	}
+}
+
+func TestParser_TypedPackage(t *testing.T) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filepath.Join("testdata", "source", "underlying.go"), nil, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("error parsing source: %v", err)
+	}
+
+	tPkg, err := (&types.Config{}).Check(defaultPkgName, fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("error type-checking source: %v", err)
+	}
+
+	dPkg, err := doc.NewFromFiles(fset, []*ast.File{file}, defaultPkgName, doc.AllDecls|doc.PreserveAST)
+	if err != nil {
+		t.Fatalf("error creating doc package: %v", err)
+	}
+
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.TypedPackage(dPkg, tPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	for _, td := range pkg.Types {
+		if td.Underlying != "" {
+			t.Errorf("expected no underlying type of %s without WithResolveUnderlying, but got %q", td.Name, td.Underlying)
+		}
+	}
+
+	pkgParser, _ = pkgdmp.NewParser(pkgdmp.WithResolveUnderlying())
+
+	pkg, err = pkgParser.TypedPackage(dPkg, tPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	want := map[string]string{
+		"MyA":        "int",
+		"MyB":        "int",
+		"MyC":        "",
+		"MyCelsius":  "",
+		"MyList":     "",
+		"MyNames":    "[]string",
+		"MyPoint":    "",
+		"MyPosition": "struct{X int; Y int}",
+	}
+
+	if len(pkg.Types) != len(want) {
+		t.Fatalf("expected %d types, but got %d", len(want), len(pkg.Types))
+	}
+
+	for _, td := range pkg.Types {
+		if td.Underlying != want[td.Name] {
+			t.Errorf("expected underlying type of %s to be %q, but got %q", td.Name, want[td.Name], td.Underlying)
+		}
+	}
+}
+
+func TestParser_UnmarshalPackages(t *testing.T) {
+	opts := []pkgdmp.ParserOption{
+		pkgdmp.WithNoReceiverNames(),
+		pkgdmp.WithImports(),
+		pkgdmp.WithWrap(60),
+		pkgdmp.WithSymbolFilters(),
+		pkgdmp.WithQualifiedImports(),
+	}
+
+	for _, sourceFile := range []string{"", "anon_types.go", "generics.go", "iface_docs.go", "const_exprs.go", "multi_name_fields.go", "enums.go", "vars.go", "qualified_imports.go"} {
+		tc := &parserTestCase{sourceFile: sourceFile}
+
+		t.Run(fmt.Sprintf("round trips %q", sourceFile), func(t *testing.T) {
+			pkgParser, _ := pkgdmp.NewParser(opts...)
+
+			dPkg, files := tc.pkgDocFiles(t)
+
+			pkg, err := pkgParser.Package(dPkg, files...)
+			if err != nil {
+				t.Fatalf("expected no error when parsing package, but got: %v", err)
+			}
+
+			data, err := pkgParser.MarshalPackages([]*pkgdmp.Package{pkg})
+			if err != nil {
+				t.Fatalf("expected no error when encoding package, but got: %v", err)
+			}
+
+			pkgs, err := pkgParser.UnmarshalPackages(data)
+			if err != nil {
+				t.Fatalf("expected no error when decoding package, but got: %v", err)
+			}
+
+			if len(pkgs) != 1 {
+				t.Fatalf("expected 1 decoded package, but got %d", len(pkgs))
+			}
+
+			want, err := pkg.Source()
+			if err != nil {
+				t.Fatalf("expected no error when getting source, but got: %v", err)
+			}
+
+			actual, err := pkgs[0].Source()
+			if err != nil {
+				t.Fatalf("expected no error when getting decoded package source, but got: %v", err)
+			}
+
+			if actual != want {
+				t.Errorf("expected decoded package source:\n\n%s\nbut got:\n\n%s", want, actual)
+			}
+
+			redata, err := pkgParser.MarshalPackages(pkgs)
+			if err != nil {
+				t.Fatalf("expected no error when re-encoding decoded package, but got: %v", err)
+			}
+
+			if string(redata) != string(data) {
+				t.Errorf("expected re-encoded package to equal encoded package:\n\n%s\nbut got:\n\n%s", data, redata)
+			}
+		})
+	}
+}
+
+func TestParser_UnmarshalPackages_JSONArray(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser()
+
+	for _, sourceFile := range []string{"const_exprs.go", "vars.go"} {
+		t.Run(sourceFile, func(t *testing.T) {
+			pkg, err := pkgParser.Package((&parserTestCase{sourceFile: sourceFile}).pkgDoc(t))
+			if err != nil {
+				t.Fatalf("expected no error when parsing package, but got: %v", err)
+			}
+
+			data, err := json.Marshal([]*pkgdmp.Package{pkg})
+			if err != nil {
+				t.Fatalf("expected no error when encoding package, but got: %v", err)
+			}
+
+			pkgs, err := pkgParser.UnmarshalPackages(data)
+			if err != nil {
+				t.Fatalf("expected no error when decoding package, but got: %v", err)
+			}
+
+			if len(pkgs) != 1 {
+				t.Fatalf("expected 1 decoded package, but got %d", len(pkgs))
+			}
+
+			for _, cg := range pkgs[0].Consts {
+				for _, c := range cg.Consts {
+					if !strings.HasPrefix(c.Spec, strings.Join(c.Names, ", ")) {
+						t.Errorf("expected rebuilt spec of %s to start with its names, but got %q", c.Ident(), c.Spec)
+					}
+				}
+			}
+
+			for _, vg := range pkgs[0].Vars {
+				for _, v := range vg.Vars {
+					if !strings.HasPrefix(v.Spec, strings.Join(v.Names, ", ")) {
+						t.Errorf("expected rebuilt spec of %s to start with its names, but got %q", v.Ident(), v.Spec)
+					}
+				}
+			}
+
+			if _, err := pkgs[0].Source(); err != nil {
+				t.Errorf("expected no error when getting decoded package source, but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestParser_UnmarshalPackages_InvalidJSON(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser()
+
+	if _, err := pkgParser.UnmarshalPackages([]byte(`{"name":`)); err == nil {
+		t.Error("expected error when decoding invalid JSON, but got nil")
+	}
+}
+
+func (tc *parserTestCase) run(tb *testing.T) {
+	tb.Helper()
+
+	pkgParser, _ := pkgdmp.NewParser(tc.opts...)
+
+	dPkg, files := tc.pkgDocFiles(tb)
+
+	pkg, err := pkgParser.Package(dPkg, files...)
+	if err != nil {
+		tb.Errorf("expected no error when parsing package, but got: %v", err)
+	}
+
+	tc.compareGolden(tb, pkg)
+}
+
+func (tc *parserTestCase) pkgDoc(tb testing.TB) *doc.Package {
+	tb.Helper()
+
+	dPkg, _ := tc.pkgDocFiles(tb)
+
+	return dPkg
+}
+
+// pkgDocFiles returns the documentation of the test case's source package and
+// the syntax trees of its files, which are nil for the default package.
+func (tc *parserTestCase) pkgDocFiles(tb testing.TB) (*doc.Package, []*ast.File) {
+	tb.Helper()
+
+	if tc.sourceFile == "" {
+		return defaultDocPkg, nil
+	}
+
+	tDir := tb.TempDir()
+
+	src, err := os.Open(filepath.Join("testdata", "source", tc.sourceFile))
+	if err != nil {
+		tb.Fatalf("error opening source file: %v", err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(filepath.Join(tDir, "file.go"))
+	if err != nil {
+		tb.Fatalf("error creating temporary source file: %v", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		tb.Fatalf("error copying source file content to temporary file")
+	}
+
+	fset := token.NewFileSet()
+
+	pkgMap, err := parser.ParseDir(fset, tDir, nil, parser.ParseComments)
+	if err != nil {
+		tb.Fatalf("error parsing source: %v", err)
+	}
+
+	pkg, ok := pkgMap[defaultPkgName]
+	if !ok {
+		tb.Fatalf("expected source to specify package %q", defaultPkgName)
+	}
+
+	files := make([]*ast.File, 0, len(pkg.Files))
+
+	for _, f := range pkg.Files {
+		files = append(files, f)
 	}
 
-	return doc.New(pkg, "", doc.AllDecls)
+	return doc.New(pkg, "", doc.AllDecls|doc.PreserveAST), files
 }
 
 func (tc *parserTestCase) compareGolden(tb testing.TB, pkg *pkgdmp.Package) {
@@ -340,5 +2194,5 @@ func initDefaultDocPkg() {
 		panic(fmt.Errorf("default source file does not specify expected %q package", defaultPkgName))
 	}
 
-	defaultDocPkg = doc.New(pkg, "", doc.AllDecls)
+	defaultDocPkg = doc.New(pkg, "", doc.AllDecls|doc.PreserveAST)
 }
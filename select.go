@@ -0,0 +1,129 @@
+package pkgdmp
+
+import "strings"
+
+// Select returns a copy of the package with only the symbols named by
+// selectors.
+//
+// A selector is the name of a const, var, function, or type, or the name of a
+// type and one of its methods separated by a dot, e.g. `MyStruct.MyMethod`.
+// Types selected by name are included with all of their methods, while types
+// selected by method are included with the selected methods only. Interface
+// types are always included with all of their methods, as they are part of
+// the type's declaration.
+//
+// Select returns false if the package has none of the selected symbols.
+func (p *Package) Select(selectors ...string) (*Package, bool) {
+	names := make(map[string]struct{}, len(selectors))
+	methods := make(map[string]map[string]struct{})
+
+	for _, sel := range selectors {
+		typ, method, ok := strings.Cut(sel, ".")
+		if !ok {
+			names[sel] = struct{}{}
+			continue
+		}
+
+		if methods[typ] == nil {
+			methods[typ] = make(map[string]struct{})
+		}
+
+		methods[typ][method] = struct{}{}
+	}
+
+	// selected returns true if the function or method f is selected.
+	selected := func(f Func) bool {
+		rt := f.ReceiverType()
+		if rt == "" {
+			_, ok := names[f.Name]
+			return ok
+		}
+
+		if _, ok := names[rt]; ok {
+			return true
+		}
+
+		_, ok := methods[rt][f.Name]
+
+		return ok
+	}
+
+	res := &Package{
+		Name:     p.Name,
+		Header:   p.Header,
+		Doc:      p.Doc,
+		Imports:  p.Imports,
+		printCfg: p.printCfg,
+	}
+
+	for _, cg := range p.Consts {
+		var consts []Const
+
+		for _, c := range cg.Consts {
+			for _, n := range c.Names {
+				if _, ok := names[n]; ok {
+					consts = append(consts, c)
+					break
+				}
+			}
+		}
+
+		if len(consts) != 0 {
+			cg.Consts = consts
+			res.Consts = append(res.Consts, cg)
+		}
+	}
+
+	for _, vg := range p.Vars {
+		var vars []Var
+
+		for _, v := range vg.Vars {
+			for _, n := range v.Names {
+				if _, ok := names[n]; ok {
+					vars = append(vars, v)
+					break
+				}
+			}
+		}
+
+		if len(vars) != 0 {
+			vg.Vars = vars
+			res.Vars = append(res.Vars, vg)
+		}
+	}
+
+	for _, td := range p.Types {
+		_, byName := names[td.Name]
+		_, byMethod := methods[td.Name]
+
+		if !byName && !byMethod {
+			continue
+		}
+
+		if !byName && td.Type != "interface" {
+			var tdMethods []Func
+
+			for _, m := range td.Methods {
+				if selected(m) {
+					tdMethods = append(tdMethods, m)
+				}
+			}
+
+			td.Methods = tdMethods
+		}
+
+		res.Types = append(res.Types, td)
+	}
+
+	for _, f := range p.Funcs {
+		if selected(f) {
+			res.Funcs = append(res.Funcs, f)
+		}
+	}
+
+	if res.IsEmpty() {
+		return nil, false
+	}
+
+	return res, true
+}
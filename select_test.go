@@ -0,0 +1,106 @@
+package pkgdmp_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+func TestPackage_Select(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(defaultDocPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		selectors  []string
+		wantConsts []string
+		wantTypes  []string
+		wantFuncs  []string
+	}{
+		{
+			name:      "type with methods",
+			selectors: []string{"MyStruct"},
+			wantTypes: []string{"MyStruct"},
+			wantFuncs: []string{"MyStruct.MyMethod", "MyStruct.myUnexportedMethod"},
+		},
+		{
+			name:      "method",
+			selectors: []string{"MyStruct.MyMethod"},
+			wantTypes: []string{"MyStruct"},
+			wantFuncs: []string{"MyStruct.MyMethod"},
+		},
+		{
+			name:      "interface method",
+			selectors: []string{"MyInterface.MyMethod"},
+			wantTypes: []string{"MyInterface"},
+			wantFuncs: []string{"MyInterface.MyMethod"},
+		},
+		{
+			name:       "functions and consts",
+			selectors:  []string{"MyFunction", "NewMyStruct", "MyFatal", "MyIntConst"},
+			wantConsts: []string{"MyStringConst", "MyFatal"},
+			wantFuncs:  []string{"NewMyStruct", "MyFunction"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, ok := pkg.Select(tt.selectors...)
+			if !ok {
+				t.Fatalf("expected symbols %v to be selected", tt.selectors)
+			}
+
+			var consts, types, funcs []string
+
+			for _, cg := range selected.Consts {
+				for _, c := range cg.Consts {
+					consts = append(consts, c.Ident())
+				}
+			}
+
+			for _, td := range selected.Types {
+				types = append(types, td.Name)
+
+				for _, m := range td.Methods {
+					funcs = append(funcs, td.Name+"."+m.Name)
+				}
+			}
+
+			for _, f := range selected.Funcs {
+				funcs = append(funcs, f.Name)
+			}
+
+			if !reflect.DeepEqual(consts, tt.wantConsts) {
+				t.Errorf("expected consts %v, but got %v", tt.wantConsts, consts)
+			}
+
+			if !reflect.DeepEqual(types, tt.wantTypes) {
+				t.Errorf("expected types %v, but got %v", tt.wantTypes, types)
+			}
+
+			if !reflect.DeepEqual(funcs, tt.wantFuncs) {
+				t.Errorf("expected funcs %v, but got %v", tt.wantFuncs, funcs)
+			}
+		})
+	}
+}
+
+func TestPackage_Select_NotFound(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(defaultDocPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	for _, sel := range []string{"MyNonExistentSymbol", "MyNonExistentType.MyMethod"} {
+		if _, ok := pkg.Select(sel); ok {
+			t.Errorf("expected no symbols to be selected by %s", sel)
+		}
+	}
+}
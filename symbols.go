@@ -0,0 +1,395 @@
+package pkgdmp
+
+import (
+	"go/format"
+	"io"
+	"strings"
+)
+
+// symbolKinds maps symbol types to the kind names used in [FlatSymbol].
+var symbolKinds = map[SymbolType]string{
+	SymbolConst:         "const",
+	SymbolVar:           "var",
+	SymbolIdentType:     "identType",
+	SymbolFuncType:      "funcType",
+	SymbolStructType:    "struct",
+	SymbolInterfaceType: "interface",
+	SymbolMapType:       "mapType",
+	SymbolChanType:      "chanType",
+	SymbolArrayType:     "arrayType",
+	SymbolFunc:          "func",
+	SymbolMethod:        "method",
+}
+
+// kindOrder is the order of symbol kinds when grouping symbols by kind.
+var kindOrder = []SymbolType{
+	SymbolConst,
+	SymbolStructType,
+	SymbolInterfaceType,
+	SymbolIdentType,
+	SymbolFuncType,
+	SymbolMapType,
+	SymbolChanType,
+	SymbolArrayType,
+	SymbolFunc,
+	SymbolMethod,
+}
+
+// kindLabels maps symbol types to section labels when grouping symbols by
+// kind.
+var kindLabels = map[SymbolType]string{
+	SymbolConst:         "Constants",
+	SymbolVar:           "Variables",
+	SymbolStructType:    "Structs",
+	SymbolInterfaceType: "Interfaces",
+	SymbolIdentType:     "Types",
+	SymbolFuncType:      "Function types",
+	SymbolMapType:       "Map types",
+	SymbolChanType:      "Channel types",
+	SymbolArrayType:     "Array types",
+	SymbolFunc:          "Functions",
+	SymbolMethod:        "Methods",
+}
+
+// GroupedPackage represents a package with its symbols grouped by kind.
+type GroupedPackage struct {
+	Name    string              `json:"name"`
+	Doc     string              `json:"doc,omitempty"`
+	Imports []string            `json:"imports,omitempty"`
+	Symbols map[string][]Symbol `json:"symbols"`
+}
+
+// GroupByKind returns the package with its consts, vars, type definitions,
+// and functions grouped by kind, e.g. `struct` and `interface`.
+//
+// Methods of type definitions stay with their type.
+func (p *Package) GroupByKind() *GroupedPackage {
+	gp := &GroupedPackage{
+		Name:    p.Name,
+		Doc:     p.Doc,
+		Imports: p.Imports,
+		Symbols: make(map[string][]Symbol),
+	}
+
+	add := func(s Symbol) {
+		kind := symbolKinds[s.SymbolType()]
+		gp.Symbols[kind] = append(gp.Symbols[kind], s)
+	}
+
+	for _, cg := range p.Consts {
+		for _, c := range cg.Consts {
+			if c.Doc == "" {
+				c.Doc = cg.Doc
+			}
+
+			add(c)
+		}
+	}
+
+	for _, vg := range p.Vars {
+		for _, v := range vg.Vars {
+			if v.Doc == "" {
+				v.Doc = vg.Doc
+			}
+
+			add(v)
+		}
+	}
+
+	for _, td := range p.Types {
+		add(td)
+	}
+
+	for _, f := range p.Funcs {
+		add(f)
+	}
+
+	return gp
+}
+
+// FlatSymbol represents a package symbol in a flat list of symbols.
+type FlatSymbol struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Package   string `json:"package"`
+	Signature string `json:"signature"`
+	Doc       string `json:"doc,omitempty"`
+}
+
+// Symbols returns a flat list of the package's consts, vars, types,
+// functions, and methods.
+//
+// Methods are named after their receiver type, e.g. `MyStruct.MyMethod`.
+func (p *Package) Symbols() []FlatSymbol {
+	cfg := p.printConfig()
+
+	var res []FlatSymbol
+
+	for _, cg := range p.Consts {
+		for _, c := range cg.Consts {
+			sig := "const " + c.signature()
+			doc := cg.Doc
+
+			if c.Doc != "" {
+				doc = c.Doc
+			}
+
+			for _, name := range c.Names {
+				res = append(res, p.flatSymbol(c, name, sig, doc))
+			}
+		}
+	}
+
+	for _, vg := range p.Vars {
+		for _, v := range vg.Vars {
+			sig := "var " + v.signature()
+			doc := vg.Doc
+
+			if v.Doc != "" {
+				doc = v.Doc
+			}
+
+			for _, name := range v.Names {
+				res = append(res, p.flatSymbol(v, name, sig, doc))
+			}
+		}
+	}
+
+	for _, td := range p.Types {
+		sigTd := td
+		sigTd.Doc = ""
+
+		if td.Type != "interface" {
+			sigTd.Methods = nil
+		}
+
+		res = append(res, p.flatSymbol(td, td.Name, signature(func(w io.Writer) { sigTd.print(w, cfg) }), td.Doc))
+
+		if td.Type == "interface" {
+			continue
+		}
+
+		for _, m := range td.Methods {
+			res = append(res, p.flatFunc(m, td.Name+"."+m.Name, cfg))
+		}
+	}
+
+	for _, f := range p.Funcs {
+		name := f.Name
+		if f.Receiver != nil {
+			name = f.ReceiverType() + "." + name
+		}
+
+		res = append(res, p.flatFunc(f, name, cfg))
+	}
+
+	return res
+}
+
+// PackageStats holds counts of the symbols in a package.
+type PackageStats struct {
+	Consts   int `json:"consts"`
+	Vars     int `json:"vars"`
+	Types    int `json:"types"`
+	Funcs    int `json:"funcs"`
+	Methods  int `json:"methods"`
+	Exported int `json:"exported"`
+}
+
+// Stats returns counts of the package's consts, vars, type definitions,
+// functions, and methods, and the total number of exported symbols among them.
+//
+// Consts and vars are counted by name, and methods of interface types are counted as
+// part of their type. Methods are only counted as exported if their receiver
+// type is exported as well.
+func (p *Package) Stats() PackageStats {
+	var stats PackageStats
+
+	for _, cg := range p.Consts {
+		for _, c := range cg.Consts {
+			for _, name := range c.Names {
+				stats.Consts++
+
+				if isExportedIdent(name) {
+					stats.Exported++
+				}
+			}
+		}
+	}
+
+	for _, vg := range p.Vars {
+		for _, v := range vg.Vars {
+			for _, name := range v.Names {
+				stats.Vars++
+
+				if isExportedIdent(name) {
+					stats.Exported++
+				}
+			}
+		}
+	}
+
+	countMethod := func(m Func, recv string) {
+		stats.Methods++
+
+		if m.IsExported() && isExportedIdent(recv) {
+			stats.Exported++
+		}
+	}
+
+	for _, td := range p.Types {
+		stats.Types++
+
+		if td.IsExported() {
+			stats.Exported++
+		}
+
+		if td.Type == "interface" {
+			continue
+		}
+
+		for _, m := range td.Methods {
+			countMethod(m, td.Name)
+		}
+	}
+
+	for _, f := range p.Funcs {
+		if f.Receiver != nil {
+			countMethod(f, f.ReceiverType())
+			continue
+		}
+
+		stats.Funcs++
+
+		if f.IsExported() {
+			stats.Exported++
+		}
+	}
+
+	return stats
+}
+
+func (p *Package) flatFunc(f Func, name string, cfg printConfig) FlatSymbol {
+	sigF := f
+	sigF.Doc = ""
+
+	return p.flatSymbol(f, name, signature(func(w io.Writer) { sigF.print(w, cfg) }), f.Doc)
+}
+
+func (p *Package) flatSymbol(s Symbol, name, sig, doc string) FlatSymbol {
+	return FlatSymbol{
+		Kind:      symbolKinds[s.SymbolType()],
+		Name:      name,
+		Package:   p.Name,
+		Signature: sig,
+		Doc:       doc,
+	}
+}
+
+// signature returns the formatted code written by print, or the unformatted
+// code if it cannot be formatted.
+func signature(print func(io.Writer)) string {
+	var b strings.Builder
+
+	print(&b)
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return strings.TrimSpace(b.String())
+	}
+
+	return strings.TrimSpace(string(formatted))
+}
+
+// Walk traverses the package's symbols depth-first in the order they are
+// rendered, calling fn for each symbol: consts, vars, type definitions with their
+// fields and methods, and functions with their receivers, parameters, and
+// results. Type parameters and fields of inline struct and interface types are
+// visited as well.
+//
+// Walk stops the traversal if fn returns false.
+func (p *Package) Walk(fn func(Symbol) bool) {
+	for _, cg := range p.Consts {
+		for _, c := range cg.Consts {
+			if !fn(c) {
+				return
+			}
+		}
+	}
+
+	for _, vg := range p.Vars {
+		for _, v := range vg.Vars {
+			if !fn(v) {
+				return
+			}
+		}
+	}
+
+	for _, td := range p.Types {
+		if !walkTypeDef(td, fn) {
+			return
+		}
+	}
+
+	for _, f := range p.Funcs {
+		if !walkFunc(f, fn) {
+			return
+		}
+	}
+}
+
+func walkTypeDef(td TypeDef, fn func(Symbol) bool) bool {
+	if !fn(td) {
+		return false
+	}
+
+	for _, fields := range [][]Field{td.TypeParams, td.Fields, td.Params, td.Results} {
+		if !walkFields(fields, fn) {
+			return false
+		}
+	}
+
+	for _, m := range td.Methods {
+		if !walkFunc(m, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func walkFunc(f Func, fn func(Symbol) bool) bool {
+	if !fn(f) {
+		return false
+	}
+
+	if f.Receiver != nil && !walkField(*f.Receiver, fn) {
+		return false
+	}
+
+	return walkFields(f.TypeParams, fn) && walkFields(f.Params, fn) && walkFields(f.Results, fn)
+}
+
+func walkFields(fields []Field, fn func(Symbol) bool) bool {
+	for _, f := range fields {
+		if !walkField(f, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func walkField(f Field, fn func(Symbol) bool) bool {
+	if !fn(f) || !walkFields(f.Fields, fn) {
+		return false
+	}
+
+	for _, m := range f.Methods {
+		if !walkFunc(m, fn) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,290 @@
+package pkgdmp_test
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/michenriksen/pkgdmp"
+)
+
+func TestPackage_Symbols(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(defaultDocPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	symbols := make(map[string]pkgdmp.FlatSymbol)
+
+	for _, s := range pkg.Symbols() {
+		if _, ok := symbols[s.Name]; ok {
+			t.Errorf("expected symbol names to be unique, but %s appears more than once", s.Name)
+		}
+
+		symbols[s.Name] = s
+	}
+
+	tt := []pkgdmp.FlatSymbol{
+		{
+			Kind:      "const",
+			Name:      "MyUint32Const",
+			Package:   "mypackage",
+			Signature: `const MyStringConst, MyUint32Const, MyIntConst = "hello", uint32(123), 42`,
+			Doc:       "An ugly const declaration group to check that parser handles different scenarios correctly.",
+		},
+		{
+			Kind:      "const",
+			Name:      "MyError",
+			Package:   "mypackage",
+			Signature: "const MyError MyLogLevel = iota",
+			Doc:       "Check that parser handles this common const declaration method correctly.",
+		},
+		{
+			Kind:      "struct",
+			Name:      "MyStruct",
+			Package:   "mypackage",
+			Signature: "type MyStruct struct {\n\tExportedField                      int    `json:\"exported,omitempty\" xml:\"exported\"` // exported field.\n\tunexportedField                    string // unexported field.\n\tunexportedField1, unexportedField2 int    // unexported shorthand fields.\n}",
+			Doc:       "MyStruct is a struct with exported and unexported fields.",
+		},
+		{
+			Kind:      "method",
+			Name:      "MyStruct.MyMethod",
+			Package:   "mypackage",
+			Signature: "func (s MyStruct) MyMethod()",
+			Doc:       "MyMethod is a method associated with MyStruct.",
+		},
+		{
+			Kind:      "interface",
+			Name:      "MyInterface",
+			Package:   "mypackage",
+			Signature: "type MyInterface interface {\n\tMyMethod() error\n}",
+			Doc:       "MyInterface is an interface with a single method.",
+		},
+		{
+			Kind:      "func",
+			Name:      "NewMyStruct",
+			Package:   "mypackage",
+			Signature: "func NewMyStruct(n int) (*MyStruct, error)",
+			Doc:       "NewMyStruct is an example constructor function for [MyStruct]",
+		},
+	}
+
+	for _, want := range tt {
+		want := want
+
+		t.Run(want.Name, func(t *testing.T) {
+			actual, ok := symbols[want.Name]
+			if !ok {
+				t.Fatalf("expected symbol %s to be in symbol list", want.Name)
+			}
+
+			if actual != want {
+				t.Errorf("expected symbol:\n\n%#v\n\nbut got:\n\n%#v", want, actual)
+			}
+		})
+	}
+}
+
+func TestPackage_Symbols_Signatures(t *testing.T) {
+	pkg := parseSource(t, `package mypackage
+
+type Color int
+
+const (
+	Red Color = iota // Red color.
+	Green            // Green color.
+)
+
+var MyVar = "hello" // MyVar is a var.
+`)
+
+	var sigs []string
+
+	for _, s := range pkg.Symbols() {
+		if s.Kind == "const" || s.Kind == "var" {
+			sigs = append(sigs, s.Signature)
+		}
+	}
+
+	want := []string{
+		"const Red Color = iota",
+		"const Green Color = iota",
+		`var MyVar = "hello"`,
+	}
+
+	if !reflect.DeepEqual(sigs, want) {
+		t.Errorf("expected signatures %q, but got %q", want, sigs)
+	}
+}
+
+func TestPackage_Walk(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser(
+		pkgdmp.WithSymbolFilters(
+			pkgdmp.FilterMatchingIdents(pkgdmp.Include, regexp.MustCompile(`^(MySingleConst|MyStruct|MyMethod|NewMyStruct)$`)),
+		),
+	)
+
+	pkg, err := pkgParser.Package(defaultDocPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	want := []string{
+		"SymbolConst MySingleConst",
+		"SymbolStructType MyStruct",
+		"SymbolStructField ExportedField",
+		"SymbolStructField unexportedField",
+		"SymbolStructField unexportedField1",
+		"SymbolMethod MyMethod",
+		"SymbolReceiverField s",
+		"SymbolFunc NewMyStruct",
+		"SymbolParamField n",
+		"SymbolResultField ",
+		"SymbolResultField ",
+	}
+
+	t.Run("visits all symbols", func(t *testing.T) {
+		var actual []string
+
+		pkg.Walk(func(s pkgdmp.Symbol) bool {
+			actual = append(actual, fmt.Sprintf("%s %s", s.SymbolType(), s.Ident()))
+			return true
+		})
+
+		if !reflect.DeepEqual(actual, want) {
+			t.Errorf("expected visited symbols:\n\n%q\n\nbut got:\n\n%q", want, actual)
+		}
+	})
+
+	t.Run("stops when fn returns false", func(t *testing.T) {
+		var actual []string
+
+		pkg.Walk(func(s pkgdmp.Symbol) bool {
+			actual = append(actual, fmt.Sprintf("%s %s", s.SymbolType(), s.Ident()))
+			return s.SymbolType() != pkgdmp.SymbolStructField
+		})
+
+		if !reflect.DeepEqual(actual, want[:3]) {
+			t.Errorf("expected visited symbols:\n\n%q\n\nbut got:\n\n%q", want[:3], actual)
+		}
+	})
+}
+
+func TestPackage_GroupByKind(t *testing.T) {
+	pkgParser, _ := pkgdmp.NewParser()
+
+	pkg, err := pkgParser.Package(defaultDocPkg)
+	if err != nil {
+		t.Fatalf("expected no error when parsing package, but got: %v", err)
+	}
+
+	grouped := pkg.GroupByKind()
+
+	if grouped.Name != pkg.Name {
+		t.Errorf("expected grouped package name to be %q, but got %q", pkg.Name, grouped.Name)
+	}
+
+	want := map[string][]string{
+		"const":     {"MyStringConst", "MyFloatConst", "MyFloat32Const", "MyInitConst", "MySingleConst", "MyFatal", "MyError", "MyWarn", "MyInfo", "MyDebug"},
+		"identType": {"MyExportedType", "MyLogLevel", "myUnexportedType"},
+		"funcType":  {"MyFunctionType"},
+		"interface": {"MyInterface", "myUnexportedInterface"},
+		"struct":    {"MyStruct"},
+		"func":      {"MyThirdFunction", "NewMyStruct", "MyFunction", "MyOtherFunction", "myUnexportedFunction"},
+	}
+
+	actual := make(map[string][]string, len(grouped.Symbols))
+
+	for kind, symbols := range grouped.Symbols {
+		for _, s := range symbols {
+			actual[kind] = append(actual[kind], s.Ident())
+		}
+	}
+
+	if !reflect.DeepEqual(actual, want) {
+		t.Errorf("expected grouped symbols:\n\n%v\n\nbut got:\n\n%v", want, actual)
+	}
+}
+
+func TestPackage_Stats(t *testing.T) {
+	pkg := &pkgdmp.Package{
+		Name: "mypackage",
+		Consts: []pkgdmp.ConstGroup{
+			{Consts: []pkgdmp.Const{{Names: []string{"MyConst", "myConst"}}}},
+		},
+		Vars: []pkgdmp.VarGroup{
+			{Vars: []pkgdmp.Var{{Names: []string{"MyVar"}}, {Names: []string{"myVar"}}}},
+		},
+		Types: []pkgdmp.TypeDef{
+			{
+				Name:    "MyStruct",
+				Type:    "struct",
+				Methods: []pkgdmp.Func{{Name: "MyMethod"}, {Name: "myMethod"}},
+			},
+			{
+				Name:    "MyInterface",
+				Type:    "interface",
+				Methods: []pkgdmp.Func{{Name: "MyMethod"}},
+			},
+			{Name: "myType", Type: "int"},
+		},
+		Funcs: []pkgdmp.Func{
+			{Name: "MyFunction"},
+			{Name: "myFunction"},
+			{Name: "MyMethod", Receiver: &pkgdmp.Field{Names: []string{"t"}, Type: "*myType"}},
+		},
+	}
+
+	want := pkgdmp.PackageStats{
+		Consts:   2,
+		Vars:     2,
+		Types:    3,
+		Funcs:    2,
+		Methods:  3,
+		Exported: 6,
+	}
+
+	if actual := pkg.Stats(); actual != want {
+		t.Errorf("expected stats %+v, but got %+v", want, actual)
+	}
+}
+
+func TestPackage_IsEmpty(t *testing.T) {
+	src := &parserTestCase{sourceFile: "unexported_only.go"}
+
+	tt := []struct {
+		name string
+		opts []pkgdmp.ParserOption
+		want bool
+	}{
+		{"without filters", nil, false},
+		{
+			"with unexported symbols excluded",
+			[]pkgdmp.ParserOption{pkgdmp.WithSymbolFilters(pkgdmp.FilterUnexported(pkgdmp.Exclude))},
+			true,
+		},
+		{
+			"with only vars included",
+			[]pkgdmp.ParserOption{pkgdmp.WithSymbolFilters(pkgdmp.FilterSymbolTypes(pkgdmp.Include, pkgdmp.SymbolVar))},
+			false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			pkgParser, _ := pkgdmp.NewParser(tc.opts...)
+
+			pkg, err := pkgParser.Package(src.pkgDoc(t))
+			if err != nil {
+				t.Fatalf("expected no error when parsing package, but got: %v", err)
+			}
+
+			if actual := pkg.IsEmpty(); actual != tc.want {
+				t.Errorf("expected IsEmpty to return %t, but got %t", tc.want, actual)
+			}
+		})
+	}
+}
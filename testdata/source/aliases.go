@@ -0,0 +1,20 @@
+package mypackage
+
+// Byte is an alias for byte.
+type Byte = byte
+
+// MyCounts is an alias for a map type.
+type MyCounts = map[string]int
+
+// MyPoint is an alias for an anonymous struct type.
+type MyPoint = struct {
+	X, Y int
+}
+
+// MyID is a type definition, not an alias.
+type MyID string
+
+// String returns the ID as a string.
+func (id MyID) String() string {
+	return string(id)
+}
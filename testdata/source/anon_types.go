@@ -0,0 +1,19 @@
+package mypackage
+
+// MyOptions is a struct with an inline anonymous struct field.
+type MyOptions struct {
+	// Server contains server options.
+	Server struct {
+		Host string `json:"host"` // server host.
+		Port int    `json:"port"` // server port.
+	} `json:"server"`
+	Hooks []*struct {
+		Name string
+	}
+}
+
+// MyConfigure takes inline anonymous struct and interface parameters.
+func MyConfigure(opts struct{ A int }, logger interface {
+	// Log logs a message.
+	Log(msg string) error
+}) struct{ OK bool }
@@ -0,0 +1,23 @@
+package mypackage
+
+// #include <stdlib.h>
+import "C"
+
+import "unsafe"
+
+// _Ctype_int is a cgo pseudo-type as found in cgo generated code.
+type _Ctype_int int32
+
+// _Cfunc_free is a cgo pseudo-function as found in cgo generated code.
+func _Cfunc_free(p unsafe.Pointer) {}
+
+// _cgo_runtime_cgocall is a cgo runtime hook as found in cgo generated code.
+var _cgo_runtime_cgocall func(unsafe.Pointer, uintptr) int32
+
+// MyCgoSize is the size of a C int in bytes.
+const MyCgoSize = C.sizeof_int
+
+// MyCgoFunc is an exported function using cgo types.
+func MyCgoFunc(n C.int) C.int {
+	return n
+}
@@ -0,0 +1,19 @@
+package mypackage
+
+// Character constants.
+const (
+	Letter         = 'a'        // char literal.
+	Tab            = '\t'       // escaped char literal.
+	Quote          = '\''       // escaped quote literal.
+	Accent         = 'é'        // non-ASCII char literal.
+	Unicode        = '\u00e9'   // unicode escaped char literal.
+	Byte           = byte('A')  // byte conversion.
+	EscByte        = byte('\n') // escaped byte conversion.
+	TypedRune rune = 'z'        // typed char literal.
+)
+
+// Newline is a single byte conversion const.
+const Newline = byte('\n')
+
+// Space is another single byte conversion const.
+const Space = byte(' ')
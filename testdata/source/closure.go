@@ -0,0 +1,63 @@
+package mypackage
+
+// MyServer is an example server referring to other types of the package.
+type MyServer struct {
+	Config   *MyConfig
+	Handlers map[string]MyHandler
+	logger   myLogger
+}
+
+// NewMyServer returns a new server.
+func NewMyServer(cfg *MyConfig) *MyServer {
+	return &MyServer{Config: cfg}
+}
+
+// Serve serves a request.
+func (s *MyServer) Serve(r MyRequest) (*MyResponse, error) {
+	return nil, nil
+}
+
+// MyConfig refers to itself and to [MyTLS].
+type MyConfig struct {
+	Parent *MyConfig
+	TLS    MyTLS
+}
+
+// MyTLS refers back to [MyServer].
+type MyTLS struct {
+	Cert   []byte
+	server *MyServer
+}
+
+// MyHandler is a handler function type.
+type MyHandler func(MyRequest) error
+
+// MyRequest is an example request.
+type MyRequest struct {
+	Path string
+}
+
+// MyResponse is an example response.
+type MyResponse struct {
+	Status int
+}
+
+// myLogger is an unexported interface.
+type myLogger interface {
+	Log(level MyLevel, msg string)
+}
+
+// MyLevel is a log level.
+type MyLevel int
+
+// MyUnrelated is not referred to by [MyServer].
+type MyUnrelated struct {
+	// MyRequest is a field with the same name as a type.
+	MyRequest string
+	Anon      struct{ MyResponse int }
+}
+
+// MyHelper refers to [MyUnrelated].
+func MyHelper(u MyUnrelated) string {
+	return ""
+}
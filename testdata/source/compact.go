@@ -0,0 +1,21 @@
+package mypackage
+
+import "io"
+
+// MyHandlers is a struct with func fields and embedded fields.
+type MyHandlers struct {
+	io.Reader
+	*MyHandlers
+
+	// OnEvent is called for each event.
+	OnEvent func(name string) error // event handler.
+	OnClose func()
+}
+
+// MyReadCloser embeds other interfaces.
+type MyReadCloser interface {
+	io.Reader
+
+	// Close closes the reader.
+	Close() error
+}
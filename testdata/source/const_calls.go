@@ -0,0 +1,23 @@
+package mypackage
+
+import (
+	"time"
+	"unsafe"
+)
+
+// Conversions checks that parser handles conversion const values.
+const (
+	MyDuration   = time.Duration(0)
+	MyTimeout    = time.Duration(30) * time.Second
+	MyNestedConv = uint8(int8(-1) + 1)
+)
+
+// Builtins checks that parser handles builtin function call const values.
+const (
+	MyImaginary               = complex(0, 1)
+	MyTypedComplex  complex64 = complex(1, 2)
+	MyRealPart                = real(3 + 4i)
+	MyImaginaryPart           = imag(MyImaginary)
+	MyLength                  = len("hello")
+	MySize                    = unsafe.Sizeof(MyLength)
+)
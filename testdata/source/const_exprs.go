@@ -0,0 +1,30 @@
+package mypackage
+
+// Byte sizes checks that parser handles bit-shift const expressions.
+const (
+	KB = 1 << 10
+	MB = 1 << 20
+	GB = 1 << (10 * 3)
+)
+
+// Arithmetic checks that parser handles arithmetic const expressions.
+const (
+	MyNegative  = -1
+	MySum       = 1 + 2.5
+	MyProduct   = (2 * 3) + 'a'
+	MyComplex   = 2i * 2
+	MyCompare   = 1 < 2
+	MyNot       = !false
+	MyConcat    = "hello" + " " + "world"
+	MyReference = KB * 4
+)
+
+// MyFlag is a typed bit flag.
+type MyFlag uint8
+
+// Bit flags checks that parser handles iota shift expressions.
+const (
+	MyFlagA MyFlag = 1 << iota
+	MyFlagB
+	MyFlagC
+)
@@ -0,0 +1,23 @@
+package mypackage
+
+// MyStruct is a struct with directives in field comments.
+type MyStruct struct {
+	ID   int    // nolint:revive // unique identifier.
+	Name string // name of the struct //nolint:lll
+}
+
+// MyFunction is a function with a nolint directive in its doc comment.
+//
+// It does nothing.
+// nolint:gocyclo // complex by design.
+func MyFunction() {}
+
+// MyDeprecatedFunction is a function with a lint:ignore directive.
+//
+// lint:ignore SA1019 kept for compatibility.
+func MyDeprecatedFunction() {}
+
+// MyInlineFunction is a function with a compiler directive.
+//
+//go:noinline
+func MyInlineFunction() {}
@@ -0,0 +1,21 @@
+package mypackage
+
+// MyCopy copies bytes from src to dst.
+//
+// Params:
+//   - dst - The destination writer.
+//   - src - The source reader, which is read
+//     until EOF.
+//
+// Return values:
+// The number of bytes copied and any error.
+//
+// MyCopy does not close dst or src.
+func MyCopy(dst, src any) (int, error) {
+	return 0, nil
+}
+
+// MyPlainFunction has a doc comment without sections.
+//
+// Returns are not described here.
+func MyPlainFunction() {}
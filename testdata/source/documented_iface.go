@@ -0,0 +1,22 @@
+package mypackage
+
+// MyCache is an interface with many documented methods.
+type MyCache interface {
+	// Set stores value under key. Existing values are replaced.
+	Set(key string, value []byte) error
+
+	// Get returns the value stored under key.
+	//
+	// An error is returned if key does not exist in the cache.
+	Get(key string) ([]byte, error)
+
+	// Delete removes the value stored under key, if any.
+	Delete(key string) error
+
+	Keys() []string // returns all keys in the cache.
+
+	// Clear removes all values from the cache.
+	Clear()
+
+	Len() int
+}
@@ -0,0 +1,25 @@
+package mypackage
+
+import (
+	"embed"
+	_ "embed"
+)
+
+// Version is the version of the package.
+//
+//go:embed version.txt
+var Version string
+
+//go:embed logo.png
+var logo []byte
+
+// Embedded files.
+var (
+	// Templates contains the HTML templates.
+	//go:embed templates/*.html
+	//go:embed templates/partials/*.html
+	Templates embed.FS
+
+	//go:embed "static files"
+	Static embed.FS
+)
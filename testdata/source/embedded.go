@@ -0,0 +1,29 @@
+package mypackage
+
+import "io"
+
+// MyBase is a struct embedded in other structs.
+type MyBase struct {
+	ID        int    `json:"id"` // unique identifier.
+	Name      string `json:"name"`
+	createdAt int64
+}
+
+// myTimestamps is an unexported struct embedded in other structs.
+type myTimestamps struct {
+	CreatedAt, UpdatedAt int64
+}
+
+// MyEntity is a struct embedding other structs.
+type MyEntity struct {
+	*MyBase
+	myTimestamps
+	io.Reader
+	Name string // entity name, shadowing MyBase.Name.
+}
+
+// MyRecord is a struct embedding a struct with embedded structs.
+type MyRecord struct {
+	MyEntity
+	Version int
+}
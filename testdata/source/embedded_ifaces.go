@@ -0,0 +1,32 @@
+package mypackage
+
+// Reader is an interface embedded in other interfaces.
+type Reader interface {
+	// Read reads up to len(p) bytes into p.
+	Read(p []byte) (n int, err error)
+}
+
+// Writer is an interface embedded in other interfaces.
+type Writer interface {
+	Write(p []byte) (n int, err error) // writes len(p) bytes from p.
+}
+
+// closer is an unexported interface embedded in other interfaces.
+type closer interface {
+	Close() error
+}
+
+// ReadCloser is an interface embedding other interfaces.
+type ReadCloser interface {
+	Reader
+	closer
+}
+
+// ReadWriteCloser is an interface embedding an interface that embeds other
+// interfaces.
+type ReadWriteCloser interface {
+	ReadCloser
+	Writer
+	// Close closes the underlying stream, shadowing closer.Close.
+	Close() error
+}
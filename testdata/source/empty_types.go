@@ -0,0 +1,34 @@
+package mypackage
+
+// Empty is declared without fields.
+type Empty struct{}
+
+// Hidden has only unexported fields.
+type Hidden struct {
+	name string
+	age  int
+}
+
+// HiddenWithMethods has only unexported fields and an exported method.
+type HiddenWithMethods struct {
+	name string
+}
+
+// Name returns the name.
+func (h HiddenWithMethods) Name() string {
+	return h.name
+}
+
+// Visible has an exported field.
+type Visible struct {
+	Name string
+	age  int
+}
+
+// Any is declared without methods.
+type Any interface{}
+
+// Embedding only embeds other interfaces.
+type Embedding interface {
+	Any
+}
@@ -0,0 +1,39 @@
+package mypackage
+
+// Color is a color.
+type Color int
+
+// Supported colors.
+const (
+	unknownColor Color = iota // Unknown color.
+
+	// Red is the color of blood.
+	Red
+	Green // Green is the color of grass.
+	Blue
+)
+
+// MyFlag is a bit flag.
+type MyFlag uint8
+
+// Supported flags.
+const (
+	MyFlagA MyFlag = 1 << iota // First flag.
+	MyFlagB                    // Second flag.
+	MyFlagC                    // Third flag.
+)
+
+// MyWeekday is a day of the week.
+type MyWeekday int
+
+// Untyped iota consts are not enums.
+const (
+	MyMonday = iota
+	MyTuesday
+)
+
+// Typed consts with explicit values are not enums.
+const (
+	MySaturday MyWeekday = 6
+	MySunday   MyWeekday = 7
+)
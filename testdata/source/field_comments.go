@@ -0,0 +1,16 @@
+package mypackage
+
+// Config is a struct with trailing field comments of varying lengths.
+type Config struct {
+	Name    string // Name of the configuration.
+	Timeout int    // Timeout in seconds, see https://example.com/docs//timeouts.
+	Verbose bool   /* Verbose enables
+	verbose logging. */
+	Tags []string // Tags.
+}
+
+// Client is a client for a service.
+type Client interface {
+	Do(req string) error /* Do sends a request
+	and waits for the response. */
+}
@@ -0,0 +1,31 @@
+package mypackage
+
+// MyErrFunc is a function type returning a function returning an error.
+type MyErrFunc func() func() error
+
+// MyCurryFunc is a function type returning multiple levels of functions.
+type MyCurryFunc func(int) func(string) (int, error)
+
+// MyAdder returns a function adding x to its argument.
+func MyAdder(x int) func(int) int { return nil }
+
+// MyErrFuncFactory returns a function returning an error.
+func MyErrFuncFactory() func() error { return nil }
+
+// MyNamedFuncResult returns a named function result and an error.
+func MyNamedFuncResult() (f func(int) int, err error) { return nil, nil }
+
+// MySingleNamedFuncResult returns a single named function result.
+func MySingleNamedFuncResult() (f func() error) { return nil }
+
+// MySingleNamedResult returns a single named result.
+func MySingleNamedResult() (err error) { return nil }
+
+// MyFuncResults returns multiple unnamed function results.
+func MyFuncResults() (func(), func() (int, error)) { return nil, nil }
+
+// MyCurry returns multiple levels of functions.
+func MyCurry(x int) func(int) func(string) (int, error) { return nil }
+
+// MyFuncParam takes a function returning a function.
+func MyFuncParam(fn func() func() error) {}
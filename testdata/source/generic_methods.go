@@ -0,0 +1,30 @@
+package mypackage
+
+// MyStack is a generic stack.
+type MyStack[T any] struct {
+	items []T
+}
+
+// Push pushes v onto the stack.
+func (s *MyStack[T]) Push(v T) {}
+
+// Peek returns the top item of the stack.
+func (s MyStack[T]) Peek() (T, bool) {
+	var zero T
+	return zero, false
+}
+
+// MyPair is a generic pair.
+type MyPair[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// Set sets the key and value of the pair.
+func (p *MyPair[K, V]) Set(k K, v V) {}
+
+// Value returns the value of the pair.
+func (*MyPair[_, V]) Value() V {
+	var zero V
+	return zero
+}
@@ -0,0 +1,25 @@
+package mypackage
+
+// MyBox is a generic struct with a single type parameter.
+type MyBox[T any] struct {
+	Value T
+}
+
+// MyPair is a generic struct with multiple type parameters.
+type MyPair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// MyContainer is a struct with fields of instantiated generic types.
+type MyContainer struct {
+	Box   MyBox[int]
+	Pairs []MyPair[string, *MyBox[int]]
+	Index map[string][]*MyBox[int]
+}
+
+// MyLookup takes and returns instantiated generic types.
+func MyLookup(pairs []MyPair[string, int], key MyBox[string]) map[string][]*MyBox[int]
+
+// MyMap is a generic function with type parameters.
+func MyMap[T, U any](items []T, fn func(T) U) []U
@@ -0,0 +1,17 @@
+package mypackage
+
+import "io"
+
+// MyStore is an interface with documented methods.
+type MyStore interface {
+	// Get returns the value stored under key, or an error if key does not
+	// exist in the store.
+	Get(key string) (string, error)
+
+	// Set stores value under key.
+	Set(key, value string) error
+
+	Len() int // Len returns the number of stored values.
+
+	io.Closer
+}
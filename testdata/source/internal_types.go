@@ -0,0 +1,42 @@
+package mypackage
+
+import "net/http"
+
+// options configures a client.
+type options struct {
+	timeout int
+}
+
+// handlerFunc handles requests.
+type handlerFunc func(http.ResponseWriter, *http.Request)
+
+type state int
+
+// Option configures a client.
+type Option func(*options)
+
+// Registry maps names to handlers.
+type Registry map[string]handlerFunc
+
+// States is a list of states.
+type States []state
+
+// Client is an example client.
+type Client struct {
+	// Handler handles requests.
+	Handler handlerFunc
+	Options []*options
+	States  map[state]bool
+	Status  http.ConnState
+	opts    options
+}
+
+// NewClient creates a new client.
+func NewClient(opts ...Option) *Client {
+	return nil
+}
+
+// Handle registers a handler for a pattern.
+func (c *Client) Handle(pattern string, h handlerFunc) (state, error) {
+	return 0, nil
+}
@@ -0,0 +1,37 @@
+package mypackage
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// MyClient is an example client.
+type MyClient struct{}
+
+// MyShortFunction has a signature that fits within the wrap width.
+func MyShortFunction(a int) error {
+	return nil
+}
+
+// MyLongFunction has six parameters and a signature longer than the wrap width.
+func MyLongFunction(ctx context.Context, name string, r io.Reader, w io.Writer, timeout time.Duration, retries int) (int, error) {
+	return 0, nil
+}
+
+// MyGroupedFunction has grouped parameter names.
+func MyGroupedFunction(firstName, lastName string, age, height int, createdAt, updatedAt time.Time) error {
+	return nil
+}
+
+// Do is a method with a signature longer than the wrap width.
+func (c *MyClient) Do(ctx context.Context, method, url string, body io.Reader, headers map[string]string) error {
+	return nil
+}
+
+// MyStore is an interface with a method signature longer than the wrap width.
+type MyStore interface {
+	// Put stores a value.
+	Put(ctx context.Context, key string, value io.Reader, ttl time.Duration, tags map[string]string) error
+	Get(ctx context.Context, key string) (io.Reader, error) // Get returns a stored value.
+}
@@ -0,0 +1,31 @@
+package mypackage
+
+// MyStore is an interface with many methods.
+type MyStore interface {
+	// Get returns the value for key.
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+	Close() error
+}
+
+// MyCache is a struct with many methods.
+type MyCache struct{}
+
+// Get returns the value for key.
+func (c *MyCache) Get(key string) (string, error) { return "", nil }
+
+// Set sets the value for key.
+func (c *MyCache) Set(key, value string) error { return nil }
+
+// Delete deletes the value for key.
+func (c *MyCache) Delete(key string) error { return nil }
+
+// MyFlags is an integer type with methods.
+type MyFlags int
+
+// Has returns true if flag is set.
+func (f MyFlags) Has(flag MyFlags) bool { return f&flag != 0 }
+
+// String returns the flags as a string.
+func (f MyFlags) String() string { return "" }
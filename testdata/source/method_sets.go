@@ -0,0 +1,59 @@
+package mypackage
+
+import "io"
+
+// MyFileStore stores values in files.
+type MyFileStore struct{}
+
+// Get returns the value for key.
+func (s *MyFileStore) Get(key string) ([]byte, error) {
+	return nil, nil
+}
+
+// Put stores value for key.
+func (s *MyFileStore) Put(key string, value []byte) error {
+	return nil
+}
+
+// Delete deletes the value for key.
+func (s *MyFileStore) Delete(key string) error {
+	return nil
+}
+
+// Sync flushes stored values to disk.
+func (s *MyFileStore) Sync() error {
+	return nil
+}
+
+// Close closes the store.
+func (s *MyFileStore) Close() error {
+	return nil
+}
+
+// MyMemStore stores values in memory.
+type MyMemStore struct{}
+
+// Get returns the value for k.
+func (m MyMemStore) Get(k string) (v []byte, err error) {
+	return nil, nil
+}
+
+// Put stores v for k.
+func (m MyMemStore) Put(k string, v []byte) error {
+	return nil
+}
+
+// Delete deletes the values for keys.
+func (m MyMemStore) Delete(keys ...string) error {
+	return nil
+}
+
+// Snapshot writes stored values to w.
+func (m MyMemStore) Snapshot(w io.Writer) error {
+	return nil
+}
+
+// MyCloser can be closed.
+type MyCloser interface {
+	Close() error
+}
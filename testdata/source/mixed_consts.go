@@ -0,0 +1,18 @@
+package mypackage
+
+// MyMixedKind is a custom type for typed consts.
+type MyMixedKind uint8
+
+// Mixed typed and untyped consts to check that structured values agree with
+// the rendered declarations.
+const (
+	MyUntyped               = "x"
+	MyTyped     int         = 2
+	MyConverted             = uint8(1 << 2)
+	MyIota                  = iota
+	MyBool                  = true
+	MyAlias                 = MyTyped
+	MyTypedIota MyMixedKind = iota + 1
+	MyImplicit
+	MyPair, MyOtherPair int = 3, 4
+)
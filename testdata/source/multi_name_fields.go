@@ -0,0 +1,8 @@
+package mypackage
+
+// MyPoint is a struct with multi-name fields sharing tags.
+type MyPoint struct {
+	X, Y, Z     float64 `json:"coord,omitempty" xml:"coord"` // coordinates.
+	Name, Label string  `json:"name"`
+	a, b        bool    // unexported shorthand fields.
+}
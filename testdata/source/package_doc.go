@@ -0,0 +1,27 @@
+// Package mypackage is an example package with a multi-paragraph package
+// doc comment.
+//
+// The second paragraph of the package doc comment describes the package in
+// more detail, and is only included with full package docs.
+//
+// # Usage
+//
+// Call [MyFunction] to do something:
+//
+//	mypackage.MyFunction()
+package mypackage
+
+// MyFunction is an example function with a multi-paragraph doc comment.
+//
+// The second paragraph is only included with full docs.
+func MyFunction() {}
+
+// MyStruct is an example struct with a multi-paragraph doc comment.
+//
+// The second paragraph is only included with full docs.
+type MyStruct struct {
+	// Name is a field with a multi-paragraph doc comment.
+	//
+	// The second paragraph is only included with full docs.
+	Name string
+}
@@ -0,0 +1,40 @@
+package mypackage
+
+import (
+	"context"
+	stdlog "log"
+	"net/http"
+
+	renamed "github.com/example/logging"
+	"github.com/example/project/v2/store"
+	"github.com/mattn/go-sqlite3"
+	"gopkg.in/yaml.v3"
+)
+
+// MyClient is a client for a remote store.
+type MyClient struct {
+	HTTP   *http.Client
+	Store  store.Store
+	Logger renamed.Logger
+	Nodes  map[string][]*yaml.Node
+	Log    *stdlog.Logger
+	Conn   *sqlite3.SQLiteConn
+}
+
+// MyHandler handles store events.
+type MyHandler func(ctx context.Context, ev store.Event) error
+
+// MyItems is a list of store items.
+type MyItems []store.Item
+
+// DefaultStore is the default store.
+var DefaultStore store.Store
+
+// NewMyClient returns a new client for store s.
+func NewMyClient(s store.Store, opts ...store.Option) (*MyClient, error)
+
+// Get returns the item stored with key.
+func (c *MyClient) Get(ctx context.Context, key store.Key) (*store.Item, error)
+
+// Decode decodes node into the client's configuration.
+func (c *MyClient) Decode(node *yaml.Node) error
@@ -0,0 +1,12 @@
+package mypackage
+
+import (
+	"net/http"
+	"time"
+)
+
+// MyClient re-exports [http.Client].
+type MyClient = http.Client
+
+// MyDuration is a type defined from [time.Duration].
+type MyDuration time.Duration
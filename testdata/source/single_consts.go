@@ -0,0 +1,18 @@
+package mypackage
+
+// MyHost is the default host.
+const MyHost = "localhost"
+
+// MyScheme is the default scheme.
+const MyScheme = "https"
+
+const MyPort = 8080
+
+// MyTimeout is the default timeout in seconds.
+const MyTimeout = 30
+
+// MyRatio is the default ratio.
+const MyRatio = 0.5
+
+// MyRetries is the default number of retries.
+const MyRetries uint8 = 3
@@ -0,0 +1,27 @@
+package mypackage
+
+// MyCelsius is a temperature in degrees Celsius.
+type MyCelsius float64
+
+// MyA is defined in terms of MyB.
+type MyA MyB
+
+// MyB is defined in terms of MyC.
+type MyB MyC
+
+// MyC is an integer type.
+type MyC int
+
+// MyNames is defined in terms of a slice type.
+type MyNames MyList
+
+// MyList is a list of strings.
+type MyList []string
+
+// MyPoint is a struct type.
+type MyPoint struct {
+	X, Y int
+}
+
+// MyPosition is defined in terms of a struct type.
+type MyPosition MyPoint
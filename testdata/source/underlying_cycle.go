@@ -0,0 +1,10 @@
+package mypackage
+
+// MyPing is defined in terms of MyPong, which is defined in terms of MyPing.
+type MyPing MyPong
+
+// MyPong is defined in terms of MyPing.
+type MyPong MyPing
+
+// MyPingPong is defined in terms of a cyclic type.
+type MyPingPong MyPing
@@ -0,0 +1,13 @@
+package mypackage
+
+const defaultName = "default"
+
+var registry = map[string]myStruct{}
+
+type myStruct struct {
+	name string
+}
+
+func newMyStruct(name string) *myStruct {
+	return &myStruct{name: name}
+}
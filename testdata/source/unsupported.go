@@ -0,0 +1,26 @@
+package mypackage
+
+import "io"
+
+// MyPointer is a pointer type, which is not supported.
+type MyPointer *int
+
+// MyReadCloser is an interface with an embedded interface.
+type MyReadCloser interface {
+	io.Reader
+
+	// Close closes the reader.
+	Close() error
+}
+
+// MyNumber is a constraint with a type union, which is not supported.
+type MyNumber interface {
+	~int | ~float64
+}
+
+// MyOptions is a struct with an inline interface.
+type MyOptions struct {
+	Logger interface {
+		io.Writer
+	}
+}
@@ -0,0 +1,15 @@
+package mypackage
+
+import "os"
+
+// MySelectorConst checks that parser handles selector const values.
+const MySelectorConst = os.ModePerm
+
+// MyCallConst checks that parser does not panic on zero-argument calls.
+const MyCallConst = someFunc()
+
+// MyIndexConst checks that parser handles index expression const values.
+const MyIndexConst = "hello"[0]
+
+// MySizeConst checks that parser handles calls with non-literal arguments.
+const MySizeConst = len(MySelectorConst.String())
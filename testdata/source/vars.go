@@ -0,0 +1,29 @@
+package mypackage
+
+import "errors"
+
+// ErrNotFound is returned when something is not found.
+var ErrNotFound = errors.New("not found")
+
+// Default settings.
+var (
+	// DefaultName is the default name.
+	DefaultName = "default"
+	// DefaultSize is the default size.
+	DefaultSize int = 42
+
+	defaultTimeout = 30 // In seconds.
+)
+
+// MaxRetries is the maximum number of retries.
+var MaxRetries, MinRetries int
+
+var internalCounter int // Incremented on each call.
+
+// MyStruct is a struct.
+type MyStruct struct {
+	Name string
+}
+
+// DefaultStruct is the default struct.
+var DefaultStruct = &MyStruct{Name: "default"}
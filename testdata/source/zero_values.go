@@ -0,0 +1,26 @@
+package mypackage
+
+import (
+	"io"
+	"time"
+)
+
+// MyConfig is a configuration struct with fields of different types.
+type MyConfig struct {
+	Name       string // name of the service.
+	Port       int    `json:"port"`
+	Ratio      float64
+	Debug      bool
+	Tags       []string
+	Labels     map[string]string
+	Parent     *MyConfig
+	Timeout    time.Duration // named types are not annotated.
+	Output     io.Writer
+	Err        error
+	Done       chan struct{}
+	OnClose    func() error
+	Checksum   [4]byte
+	Anything   any
+	MinX, MaxX int32
+	io.Reader
+}